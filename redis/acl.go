@@ -0,0 +1,282 @@
+package redis
+
+import (
+	"errors"
+	"strings"
+)
+
+//* ACL
+
+// ACLRules builds the rules of an ACL SETUSER command. Rules are applied in order.
+//
+//	r := redis.NewACLRules().Reset().On().Password("secret").
+//		Keys("app:*").AllowCategories("read").AllowCommands("set")
+//	err := c.ACLSetUser("app", r)
+type ACLRules struct {
+	rules []interface{}
+}
+
+// NewACLRules returns an empty ACLRules.
+func NewACLRules() *ACLRules {
+	return &ACLRules{}
+}
+
+func (r *ACLRules) add(prefix string, values []string) *ACLRules {
+	for _, v := range values {
+		r.rules = append(r.rules, prefix+v)
+	}
+	return r
+}
+
+// Rule adds raw rules, in the ACL rule syntax.
+func (r *ACLRules) Rule(rules ...string) *ACLRules {
+	return r.add("", rules)
+}
+
+// Reset resets the user to a new user: off, without passwords, commands, keys and
+// channels.
+func (r *ACLRules) Reset() *ACLRules {
+	return r.Rule("reset")
+}
+
+// On enables the user.
+func (r *ACLRules) On() *ACLRules {
+	return r.Rule("on")
+}
+
+// Off disables the user. Already authenticated connections keep working.
+func (r *ACLRules) Off() *ACLRules {
+	return r.Rule("off")
+}
+
+// NoPass allows any password for the user.
+func (r *ACLRules) NoPass() *ACLRules {
+	return r.Rule("nopass")
+}
+
+// ResetPass removes all passwords of the user, and nopass.
+func (r *ACLRules) ResetPass() *ACLRules {
+	return r.Rule("resetpass")
+}
+
+// Password adds the given passwords to the user.
+func (r *ACLRules) Password(passwords ...string) *ACLRules {
+	return r.add(">", passwords)
+}
+
+// HashedPassword adds the given passwords to the user, as hex encoded SHA-256 hashes.
+func (r *ACLRules) HashedPassword(hashes ...string) *ACLRules {
+	return r.add("#", hashes)
+}
+
+// RemovePassword removes the given passwords from the user.
+func (r *ACLRules) RemovePassword(passwords ...string) *ACLRules {
+	return r.add("<", passwords)
+}
+
+// AllowCommands allows the given commands, or subcommands as "config|get".
+func (r *ACLRules) AllowCommands(cmds ...string) *ACLRules {
+	return r.add("+", cmds)
+}
+
+// DenyCommands denies the given commands, or subcommands as "config|set".
+func (r *ACLRules) DenyCommands(cmds ...string) *ACLRules {
+	return r.add("-", cmds)
+}
+
+// AllowCategories allows the commands of the given categories, see ACLCat.
+func (r *ACLRules) AllowCategories(categories ...string) *ACLRules {
+	return r.add("+@", categories)
+}
+
+// DenyCategories denies the commands of the given categories, see ACLCat.
+func (r *ACLRules) DenyCategories(categories ...string) *ACLRules {
+	return r.add("-@", categories)
+}
+
+// AllCommands allows all commands, including future ones.
+func (r *ACLRules) AllCommands() *ACLRules {
+	return r.Rule("allcommands")
+}
+
+// NoCommands denies all commands.
+func (r *ACLRules) NoCommands() *ACLRules {
+	return r.Rule("nocommands")
+}
+
+// Keys allows reading and writing the keys matching the given glob-style patterns.
+func (r *ACLRules) Keys(patterns ...string) *ACLRules {
+	return r.add("~", patterns)
+}
+
+// ReadKeys allows reading the keys matching the given patterns (Redis 7.0 or later).
+func (r *ACLRules) ReadKeys(patterns ...string) *ACLRules {
+	return r.add("%R~", patterns)
+}
+
+// WriteKeys allows writing the keys matching the given patterns (Redis 7.0 or later).
+func (r *ACLRules) WriteKeys(patterns ...string) *ACLRules {
+	return r.add("%W~", patterns)
+}
+
+// AllKeys allows all keys.
+func (r *ACLRules) AllKeys() *ACLRules {
+	return r.Rule("allkeys")
+}
+
+// ResetKeys removes all key patterns.
+func (r *ACLRules) ResetKeys() *ACLRules {
+	return r.Rule("resetkeys")
+}
+
+// Channels allows the Pub/Sub channels matching the given glob-style patterns.
+func (r *ACLRules) Channels(patterns ...string) *ACLRules {
+	return r.add("&", patterns)
+}
+
+// AllChannels allows all Pub/Sub channels.
+func (r *ACLRules) AllChannels() *ACLRules {
+	return r.Rule("allchannels")
+}
+
+// ResetChannels removes all channel patterns.
+func (r *ACLRules) ResetChannels() *ACLRules {
+	return r.Rule("resetchannels")
+}
+
+// ACLUser is a user, as returned by ACL GETUSER.
+type ACLUser struct {
+	Flags     []string // "on" or "off", "nopass"...
+	Passwords []string // SHA-256 hashes
+	Commands  string   // Command rules, such as "+@all -debug"
+	Keys      string   // Key rules, such as "~app:* %R~shared:*"
+	Channels  string   // Channel rules, such as "&*" (Redis 7.0 or later)
+	Selectors []ACLSelector
+}
+
+// ACLSelector is an additional set of permissions of a user (Redis 7.0 or later).
+type ACLSelector struct {
+	Commands string
+	Keys     string
+	Channels string
+}
+
+// ACLWhoAmI returns the user of the connection.
+func (c *Client) ACLWhoAmI() (string, error) {
+	return c.Cmd("acl", "whoami").Str()
+}
+
+// ACLList returns the users, as ACL rules in the format of ACL files.
+func (c *Client) ACLList() ([]string, error) {
+	return c.Cmd("acl", "list").List()
+}
+
+// ACLUsers returns the usernames.
+func (c *Client) ACLUsers() ([]string, error) {
+	return c.Cmd("acl", "users").List()
+}
+
+// ACLGetUser returns the given user, or false if it does not exist.
+func (c *Client) ACLGetUser(name string) (*ACLUser, bool, error) {
+	r := c.Cmd("acl", "getuser", name)
+	if r.Type == NilReply {
+		return nil, false, nil
+	}
+	m, err := r.pairs()
+	if err != nil {
+		return nil, false, err
+	}
+	u := &ACLUser{}
+	if u.Flags, err = optList(m["flags"]); err != nil {
+		return nil, false, err
+	}
+	if u.Passwords, err = optList(m["passwords"]); err != nil {
+		return nil, false, err
+	}
+	if err = parseACLRules(m, &u.Commands, &u.Keys, &u.Channels); err != nil {
+		return nil, false, err
+	}
+	if sr, ok := m["selectors"]; ok {
+		for _, e := range sr.Elems {
+			sm, err := e.pairs()
+			if err != nil {
+				return nil, false, err
+			}
+			var s ACLSelector
+			if err = parseACLRules(sm, &s.Commands, &s.Keys, &s.Channels); err != nil {
+				return nil, false, err
+			}
+			u.Selectors = append(u.Selectors, s)
+		}
+	}
+	return u, true, nil
+}
+
+// ACLSetUser creates the given user, or modifies it, applying the given rules.
+func (c *Client) ACLSetUser(name string, rules *ACLRules) error {
+	return c.Cmd("acl", "setuser", name, rules.rules).Err
+}
+
+// ACLDelUser deletes the given users and closes their connections. It returns the
+// number of users deleted.
+func (c *Client) ACLDelUser(names ...string) (int64, error) {
+	return c.Cmd("acl", "deluser", names).Int64()
+}
+
+// ACLCat returns the command categories, or the commands of the given category.
+func (c *Client) ACLCat(category ...string) ([]string, error) {
+	if len(category) > 1 {
+		return nil, errors.New("at most one category can be given")
+	}
+	return c.Cmd("acl", "cat", category).List()
+}
+
+// ACLGenPass returns a random password with the given number of bits, or 256 bits if
+// zero.
+func (c *Client) ACLGenPass(bits int) (string, error) {
+	var args []interface{}
+	if bits != 0 {
+		args = append(args, bits)
+	}
+	return c.Cmd("acl", "genpass", args).Str()
+}
+
+// optList returns a list reply, or nil if r is nil.
+func optList(r *Reply) ([]string, error) {
+	if r == nil {
+		return nil, nil
+	}
+	return r.List()
+}
+
+// parseACLRules parses the rule fields of a user or selector of ACL GETUSER. They are
+// strings since Redis 7.0, while keys and channels are lists of patterns in Redis 6.
+func parseACLRules(m map[string]*Reply, commands, keys, channels *string) error {
+	fields := []struct {
+		name, prefix string
+		p            *string
+	}{{"commands", "", commands}, {"keys", "~", keys}, {"channels", "&", channels}}
+	for _, f := range fields {
+		r, ok := m[f.name]
+		if !ok {
+			continue
+		}
+		if r.Type != MultiReply {
+			s, err := r.Str()
+			if err != nil {
+				return err
+			}
+			*f.p = s
+			continue
+		}
+		l, err := r.List()
+		if err != nil {
+			return err
+		}
+		for i, pattern := range l {
+			l[i] = f.prefix + pattern
+		}
+		*f.p = strings.Join(l, " ")
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+package redis
+
+import (
+	. "launchpad.net/gocheck"
+)
+
+type ACLSuite struct{}
+
+var _ = Suite(&ACLSuite{})
+
+func (s *ACLSuite) TestACLSetUser(c *C) {
+	cl, f := fakeClient("+OK\r\n")
+	r := NewACLRules().Reset().On().Password("secret").HashedPassword("ab12").
+		RemovePassword("old").Keys("app:*").ReadKeys("shared:*").WriteKeys("out:*").
+		Channels("news.*").AllowCategories("read").DenyCategories("dangerous").
+		AllowCommands("set", "config|get").DenyCommands("flushdb").Rule("resetchannels")
+	c.Assert(cl.ACLSetUser("app", r), IsNil)
+	c.Check(f.out.String(), Equals, "*18\r\n$3\r\nacl\r\n$7\r\nsetuser\r\n$3\r\napp\r\n"+
+		"$5\r\nreset\r\n$2\r\non\r\n$7\r\n>secret\r\n$5\r\n#ab12\r\n$4\r\n<old\r\n"+
+		"$6\r\n~app:*\r\n$11\r\n%R~shared:*\r\n$8\r\n%W~out:*\r\n$7\r\n&news.*\r\n"+
+		"$6\r\n+@read\r\n$11\r\n-@dangerous\r\n$4\r\n+set\r\n$11\r\n+config|get\r\n"+
+		"$8\r\n-flushdb\r\n$13\r\nresetchannels\r\n")
+
+	r = NewACLRules().Off().NoPass().ResetPass().AllCommands().NoCommands().AllKeys().
+		ResetKeys().AllChannels().ResetChannels()
+	c.Check(r.rules, DeepEquals, []interface{}{"off", "nopass", "resetpass", "allcommands",
+		"nocommands", "allkeys", "resetkeys", "allchannels", "resetchannels"})
+}
+
+func (s *ACLSuite) TestACLGetUser(c *C) {
+	cl, f := fakeClient("*10\r\n$5\r\nflags\r\n*1\r\n$2\r\non\r\n" +
+		"$9\r\npasswords\r\n*1\r\n$4\r\nab12\r\n$8\r\ncommands\r\n$10\r\n-@all +get\r\n" +
+		"$4\r\nkeys\r\n$6\r\n~app:*\r\n$9\r\nselectors\r\n*1\r\n" +
+		"*6\r\n$8\r\ncommands\r\n$4\r\n+set\r\n$4\r\nkeys\r\n$5\r\n%W~x*\r\n" +
+		"$8\r\nchannels\r\n$0\r\n\r\n" +
+		"*8\r\n$5\r\nflags\r\n*2\r\n$3\r\noff\r\n$6\r\nnopass\r\n" +
+		"$8\r\ncommands\r\n$5\r\n+@all\r\n" +
+		"$4\r\nkeys\r\n*2\r\n$1\r\na\r\n$1\r\nb\r\n$8\r\nchannels\r\n*1\r\n$1\r\n*\r\n" +
+		"$-1\r\n")
+	u, ok, err := cl.ACLGetUser("app")
+	c.Assert(err, IsNil)
+	c.Check(ok, Equals, true)
+	c.Check(f.out.String(), Equals, "*3\r\n$3\r\nacl\r\n$7\r\ngetuser\r\n$3\r\napp\r\n")
+	c.Check(u, DeepEquals, &ACLUser{
+		Flags:     []string{"on"},
+		Passwords: []string{"ab12"},
+		Commands:  "-@all +get",
+		Keys:      "~app:*",
+		Selectors: []ACLSelector{{Commands: "+set", Keys: "%W~x*"}},
+	})
+
+	u, ok, err = cl.ACLGetUser("old")
+	c.Assert(err, IsNil)
+	c.Check(ok, Equals, true)
+	c.Check(u, DeepEquals, &ACLUser{Flags: []string{"off", "nopass"}, Commands: "+@all",
+		Keys: "~a ~b", Channels: "&*"})
+
+	_, ok, err = cl.ACLGetUser("nobody")
+	c.Assert(err, IsNil)
+	c.Check(ok, Equals, false)
+}
+
+func (s *ACLSuite) TestACLCommands(c *C) {
+	cl, f := fakeClient("$7\r\ndefault\r\n*1\r\n$24\r\nuser default on ~* +@all\r\n" +
+		"*1\r\n$7\r\ndefault\r\n:1\r\n*1\r\n$4\r\nread\r\n*1\r\n$3\r\nget\r\n" +
+		"$4\r\nab12\r\n")
+	who, err := cl.ACLWhoAmI()
+	c.Assert(err, IsNil)
+	c.Check(who, Equals, "default")
+	l, err := cl.ACLList()
+	c.Assert(err, IsNil)
+	c.Check(l, DeepEquals, []string{"user default on ~* +@all"})
+	l, err = cl.ACLUsers()
+	c.Assert(err, IsNil)
+	c.Check(l, DeepEquals, []string{"default"})
+	n, err := cl.ACLDelUser("a", "b")
+	c.Assert(err, IsNil)
+	c.Check(n, Equals, int64(1))
+	_, err = cl.ACLCat()
+	c.Assert(err, IsNil)
+	l, err = cl.ACLCat("read")
+	c.Assert(err, IsNil)
+	c.Check(l, DeepEquals, []string{"get"})
+	p, err := cl.ACLGenPass(16)
+	c.Assert(err, IsNil)
+	c.Check(p, Equals, "ab12")
+	c.Check(f.out.String(), Equals, "*2\r\n$3\r\nacl\r\n$6\r\nwhoami\r\n"+
+		"*2\r\n$3\r\nacl\r\n$4\r\nlist\r\n*2\r\n$3\r\nacl\r\n$5\r\nusers\r\n"+
+		"*4\r\n$3\r\nacl\r\n$7\r\ndeluser\r\n$1\r\na\r\n$1\r\nb\r\n"+
+		"*2\r\n$3\r\nacl\r\n$3\r\ncat\r\n*3\r\n$3\r\nacl\r\n$3\r\ncat\r\n$4\r\nread\r\n"+
+		"*3\r\n$3\r\nacl\r\n$7\r\ngenpass\r\n$2\r\n16\r\n")
+	_, err = cl.ACLCat("a", "b")
+	c.Check(err, NotNil)
+}
@@ -0,0 +1,228 @@
+package redis
+
+import (
+	"math/rand"
+	"sort"
+	"strings"
+	"time"
+)
+
+//* Keyspace analytics
+
+// DefaultTTLBounds are the default upper bounds of the TTL buckets of AnalyzeKeyspace.
+var DefaultTTLBounds = []time.Duration{
+	time.Minute, time.Hour, 24 * time.Hour, 7 * 24 * time.Hour,
+}
+
+// DefaultSizeBounds are the default upper bounds of the size buckets of AnalyzeKeyspace.
+var DefaultSizeBounds = []int64{64, 1 << 10, 16 << 10, 256 << 10, 1 << 20, 16 << 20}
+
+// KeyspaceOptions configures AnalyzeKeyspace.
+type KeyspaceOptions struct {
+	Match      string  // SCAN pattern, all keys if empty
+	Count      int     // SCAN COUNT hint, see Scan
+	SampleRate float64 // Fraction of the scanned keys analyzed, all keys if zero
+	MaxKeys    int     // Number of keys analyzed, all keys if zero
+	SkipMemory bool    // Don't measure the keys with MEMORY USAGE
+	Samples    int     // See MemoryUsage
+
+	Separator  string          // Separator of key prefixes, ":" if empty
+	Depth      int             // Number of segments of key prefixes, 1 if zero
+	Top        int             // Number of biggest keys and prefixes reported, 10 if zero
+	TTLBounds  []time.Duration // DefaultTTLBounds if nil
+	SizeBounds []int64         // DefaultSizeBounds if nil
+}
+
+// TTLBucket counts the keys whose time to live is at most Max, and above the Max of
+// the previous bucket. The last bucket has no upper bound and a zero Max.
+type TTLBucket struct {
+	Max   time.Duration
+	Count int64
+}
+
+// SizeBucket counts the keys whose size in bytes is at most Max, and above the Max of
+// the previous bucket. The last bucket has no upper bound and a zero Max.
+type SizeBucket struct {
+	Max   int64
+	Count int64
+}
+
+// PrefixStats is the number of keys sharing a prefix, and their total size.
+type PrefixStats struct {
+	Prefix string // Without the trailing separator, empty for keys without one
+	Keys   int64
+	Bytes  int64
+}
+
+// KeyspaceReport is the result of AnalyzeKeyspace. The counts only cover the analyzed
+// keys.
+type KeyspaceReport struct {
+	Scanned  int64            // Keys returned by SCAN
+	Analyzed int64            // Keys analyzed, not counting the ones deleted meanwhile
+	Types    map[string]int64 // Keys by type
+	Bytes    int64            // Total size of the keys
+	NoTTL    int64            // Keys without a time to live
+	TTLs     []TTLBucket      // Keys with a time to live
+	Sizes    []SizeBucket     // Empty if SkipMemory is set
+	Biggest  []KeySize        // Biggest keys, biggest first
+	Prefixes []PrefixStats    // Prefixes with the most keys, most first
+}
+
+// AnalyzeKeyspace scans the keyspace with SCAN and reports the types, times to live,
+// sizes and prefixes of the keys, like redis-cli --bigkeys and --memkeys. The keys of
+// each page are analyzed with one pipeline of TYPE, PTTL and MEMORY USAGE. On large
+// databases, SampleRate or MaxKeys should be set to limit the load on the server.
+func (c *Client) AnalyzeKeyspace(opt KeyspaceOptions) (*KeyspaceReport, error) {
+	if opt.Separator == "" {
+		opt.Separator = ":"
+	}
+	if opt.Depth == 0 {
+		opt.Depth = 1
+	}
+	if opt.Top == 0 {
+		opt.Top = 10
+	}
+	if opt.TTLBounds == nil {
+		opt.TTLBounds = DefaultTTLBounds
+	}
+	if opt.SizeBounds == nil {
+		opt.SizeBounds = DefaultSizeBounds
+	}
+
+	rep := &KeyspaceReport{Types: map[string]int64{}}
+	rep.TTLs = make([]TTLBucket, len(opt.TTLBounds)+1)
+	for i, b := range opt.TTLBounds {
+		rep.TTLs[i].Max = b
+	}
+	if !opt.SkipMemory {
+		rep.Sizes = make([]SizeBucket, len(opt.SizeBounds)+1)
+		for i, b := range opt.SizeBounds {
+			rep.Sizes[i].Max = b
+		}
+	}
+	prefixes := map[string]*PrefixStats{}
+
+	it := c.Scan(opt.Match, opt.Count)
+	var keys []string
+	selected := 0
+	for it.Next() {
+		rep.Scanned++
+		if opt.SampleRate > 0 && rand.Float64() >= opt.SampleRate {
+			continue
+		}
+		keys = append(keys, it.Val())
+		selected++
+		done := opt.MaxKeys > 0 && selected >= opt.MaxKeys
+		if len(it.buf) > 0 && !done {
+			continue
+		}
+		if err := c.analyzeKeys(keys, &opt, rep, prefixes); err != nil {
+			return nil, err
+		}
+		keys = keys[:0]
+		if done {
+			break
+		}
+	}
+	if err := it.Err(); err != nil {
+		return nil, err
+	}
+	if len(keys) > 0 {
+		if err := c.analyzeKeys(keys, &opt, rep, prefixes); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, p := range prefixes {
+		rep.Prefixes = append(rep.Prefixes, *p)
+	}
+	sort.Slice(rep.Prefixes, func(i, j int) bool {
+		a, b := rep.Prefixes[i], rep.Prefixes[j]
+		return a.Keys > b.Keys || a.Keys == b.Keys && a.Prefix < b.Prefix
+	})
+	if len(rep.Prefixes) > opt.Top {
+		rep.Prefixes = rep.Prefixes[:opt.Top]
+	}
+	return rep, nil
+}
+
+// analyzeKeys adds the given keys to the report, with one pipeline.
+func (c *Client) analyzeKeys(keys []string, opt *KeyspaceOptions, rep *KeyspaceReport,
+	prefixes map[string]*PrefixStats) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	var reqs []*request
+	for _, k := range keys {
+		reqs = append(reqs, &request{cmd: "type", args: []interface{}{k}},
+			&request{cmd: "pttl", args: []interface{}{k}})
+		if !opt.SkipMemory {
+			reqs = append(reqs, &request{cmd: "memory",
+				args: []interface{}{"usage", k, samplesArgs(opt.Samples)}})
+		}
+	}
+	replies := c.flush(reqs)
+	var err error
+	for _, k := range keys {
+		typ, e := replies[0].Str()
+		if e != nil && err == nil {
+			err = e
+		}
+		ttl, e := parseTTL(replies[1])
+		if e != nil && err == nil {
+			err = e
+		}
+		replies = replies[2:]
+		var size int64
+		found := true
+		if !opt.SkipMemory {
+			size, found, e = optInt64(replies[0])
+			if e != nil && err == nil {
+				err = e
+			}
+			replies = replies[1:]
+		}
+		if err != nil || typ == "none" || ttl == KeyMissing || !found {
+			continue // deleted since scanned
+		}
+
+		rep.Analyzed++
+		rep.Types[typ]++
+		if ttl == NoExpiry {
+			rep.NoTTL++
+		} else {
+			i := sort.Search(len(opt.TTLBounds), func(i int) bool {
+				return ttl <= opt.TTLBounds[i]
+			})
+			rep.TTLs[i].Count++
+		}
+		if !opt.SkipMemory {
+			rep.Bytes += size
+			i := sort.Search(len(opt.SizeBounds), func(i int) bool {
+				return size <= opt.SizeBounds[i]
+			})
+			rep.Sizes[i].Count++
+			rep.Biggest = addTopKey(rep.Biggest, KeySize{k, size}, opt.Top)
+		}
+
+		prefix := keyPrefix(k, opt.Separator, opt.Depth)
+		p := prefixes[prefix]
+		if p == nil {
+			p = &PrefixStats{Prefix: prefix}
+			prefixes[prefix] = p
+		}
+		p.Keys++
+		p.Bytes += size
+	}
+	return err
+}
+
+// keyPrefix returns the first depth segments of the given key, or an empty string if
+// it has fewer.
+func keyPrefix(key, sep string, depth int) string {
+	parts := strings.SplitN(key, sep, depth+1)
+	if len(parts) <= depth {
+		return ""
+	}
+	return strings.Join(parts[:depth], sep)
+}
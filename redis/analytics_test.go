@@ -0,0 +1,73 @@
+package redis
+
+import (
+	. "launchpad.net/gocheck"
+	"time"
+)
+
+type AnalyticsSuite struct{}
+
+var _ = Suite(&AnalyticsSuite{})
+
+func (s *AnalyticsSuite) TestKeyPrefix(c *C) {
+	c.Check(keyPrefix("user:1:name", ":", 1), Equals, "user")
+	c.Check(keyPrefix("user:1:name", ":", 2), Equals, "user:1")
+	c.Check(keyPrefix("user:1", ":", 2), Equals, "")
+	c.Check(keyPrefix("plain", ":", 1), Equals, "")
+	c.Check(keyPrefix("a/b", "/", 1), Equals, "a")
+}
+
+func (s *AnalyticsSuite) TestAnalyzeKeyspace(c *C) {
+	cl, f := fakeClient("*2\r\n$1\r\n9\r\n*3\r\n$6\r\nuser:1\r\n$6\r\nuser:2\r\n$4\r\nlone\r\n" +
+		"+hash\r\n:-1\r\n:100\r\n" +
+		"+string\r\n:30000\r\n:5000\r\n" +
+		"+none\r\n:-2\r\n$-1\r\n" +
+		"*2\r\n$1\r\n0\r\n*1\r\n$7\r\njob:abc\r\n" +
+		"+list\r\n:7200000\r\n:40\r\n")
+	rep, err := cl.AnalyzeKeyspace(KeyspaceOptions{Top: 2, Samples: 5})
+	c.Assert(err, IsNil)
+	c.Check(f.out.String()[:47], Equals,
+		"*2\r\n$4\r\nscan\r\n$1\r\n0\r\n*2\r\n$4\r\ntype\r\n$6\r\nuser:1\r\n")
+	c.Check(rep.Scanned, Equals, int64(4))
+	c.Check(rep.Analyzed, Equals, int64(3))
+	c.Check(rep.Types, DeepEquals, map[string]int64{"hash": 1, "string": 1, "list": 1})
+	c.Check(rep.Bytes, Equals, int64(5140))
+	c.Check(rep.NoTTL, Equals, int64(1))
+	c.Check(rep.TTLs, DeepEquals, []TTLBucket{{time.Minute, 1}, {time.Hour, 0},
+		{24 * time.Hour, 1}, {7 * 24 * time.Hour, 0}, {0, 0}})
+	c.Check(rep.Sizes, DeepEquals, []SizeBucket{{64, 1}, {1 << 10, 1}, {16 << 10, 1},
+		{256 << 10, 0}, {1 << 20, 0}, {16 << 20, 0}, {0, 0}})
+	c.Check(rep.Biggest, DeepEquals, []KeySize{{"user:2", 5000}, {"user:1", 100}})
+	c.Check(rep.Prefixes, DeepEquals, []PrefixStats{{"user", 2, 5100}, {"job", 1, 40}})
+}
+
+func (s *AnalyticsSuite) TestAnalyzeKeyspaceLimits(c *C) {
+	cl, f := fakeClient("*2\r\n$1\r\n9\r\n*3\r\n$1\r\na\r\n$1\r\nb\r\n$1\r\nc\r\n" +
+		"+set\r\n:-1\r\n+set\r\n:20000000000\r\n")
+	rep, err := cl.AnalyzeKeyspace(KeyspaceOptions{MaxKeys: 2, SkipMemory: true,
+		TTLBounds: []time.Duration{time.Hour}})
+	c.Assert(err, IsNil)
+	c.Check(f.out.String(), Equals, "*2\r\n$4\r\nscan\r\n$1\r\n0\r\n"+
+		"*2\r\n$4\r\ntype\r\n$1\r\na\r\n*2\r\n$4\r\npttl\r\n$1\r\na\r\n"+
+		"*2\r\n$4\r\ntype\r\n$1\r\nb\r\n*2\r\n$4\r\npttl\r\n$1\r\nb\r\n")
+	c.Check(rep.Scanned, Equals, int64(2))
+	c.Check(rep.Analyzed, Equals, int64(2))
+	c.Check(rep.TTLs, DeepEquals, []TTLBucket{{time.Hour, 0}, {0, 1}})
+	c.Check(rep.Sizes, HasLen, 0)
+	c.Check(rep.Biggest, HasLen, 0)
+	c.Check(rep.Prefixes, DeepEquals, []PrefixStats{{"", 2, 0}})
+}
+
+func (s *AnalyticsSuite) TestAnalyzeKeyspaceKeepsPipeline(c *C) {
+	cl, f := fakeClient("*2\r\n$1\r\n0\r\n*1\r\n$1\r\na\r\n+set\r\n:-1\r\n$3\r\nbar\r\n")
+	cl.Append("get", "foo")
+	rep, err := cl.AnalyzeKeyspace(KeyspaceOptions{SkipMemory: true})
+	c.Assert(err, IsNil)
+	c.Check(rep.Analyzed, Equals, int64(1))
+	c.Check(f.out.String(), Equals, "*2\r\n$4\r\nscan\r\n$1\r\n0\r\n"+
+		"*2\r\n$4\r\ntype\r\n$1\r\na\r\n*2\r\n$4\r\npttl\r\n$1\r\na\r\n")
+	v, err := cl.GetReply().Str()
+	c.Assert(err, IsNil)
+	c.Check(v, Equals, "bar")
+	c.Check(cl.GetReply().Err, Equals, PipelineQueueEmptyError)
+}
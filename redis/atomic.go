@@ -0,0 +1,82 @@
+package redis
+
+import (
+	"time"
+)
+
+//* Atomic helpers
+
+var casScript = NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	redis.call("SET", KEYS[1], ARGV[2], "KEEPTTL")
+	return 1
+end
+return 0`)
+
+var getExpireScript = NewScript(`
+local v = redis.call("GET", KEYS[1])
+if v then
+	redis.call("PEXPIRE", KEYS[1], ARGV[1])
+end
+return v`)
+
+var incrCapScript = NewScript(`
+local v = tonumber(redis.call("GET", KEYS[1]) or "0") + tonumber(ARGV[1])
+if v > tonumber(ARGV[2]) then
+	return {0, v - tonumber(ARGV[1])}
+end
+redis.call("SET", KEYS[1], v, "KEEPTTL")
+return {1, v}`)
+
+var popPushScript = NewScript(`
+local v = redis.call("RPOPLPUSH", KEYS[1], KEYS[2])
+if v then
+	redis.call("PEXPIRE", KEYS[2], ARGV[1])
+end
+return v`)
+
+// CompareAndSwap sets the given key to new if its current value is old,
+// keeping its time to live, and returns true if it did (Redis 6.0 or later).
+func CompareAndSwap(c Cmder, key, old, new string) (bool, error) {
+	return casScript.Run(c, []string{key}, old, new).Bool()
+}
+
+// GetAndExpire returns the value of the given key and sets its time to live.
+// ok is false if the key does not exist.
+func GetAndExpire(c Cmder, key string, ttl time.Duration) (v string, ok bool, err error) {
+	return optStr(getExpireScript.Run(c, []string{key}, int64(ttl/time.Millisecond)))
+}
+
+// IncrWithCap increments the integer value of the given key by incr unless the result
+// would exceed max, keeping its time to live (Redis 6.0 or later).
+// It returns the value of the key after the call and whether it was incremented.
+func IncrWithCap(c Cmder, key string, incr, max int64) (n int64, ok bool, err error) {
+	r := incrCapScript.Run(c, []string{key}, incr, max)
+	if r.Type == ErrorReply {
+		return 0, false, r.Err
+	}
+	if r.Type != MultiReply || len(r.Elems) != 2 {
+		return 0, false, ParseError
+	}
+	if ok, err = r.Elems[0].Bool(); err != nil {
+		return 0, false, err
+	}
+	n, err = r.Elems[1].Int64()
+	return n, ok, err
+}
+
+// PopPushWithTTL moves the last element of the list src to the head of the list dst,
+// like RPOPLPUSH, and sets the time to live of dst.
+// ok is false if src is empty.
+func PopPushWithTTL(c Cmder, src, dst string, ttl time.Duration) (v string, ok bool, err error) {
+	return optStr(popPushScript.Run(c, []string{src, dst}, int64(ttl/time.Millisecond)))
+}
+
+// optStr returns the string value of the given reply and false if it is a nil reply.
+func optStr(r *Reply) (string, bool, error) {
+	if r.Type == NilReply {
+		return "", false, nil
+	}
+	s, err := r.Str()
+	return s, err == nil, err
+}
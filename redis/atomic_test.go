@@ -0,0 +1,53 @@
+package redis
+
+import (
+	. "launchpad.net/gocheck"
+	"strings"
+	"time"
+)
+
+type AtomicSuite struct{}
+
+var _ = Suite(&AtomicSuite{})
+
+func (s *AtomicSuite) TestCompareAndSwap(c *C) {
+	cl, f := fakeClient(":1\r\n")
+	ok, err := CompareAndSwap(cl, "k", "a", "b")
+	c.Assert(err, IsNil)
+	c.Check(ok, Equals, true)
+	c.Check(strings.HasPrefix(f.out.String(), "*6\r\n$7\r\nevalsha\r\n$40\r\n"+casScript.SHA()),
+		Equals, true)
+}
+
+func (s *AtomicSuite) TestGetAndExpire(c *C) {
+	cl, f := fakeClient("$1\r\nv\r\n$-1\r\n")
+	v, ok, err := GetAndExpire(cl, "k", time.Second)
+	c.Assert(err, IsNil)
+	c.Check(v, Equals, "v")
+	c.Check(ok, Equals, true)
+	c.Check(strings.HasSuffix(f.out.String(), "$1\r\nk\r\n$4\r\n1000\r\n"), Equals, true)
+	_, ok, err = GetAndExpire(cl, "k", time.Second)
+	c.Assert(err, IsNil)
+	c.Check(ok, Equals, false)
+}
+
+func (s *AtomicSuite) TestIncrWithCap(c *C) {
+	cl, _ := fakeClient("*2\r\n:1\r\n:5\r\n*2\r\n:0\r\n:5\r\n")
+	n, ok, err := IncrWithCap(cl, "k", 5, 5)
+	c.Assert(err, IsNil)
+	c.Check(n, Equals, int64(5))
+	c.Check(ok, Equals, true)
+	n, ok, err = IncrWithCap(cl, "k", 5, 5)
+	c.Assert(err, IsNil)
+	c.Check(n, Equals, int64(5))
+	c.Check(ok, Equals, false)
+}
+
+func (s *AtomicSuite) TestPopPushWithTTL(c *C) {
+	cl, f := fakeClient("-NOSCRIPT No matching script.\r\n$1\r\nx\r\n")
+	v, ok, err := PopPushWithTTL(cl, "a", "b", time.Minute)
+	c.Assert(err, IsNil)
+	c.Check(v, Equals, "x")
+	c.Check(ok, Equals, true)
+	c.Check(strings.Contains(f.out.String(), "RPOPLPUSH"), Equals, true)
+}
@@ -0,0 +1,152 @@
+package redis
+
+import (
+	"strconv"
+)
+
+//* Bitmaps
+
+// SetBit sets the bit at the given offset of the value of the given key and returns
+// its old value.
+func (c *Client) SetBit(key string, offset int64, value bool) (bool, error) {
+	return c.Cmd("setbit", key, offset, value).Bool()
+}
+
+// GetBit returns the bit at the given offset of the value of the given key.
+func (c *Client) GetBit(key string, offset int64) (bool, error) {
+	return c.Cmd("getbit", key, offset).Bool()
+}
+
+// BitRange is a range of a bitmap, both inclusive. Negative offsets count from the end.
+type BitRange struct {
+	Start, End int64
+	Bit        bool // Offsets are in bits instead of bytes (Redis 7.0 or later)
+}
+
+func (r BitRange) args() []interface{} {
+	if r.Bit {
+		return []interface{}{r.Start, r.End, "bit"}
+	}
+	return []interface{}{r.Start, r.End}
+}
+
+// BitCount returns the number of set bits in the value of the given key.
+func (c *Client) BitCount(key string) (int64, error) {
+	return c.Cmd("bitcount", key).Int64()
+}
+
+// BitCountRange returns the number of set bits in the given range of the value of
+// the given key.
+func (c *Client) BitCountRange(key string, r BitRange) (int64, error) {
+	return c.Cmd("bitcount", key, r.args()).Int64()
+}
+
+// BitPos returns the offset of the first bit set to the given value in the value of
+// the given key, or -1 if there is none.
+func (c *Client) BitPos(key string, bit bool) (int64, error) {
+	return c.Cmd("bitpos", key, bit).Int64()
+}
+
+// BitPosRange is like BitPos, but only searches the given range.
+func (c *Client) BitPosRange(key string, bit bool, r BitRange) (int64, error) {
+	return c.Cmd("bitpos", key, bit, r.args()).Int64()
+}
+
+// BitFieldType is the integer type of a bit field.
+type BitFieldType struct {
+	Signed bool
+	Bits   int // Up to 64 for signed and 63 for unsigned integers
+}
+
+// Signed returns the type of signed bit fields of the given width.
+func Signed(bits int) BitFieldType {
+	return BitFieldType{Signed: true, Bits: bits}
+}
+
+// Unsigned returns the type of unsigned bit fields of the given width.
+func Unsigned(bits int) BitFieldType {
+	return BitFieldType{Bits: bits}
+}
+
+func (t BitFieldType) String() string {
+	if t.Signed {
+		return "i" + strconv.Itoa(t.Bits)
+	}
+	return "u" + strconv.Itoa(t.Bits)
+}
+
+// Overflow is the overflow behaviour of BITFIELD SET and INCRBY operations.
+type Overflow string
+
+const (
+	OverflowWrap Overflow = "wrap" // Wrap around, the default
+	OverflowSat  Overflow = "sat"  // Saturate at the minimum or maximum value
+	OverflowFail Overflow = "fail" // Skip the operation, its result is not OK
+)
+
+// BitField builds a BITFIELD command of operations on the bit fields of a key.
+// Offsets are in bits.
+type BitField struct {
+	key  string
+	args []interface{}
+}
+
+// NewBitField returns an empty BitField of the given key.
+func NewBitField(key string) *BitField {
+	return &BitField{key: key}
+}
+
+// Get adds an operation returning the bit field of the given type at the given offset.
+func (b *BitField) Get(t BitFieldType, offset int64) *BitField {
+	b.args = append(b.args, "get", t.String(), offset)
+	return b
+}
+
+// Set adds an operation setting the bit field of the given type at the given offset
+// and returning its old value.
+func (b *BitField) Set(t BitFieldType, offset, value int64) *BitField {
+	b.args = append(b.args, "set", t.String(), offset, value)
+	return b
+}
+
+// IncrBy adds an operation incrementing the bit field of the given type at the given
+// offset and returning its new value.
+func (b *BitField) IncrBy(t BitFieldType, offset, incr int64) *BitField {
+	b.args = append(b.args, "incrby", t.String(), offset, incr)
+	return b
+}
+
+// Overflow sets the overflow behaviour of the following Set and IncrBy operations.
+func (b *BitField) Overflow(o Overflow) *BitField {
+	b.args = append(b.args, "overflow", string(o))
+	return b
+}
+
+// BitFieldResult is the result of a BitField operation.
+type BitFieldResult struct {
+	Value int64
+	OK    bool // False if the operation was skipped because of OverflowFail
+}
+
+// BitField runs the operations of the given BitField and returns their results.
+func (c *Client) BitField(b *BitField) ([]BitFieldResult, error) {
+	r := c.Cmd("bitfield", b.key, b.args)
+	if r.Type == ErrorReply {
+		return nil, r.Err
+	}
+	if r.Type != MultiReply {
+		return nil, ParseError
+	}
+	results := make([]BitFieldResult, len(r.Elems))
+	for i, e := range r.Elems {
+		if e.Type == NilReply {
+			continue
+		}
+		v, err := e.Int64()
+		if err != nil {
+			return nil, err
+		}
+		results[i] = BitFieldResult{Value: v, OK: true}
+	}
+	return results, nil
+}
@@ -0,0 +1,44 @@
+package redis
+
+import (
+	. "launchpad.net/gocheck"
+	"strings"
+)
+
+type BitmapSuite struct{}
+
+var _ = Suite(&BitmapSuite{})
+
+func (s *BitmapSuite) TestBits(c *C) {
+	cl, f := fakeClient(":0\r\n:1\r\n:3\r\n:-1\r\n")
+	old, err := cl.SetBit("b", 7, true)
+	c.Assert(err, IsNil)
+	c.Check(old, Equals, false)
+	bit, err := cl.GetBit("b", 7)
+	c.Assert(err, IsNil)
+	c.Check(bit, Equals, true)
+	n, err := cl.BitCountRange("b", BitRange{Start: 0, End: 15, Bit: true})
+	c.Assert(err, IsNil)
+	c.Check(n, Equals, int64(3))
+	n, err = cl.BitPosRange("b", false, BitRange{Start: 2, End: -1})
+	c.Assert(err, IsNil)
+	c.Check(n, Equals, int64(-1))
+	c.Check(strings.HasPrefix(f.out.String(),
+		"*4\r\n$6\r\nsetbit\r\n$1\r\nb\r\n$1\r\n7\r\n$1\r\n1\r\n"), Equals, true)
+	c.Check(strings.HasSuffix(f.out.String(),
+		"*5\r\n$6\r\nbitpos\r\n$1\r\nb\r\n$1\r\n0\r\n$1\r\n2\r\n$2\r\n-1\r\n"), Equals, true)
+}
+
+func (s *BitmapSuite) TestBitField(c *C) {
+	cl, f := fakeClient("*3\r\n:0\r\n:100\r\n*-1\r\n")
+	b := NewBitField("b").Set(Unsigned(8), 0, 200).Get(Signed(8), 0).
+		Overflow(OverflowFail).IncrBy(Unsigned(8), 0, 100)
+	results, err := cl.BitField(b)
+	c.Assert(err, IsNil)
+	c.Check(results, DeepEquals, []BitFieldResult{{0, true}, {100, true}, {0, false}})
+	c.Check(f.out.String(), Equals, "*15\r\n$8\r\nbitfield\r\n$1\r\nb\r\n"+
+		"$3\r\nset\r\n$2\r\nu8\r\n$1\r\n0\r\n$3\r\n200\r\n"+
+		"$3\r\nget\r\n$2\r\ni8\r\n$1\r\n0\r\n"+
+		"$8\r\noverflow\r\n$4\r\nfail\r\n"+
+		"$6\r\nincrby\r\n$2\r\nu8\r\n$1\r\n0\r\n$3\r\n100\r\n")
+}
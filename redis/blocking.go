@@ -0,0 +1,136 @@
+package redis
+
+import (
+	"context"
+	"strconv"
+	"time"
+)
+
+//* Blocking list operations
+
+// ListSide is an end of a list.
+type ListSide string
+
+const (
+	ListLeft  ListSide = "left"
+	ListRight ListSide = "right"
+)
+
+// BLPop pops the first element of the first non-empty list of the given keys, waiting up
+// to the given timeout for one, or forever if it is 0. It returns the key and the
+// element, or false if the timeout expired.
+//
+// Blocking operations hold the connection of the client while they wait, so use a
+// dedicated client for them. The read timeout of the client is extended by the timeout
+// of the operation. Done contexts interrupt the operation by closing the client, as the
+// connection is not usable until the server replies.
+func (c *Client) BLPop(ctx context.Context, timeout time.Duration, keys ...string) (key,
+	value string, ok bool, err error) {
+	return parseBPop(c.blockingCmd(ctx, timeout, "blpop", keys, blockSeconds(timeout)))
+}
+
+// BRPop is like BLPop, but pops the last element.
+func (c *Client) BRPop(ctx context.Context, timeout time.Duration, keys ...string) (key,
+	value string, ok bool, err error) {
+	return parseBPop(c.blockingCmd(ctx, timeout, "brpop", keys, blockSeconds(timeout)))
+}
+
+// BLMove moves an element from the given side of the list src to the given side of the
+// list dst, waiting up to the given timeout for src to have one, and returns it
+// (Redis 6.2 or later). It returns false if the timeout expired. See BLPop.
+func (c *Client) BLMove(ctx context.Context, src, dst string, from, to ListSide,
+	timeout time.Duration) (string, bool, error) {
+	r := c.blockingCmd(ctx, timeout, "blmove", src, dst, string(from), string(to),
+		blockSeconds(timeout))
+	return optStr(c.checkVersion(Version{6, 2, 0}, "blmove", r))
+}
+
+// BLMPop pops up to count elements from the given side of the first non-empty list of
+// the given keys, waiting up to the given timeout for one (Redis 7.0 or later).
+// It returns the key and the elements, or false if the timeout expired. See BLPop.
+func (c *Client) BLMPop(ctx context.Context, timeout time.Duration, side ListSide,
+	count int64, keys ...string) (key string, values []string, ok bool, err error) {
+	r := c.blockingCmd(ctx, timeout, "blmpop", blockSeconds(timeout), len(keys), keys,
+		string(side), "count", count)
+	return parseMPop(c.checkVersion(Version{7, 0, 0}, "blmpop", r))
+}
+
+// blockingCmd calls the given blocking command with the given server-side timeout,
+// extending the read timeout of the client by it. Done contexts close the client.
+func (c *Client) blockingCmd(ctx context.Context, timeout time.Duration, cmd string,
+	args ...interface{}) *Reply {
+	if err := ctx.Err(); err != nil {
+		return &Reply{Type: ErrorReply, Err: err}
+	}
+	saved := c.timeout
+	if timeout == 0 {
+		c.timeout = 0
+	} else if c.timeout != 0 {
+		c.timeout += timeout
+	}
+	defer func() { c.timeout = saved }()
+
+	conn := c.conn
+	done := make(chan struct{})
+	exited := make(chan struct{})
+	go func() {
+		defer close(exited)
+		select {
+		case <-ctx.Done():
+			conn.Close()
+		case <-done:
+		}
+	}()
+	r := c.CmdContext(ctx, cmd, args...)
+	close(done)
+	<-exited
+	if r.Type == ErrorReply && ctx.Err() != nil {
+		return &Reply{Type: ErrorReply, Err: ctx.Err()}
+	}
+	return r
+}
+
+// blockSeconds returns the given timeout as the seconds argument of blocking commands.
+// Redis does not parse exponents, and takes timeouts under a millisecond as 0, that is
+// forever, so positive timeouts are sent with millisecond precision and at least 1ms.
+func blockSeconds(timeout time.Duration) string {
+	if timeout > 0 && timeout < time.Millisecond {
+		timeout = time.Millisecond
+	}
+	return strconv.FormatFloat(timeout.Seconds(), 'f', 3, 64)
+}
+
+// parseBPop parses a key, element pair, or a nil reply on timeout.
+func parseBPop(r *Reply) (key, value string, ok bool, err error) {
+	if r.Type == NilReply {
+		return "", "", false, nil
+	}
+	l, err := r.List()
+	if err != nil {
+		return "", "", false, err
+	}
+	if len(l) != 2 {
+		return "", "", false, ParseError
+	}
+	return l[0], l[1], true, nil
+}
+
+// parseMPop parses a key, elements pair, or a nil reply if there are no elements.
+func parseMPop(r *Reply) (key string, values []string, ok bool, err error) {
+	if r.Type == NilReply {
+		return "", nil, false, nil
+	}
+	if r.Type == ErrorReply {
+		return "", nil, false, r.Err
+	}
+	if r.Type != MultiReply || len(r.Elems) != 2 {
+		return "", nil, false, ParseError
+	}
+	if key, err = r.Elems[0].Str(); err != nil {
+		return "", nil, false, err
+	}
+	if values, err = r.Elems[1].List(); err != nil {
+		return "", nil, false, err
+	}
+	return key, values, true, nil
+}
@@ -0,0 +1,69 @@
+package redis
+
+import (
+	"context"
+	. "launchpad.net/gocheck"
+	"net"
+	"time"
+)
+
+type BlockingSuite struct{}
+
+var _ = Suite(&BlockingSuite{})
+
+func (s *BlockingSuite) TestBLPop(c *C) {
+	cl, f := fakeClient("*2\r\n$1\r\nl\r\n$1\r\na\r\n*-1\r\n")
+	key, v, ok, err := cl.BLPop(context.Background(), 1500*time.Millisecond, "l", "m")
+	c.Assert(err, IsNil)
+	c.Check(ok, Equals, true)
+	c.Check(key, Equals, "l")
+	c.Check(v, Equals, "a")
+	c.Check(f.out.String(), Equals, "*4\r\n$5\r\nblpop\r\n$1\r\nl\r\n$1\r\nm\r\n$5\r\n1.500\r\n")
+
+	_, _, ok, err = cl.BRPop(context.Background(), time.Second, "l")
+	c.Assert(err, IsNil)
+	c.Check(ok, Equals, false)
+}
+
+func (s *BlockingSuite) TestBlockSeconds(c *C) {
+	c.Check(blockSeconds(0), Equals, "0.000")
+	c.Check(blockSeconds(time.Microsecond), Equals, "0.001")
+	c.Check(blockSeconds(1500*time.Millisecond), Equals, "1.500")
+	c.Check(blockSeconds(time.Hour), Equals, "3600.000")
+}
+
+func (s *BlockingSuite) TestBLMPop(c *C) {
+	cl, f := fakeClient("*2\r\n$1\r\nl\r\n*2\r\n$1\r\na\r\n$1\r\nb\r\n$1\r\nc\r\n")
+	key, values, ok, err := cl.BLMPop(context.Background(), 0, ListLeft, 2, "l")
+	c.Assert(err, IsNil)
+	c.Check(ok, Equals, true)
+	c.Check(key, Equals, "l")
+	c.Check(values, DeepEquals, []string{"a", "b"})
+	c.Check(f.out.String(), Equals, "*7\r\n$6\r\nblmpop\r\n$5\r\n0.000\r\n$1\r\n1\r\n$1\r\nl\r\n"+
+		"$4\r\nleft\r\n$5\r\ncount\r\n$1\r\n2\r\n")
+
+	v, ok, err := cl.BLMove(context.Background(), "l", "m", ListLeft, ListRight, 0)
+	c.Assert(err, IsNil)
+	c.Check(ok, Equals, true)
+	c.Check(v, Equals, "c")
+}
+
+func (s *BlockingSuite) TestCancel(c *C) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	c.Assert(err, IsNil)
+	defer l.Close()
+	go func() {
+		if conn, err := l.Accept(); err == nil {
+			defer conn.Close()
+			conn.Read(make([]byte, 64))
+			time.Sleep(time.Second)
+		}
+	}()
+	cl, err := DialTimeout("tcp", l.Addr().String(), 10*time.Millisecond)
+	c.Assert(err, IsNil)
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, _, _, err = cl.BLPop(ctx, time.Minute, "l")
+	c.Check(err, Equals, context.DeadlineExceeded)
+	c.Check(cl.timeout, Equals, 10*time.Millisecond)
+}
@@ -3,7 +3,10 @@ package redis
 import (
 	"bufio"
 	"bytes"
+	"context"
 	"errors"
+	"io"
+	"log/slog"
 	"net"
 	"strconv"
 	"time"
@@ -24,11 +27,40 @@ var PipelineQueueEmptyError error = errors.New("pipeline queue empty")
 
 // Client describes a Redis client.
 type Client struct {
+	// KillBusyScripts makes the client send SCRIPT KILL when a command fails because
+	// the server is busy running a script, see ScriptBusyError.
+	KillBusyScripts bool
+	// AllowDestructive enables the helpers deleting whole databases, such as FlushDB.
+	// They fail with DestructiveError otherwise.
+	AllowDestructive bool
+	// EnableDebugCommands enables the DEBUG helpers, such as DebugSleep, which are meant
+	// for tests. They fail with DebugDisabledError otherwise.
+	EnableDebugCommands bool
+	// ValidateCommands makes the client check the number of arguments of each command
+	// before sending it, failing with an *ArityError instead. The command table is
+	// fetched with COMMAND on first use, see Commands.
+	ValidateCommands bool
+	// OnScript, if set, is called after each RunScript, FCall and FCallRO call with
+	// the name of the script or function, the time the call took and its reply.
+	OnScript func(name string, elapsed time.Duration, r *Reply)
+	// TrackLatency makes the client record the latency of the commands it sends, see
+	// LatencyReport.
+	TrackLatency bool
+	// Hook, if set, is called around each command and pipeline sent by the client.
+	Hook Hook
+	// OnConnect, if set, is called after each attempt of UpdateConfiguration to connect
+	// to a server, with the address and the error of the attempt.
+	OnConnect func(addr string, err error)
 	conn      net.Conn
 	timeout   time.Duration
 	reader    *bufio.Reader
 	pending   []*request
 	completed []*Reply
+	sent      []*request // requests of the completed replies
+	scripts   map[string]*Script
+	commands  map[string]*CommandInfo
+	version   Version
+	latency   latencyTracker
 }
 
 // Dial connects to the given Redis server with the given timeout.
@@ -59,18 +91,16 @@ func (c *Client) Close() error {
 }
 
 // Cmd calls the given Redis command.
+// Commands failing because the server is busy running a script return an error reply
+// with a *ScriptBusyError.
 func (c *Client) Cmd(cmd string, args ...interface{}) *Reply {
-	err := c.writeRequest(&request{cmd, args})
-	if err != nil {
-		return &Reply{Type: ErrorReply, Err: err}
-	}
-	return c.readReply()
+	return c.CmdContext(context.Background(), cmd, args...)
 }
 
 // Append adds the given call to the pipeline queue.
 // Use GetReply() to read the reply.
 func (c *Client) Append(cmd string, args ...interface{}) {
-	c.pending = append(c.pending, &request{cmd, args})
+	c.appendRequest(&request{cmd: cmd, args: args})
 }
 
 // GetReply returns the reply for the next request in the pipeline queue.
@@ -78,32 +108,147 @@ func (c *Client) Append(cmd string, args ...interface{}) {
 // if the pipeline queue is empty.
 func (c *Client) GetReply() *Reply {
 	if len(c.completed) > 0 {
-		r := c.completed[0]
-		c.completed = c.completed[1:]
-		return r
+		r, req := c.completed[0], c.sent[0]
+		c.completed, c.sent = c.completed[1:], c.sent[1:]
+		return c.fallback(req, r)
 	}
 	c.completed = nil
+	c.sent = nil
 
 	if len(c.pending) == 0 {
 		return &Reply{Type: ErrorReply, Err: PipelineQueueEmptyError}
 	}
 
-	nreqs := len(c.pending)
-	err := c.writeRequest(c.pending...)
+	reqs := c.pending
 	c.pending = nil
+	start := time.Now()
+	var replies []*Reply
+	c.instrument(reqs, func() []*Reply {
+		replies = c.flush(reqs)
+		return replies
+	})
+	r := replies[0]
+	c.completed, c.sent = replies[1:], reqs[1:]
+
+	// kill the script once all replies are read
+	var busy []*ScriptBusyError
+	for _, r := range append([]*Reply{r}, c.completed...) {
+		if e := busyError(r, start); e != nil {
+			busy = append(busy, e)
+		}
+	}
+	if len(busy) > 0 && c.killBusyScript(busy[0]) {
+		for _, e := range busy[1:] {
+			e.Killed = true
+		}
+	}
+
+	return c.fallback(reqs[0], r)
+}
+
+// UpdateConfiguration connects to the given server, authenticates with the given password
+// unless it is empty, and replaces the connection of the client with the new one.
+// The old connection is kept if connecting, authenticating or loading the registered
+// scripts fails.
+// Connection state, such as the selected database, is not carried over.
+func (c *Client) UpdateConfiguration(network, addr, password string) (err error) {
+	defer func() {
+		if err != nil {
+			logEvent(slog.LevelWarn, "redis: reconnection failed", "addr", addr, "error", err)
+		} else {
+			logEvent(slog.LevelInfo, "redis: reconnected", "addr", addr)
+		}
+		if c.OnConnect != nil {
+			c.OnConnect(addr, err)
+		}
+	}()
+	nc, err := DialTimeout(network, addr, c.timeout)
+	if err != nil {
+		return err
+	}
+	if password != "" {
+		if r := nc.Cmd("auth", password); r.Type == ErrorReply {
+			nc.Close()
+			return r.Err
+		}
+	}
+	nc.scripts = c.scripts
+	if err = nc.loadScripts(); err != nil {
+		nc.Close()
+		return err
+	}
+
+	old := c.conn
+	c.conn, c.reader = nc.conn, nc.reader
+	c.commands, c.version = nil, Version{}
+	old.Close()
+	return nil
+}
+
+// Reset resets the connection state on the server with RESET (Redis 6.2 or later): it
+// discards transactions and watched keys, leaves subscriptions and MONITOR mode, turns
+// CLIENT REPLY and client tracking off, selects database 0 and authenticates the
+// connection as the default user. Requests queued with Append and replies not read
+// with GetReply are discarded too.
+//
+// Reset lets callers reuse a connection in an unknown state instead of closing it.
+// Unless the server only has the default user, authenticate again afterwards.
+func (c *Client) Reset() error {
+	c.pending, c.completed, c.sent = nil, nil, nil
+	r := c.cmdSince(Version{6, 2, 0}, "reset")
+	if r.Type == ErrorReply {
+		return r.Err
+	}
+	if s, _ := r.Str(); s != "RESET" {
+		return ParseError
+	}
+	return nil
+}
+
+//* Private methods
+
+// cmd sends the given request and reads its reply.
+func (c *Client) cmd(req *request) *Reply {
+	start := time.Now()
+	err := c.writeRequest(req)
 	if err != nil {
 		return &Reply{Type: ErrorReply, Err: err}
 	}
 	r := c.readReply()
-	c.completed = make([]*Reply, nreqs-1)
-	for i := 0; i < nreqs-1; i++ {
-		c.completed[i] = c.readReply()
+	if e := busyError(r, start); e != nil {
+		c.killBusyScript(e)
 	}
-
 	return r
 }
 
-//* Private methods
+// flush sends the given requests and reads their replies. They all fail with the write
+// error if sending them fails.
+func (c *Client) flush(requests []*request) []*Reply {
+	replies := make([]*Reply, len(requests))
+	if err := c.writeRequest(requests...); err != nil {
+		for i := range replies {
+			replies[i] = &Reply{Type: ErrorReply, Err: err}
+		}
+		return replies
+	}
+	for i := range replies {
+		replies[i] = c.readReply()
+	}
+	return replies
+}
+
+func (c *Client) appendRequest(req *request) {
+	c.pending = append(c.pending, req)
+}
+
+// fallback calls the fallback of the given request if the given reply of the request
+// is a NOSCRIPT error. All replies of the pipeline have been read at that point.
+func (c *Client) fallback(req *request, r *Reply) *Reply {
+	if req.fallback != nil && isNoScript(r) {
+		return c.Cmd(req.fallback.cmd, req.fallback.args...)
+	}
+	return r
+}
 
 func (c *Client) setReadTimeout() {
 	if c.timeout != 0 {
@@ -123,8 +268,17 @@ func (c *Client) readReply() *Reply {
 }
 
 func (c *Client) writeRequest(requests ...*request) error {
+	if c.ValidateCommands {
+		if err := c.validateRequests(requests); err != nil {
+			return err
+		}
+	}
+	b, err := createRequest(requests...)
+	if err != nil {
+		return err
+	}
 	c.setWriteTimeout()
-	_, err := c.conn.Write(createRequest(requests...))
+	_, err = c.conn.Write(b)
 	if err != nil {
 		c.Close()
 		return err
@@ -141,7 +295,18 @@ func (c *Client) parse() (r *Reply) {
 		r.Err = err
 		return
 	}
+	defer func(line []byte) {
+		if r.Err == ParseError {
+			logEvent(slog.LevelWarn, "redis: protocol error", "addr", c.addr(), "line",
+				string(line))
+		}
+	}(b)
 
+	if len(b) < 3 || b[len(b)-2] != '\r' {
+		r.Type = ErrorReply
+		r.Err = ParseError
+		return
+	}
 	fb := b[0]
 	b = b[1 : len(b)-2] // get rid of the first byte and the trailing \r\n
 	switch fb {
@@ -170,7 +335,7 @@ func (c *Client) parse() (r *Reply) {
 	case '$':
 		// bulk reply
 		i, err := strconv.Atoi(string(b))
-		if err != nil {
+		if err != nil || i < -1 {
 			r.Type = ErrorReply
 			r.Err = ParseError
 		} else {
@@ -178,19 +343,13 @@ func (c *Client) parse() (r *Reply) {
 				// null bulk reply (key not found)
 				r.Type = NilReply
 			} else {
-				// bulk reply
-				ir := i + 2
-				br := make([]byte, ir)
-				rc := 0
-
-				for rc < ir {
-					n, err := c.reader.Read(br[rc:])
-					if err != nil {
-						c.Close()
-						r.Type = ErrorReply
-						r.Err = err
-					}
-					rc += n
+				// bulk reply, read by length as the payload may contain \r\n
+				br := make([]byte, i+2)
+				if _, err := io.ReadFull(c.reader, br); err != nil {
+					c.Close()
+					r.Type = ErrorReply
+					r.Err = err
+					return
 				}
 				r.Type = BulkReply
 				r.buf = br[0:i]
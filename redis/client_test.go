@@ -3,10 +3,66 @@ package redis
 import (
 	"bufio"
 	"bytes"
+	"fmt"
+	"io"
 	. "launchpad.net/gocheck"
+	"math/rand"
+	"net"
+	"sync"
 	"time"
 )
 
+// fakeConn is a net.Conn that replies with canned data and records writes.
+type fakeConn struct {
+	in  *bytes.Buffer
+	out bytes.Buffer
+}
+
+func (f *fakeConn) Read(b []byte) (int, error)         { return f.in.Read(b) }
+func (f *fakeConn) Write(b []byte) (int, error)        { return f.out.Write(b) }
+func (f *fakeConn) Close() error                       { return nil }
+func (f *fakeConn) LocalAddr() net.Addr                { return nil }
+func (f *fakeConn) RemoteAddr() net.Addr               { return nil }
+func (f *fakeConn) SetDeadline(t time.Time) error      { return nil }
+func (f *fakeConn) SetReadDeadline(t time.Time) error  { return nil }
+func (f *fakeConn) SetWriteDeadline(t time.Time) error { return nil }
+
+// fakeConns keeps the connections accepted by fake servers open, as unreferenced
+// connections are closed when garbage collected.
+var fakeConns struct {
+	sync.Mutex
+	l []net.Conn
+}
+
+// fakeServer starts a server that answers each accepted connection with
+// the next of the given raw replies and returns its address.
+func fakeServer(c *C, replies ...string) string {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	c.Assert(err, IsNil)
+	go func() {
+		defer l.Close()
+		for _, r := range replies {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			conn.Write([]byte(r))
+			fakeConns.Lock()
+			fakeConns.l = append(fakeConns.l, conn)
+			fakeConns.Unlock()
+		}
+	}()
+	return l.Addr().String()
+}
+
+// fakeClient returns a Client whose server replies with the given raw replies.
+func fakeClient(replies string) (*Client, *fakeConn) {
+	f := &fakeConn{in: bytes.NewBufferString(replies)}
+	c := &Client{conn: f}
+	c.reader = bufio.NewReaderSize(f, bufSize)
+	return c, f
+}
+
 type ClientSuite struct {
 	c *Client
 }
@@ -117,3 +173,85 @@ func (s *ClientSuite) TestParse(c *C) {
 	c.Check(r.Type, Equals, ErrorReply)
 	c.Check(r.Err, Equals, ParseError)
 }
+
+type ClientConfigSuite struct{}
+
+var _ = Suite(&ClientConfigSuite{})
+
+func (s *ClientConfigSuite) TestUpdateConfiguration(c *C) {
+	cl, f := fakeClient("$3\r\nold\r\n")
+	addr := fakeServer(c, "+OK\r\n$3\r\nnew\r\n")
+	c.Assert(cl.UpdateConfiguration("tcp", addr, "secret"), IsNil)
+	v, err := cl.Cmd("get", "foo").Str()
+	c.Assert(err, IsNil)
+	c.Check(v, Equals, "new")
+	c.Check(f.out.Len(), Equals, 0)
+
+	addr = fakeServer(c, "-ERR invalid password\r\n")
+	err = cl.UpdateConfiguration("tcp", addr, "wrong")
+	c.Check(err, ErrorMatches, "ERR invalid password")
+	c.Check(cl.UpdateConfiguration("tcp", "127.0.0.1:1", ""), NotNil)
+}
+
+func (s *ClientConfigSuite) TestOnConnect(c *C) {
+	cl, _ := fakeClient("")
+	var addrs []string
+	var errs []error
+	cl.OnConnect = func(addr string, err error) {
+		addrs = append(addrs, addr)
+		errs = append(errs, err)
+	}
+	addr := fakeServer(c, "+OK\r\n")
+	c.Assert(cl.UpdateConfiguration("tcp", addr, "secret"), IsNil)
+	c.Check(cl.UpdateConfiguration("tcp", "127.0.0.1:1", ""), NotNil)
+	c.Check(addrs, DeepEquals, []string{addr, "127.0.0.1:1"})
+	c.Check(errs[0], IsNil)
+	c.Check(errs[1], NotNil)
+}
+
+func (s *ClientConfigSuite) TestBinarySafe(c *C) {
+	rnd := rand.New(rand.NewSource(1))
+	for n := 0; n < 50; n++ {
+		key := make([]byte, rnd.Intn(16))
+		val := make([]byte, rnd.Intn(bufSize*2))
+		rnd.Read(key)
+		rnd.Read(val)
+		key = append(key, "\r\n\x00"...)
+		val = append([]byte("\x00\r\n$-1\r\n"), val...)
+
+		reply := fmt.Sprintf("$%d\r\n%s\r\n", len(val), val)
+		cl, f := fakeClient(reply + "*2\r\n" + reply + "$-1\r\n")
+		b, err := cl.Cmd("set", key, val).Bytes()
+		c.Assert(err, IsNil)
+		c.Check(b, DeepEquals, val)
+		c.Check(f.out.String(), Equals, fmt.Sprintf("*3\r\n$3\r\nset\r\n"+
+			"$%d\r\n%s\r\n$%d\r\n%s\r\n", len(key), key, len(val), val))
+
+		f.out.Reset()
+		l, err := cl.Cmd("mget", [][]byte{key, val}).ListBytes()
+		c.Assert(err, IsNil)
+		c.Check(l, DeepEquals, [][]byte{val, nil})
+		c.Check(f.out.String(), Equals, fmt.Sprintf("*3\r\n$4\r\nmget\r\n"+
+			"$%d\r\n%s\r\n$%d\r\n%s\r\n", len(key), key, len(val), val))
+	}
+
+	cl, _ := fakeClient("$10\r\nshort\r\n")
+	r := cl.Cmd("get", "k")
+	c.Check(r.Err, Equals, io.ErrUnexpectedEOF)
+	cl, _ = fakeClient("$-5\r\n")
+	c.Check(cl.Cmd("get", "k").Err, Equals, ParseError)
+	cl, _ = fakeClient("\n")
+	c.Check(cl.Cmd("get", "k").Err, Equals, ParseError)
+}
+
+func (s *ClientConfigSuite) TestReset(c *C) {
+	cl, f := fakeClient("+RESET\r\n-ERR unknown command 'reset'\r\n" + infoServer("6.0.0"))
+	cl.Append("multi")
+	cl.Append("set", "k", "v")
+	c.Assert(cl.Reset(), IsNil)
+	c.Check(f.out.String(), Equals, "*1\r\n$5\r\nreset\r\n")
+	c.Check(cl.GetReply().Err, Equals, PipelineQueueEmptyError)
+
+	err := cl.Reset()
+	c.Check(err, FitsTypeOf, &UnsupportedServerVersionError{})
+}
@@ -0,0 +1,172 @@
+package redis
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+//* Client management
+
+// ClientType is a type of connection, as used by CLIENT LIST and CLIENT KILL.
+type ClientType string
+
+const (
+	ClientAny     ClientType = ""
+	ClientNormal  ClientType = "normal"
+	ClientMaster  ClientType = "master"
+	ClientReplica ClientType = "replica"
+	ClientPubSub  ClientType = "pubsub"
+)
+
+// ClientInfo describes a connection to the server, as reported by CLIENT LIST.
+type ClientInfo struct {
+	ID      int64
+	Addr    string
+	LAddr   string // Local address of the server, Redis 6.2 or later
+	Name    string
+	User    string // Redis 6.0 or later
+	Age     time.Duration
+	Idle    time.Duration
+	Flags   string // See the CLIENT LIST documentation
+	DB      int
+	Sub     int               // Channel subscriptions
+	PSub    int               // Pattern subscriptions
+	Multi   int               // Commands queued in MULTI, -1 outside of it
+	Cmd     string            // Last command run
+	LibName string            // Redis 7.2 or later
+	LibVer  string            // Redis 7.2 or later
+	Fields  map[string]string // All fields, including the ones above
+}
+
+// ClientList returns the connections of the given type, or all of them for ClientAny.
+func (c *Client) ClientList(t ClientType) ([]ClientInfo, error) {
+	var args []interface{}
+	if t != ClientAny {
+		args = append(args, "type", string(t))
+	}
+	s, err := c.Cmd("client", "list", args).Str()
+	if err != nil {
+		return nil, err
+	}
+	return parseClientList(s)
+}
+
+// ClientKillFilter selects the connections closed by ClientKill. Connections must
+// match all of the set fields. The connection of the calling client is skipped
+// unless KillSelf is set.
+type ClientKillFilter struct {
+	ID       int64
+	Addr     string
+	LAddr    string
+	Type     ClientType
+	User     string
+	KillSelf bool
+}
+
+func (f *ClientKillFilter) args() []interface{} {
+	var args []interface{}
+	if f.ID != 0 {
+		args = append(args, "id", f.ID)
+	}
+	if f.Addr != "" {
+		args = append(args, "addr", f.Addr)
+	}
+	if f.LAddr != "" {
+		args = append(args, "laddr", f.LAddr)
+	}
+	if f.Type != ClientAny {
+		args = append(args, "type", string(f.Type))
+	}
+	if f.User != "" {
+		args = append(args, "user", f.User)
+	}
+	if args != nil && f.KillSelf {
+		args = append(args, "skipme", "no")
+	}
+	return args
+}
+
+// ClientKill closes the connections matching the given filter and returns how many
+// were closed.
+func (c *Client) ClientKill(filter *ClientKillFilter) (int64, error) {
+	args := filter.args()
+	if args == nil {
+		return 0, errors.New("empty client kill filter")
+	}
+	return c.Cmd("client", "kill", args).Int64()
+}
+
+// ClientPause suspends the processing of commands from normal and pub/sub clients
+// for the given duration. If writes is set, only commands that may write are
+// suspended (Redis 6.2 or later).
+func (c *Client) ClientPause(d time.Duration, writes bool) error {
+	args := []interface{}{"pause", int64(d / time.Millisecond)}
+	if writes {
+		args = append(args, "write")
+	}
+	return c.Cmd("client", args).Err
+}
+
+// ClientUnpause resumes the processing of commands suspended by ClientPause
+// (Redis 6.2 or later).
+func (c *Client) ClientUnpause() error {
+	return c.cmdSince(Version{6, 2, 0}, "client", "unpause").Err
+}
+
+// ClientNoEvict sets whether the connection of the calling client is excluded
+// from client eviction (Redis 7.0 or later).
+func (c *Client) ClientNoEvict(on bool) error {
+	mode := "off"
+	if on {
+		mode = "on"
+	}
+	return c.cmdSince(Version{7, 0, 0}, "client", "no-evict", mode).Err
+}
+
+// parseClientList parses the output of CLIENT LIST, one connection per line.
+func parseClientList(s string) ([]ClientInfo, error) {
+	var clients []ClientInfo
+	for _, line := range strings.Split(s, "\n") {
+		line = strings.TrimRight(line, "\r")
+		if line == "" {
+			continue
+		}
+		f := map[string]string{}
+		for _, kv := range strings.Fields(line) {
+			if i := strings.IndexByte(kv, '='); i >= 0 {
+				f[kv[:i]] = kv[i+1:]
+			}
+		}
+		ci := ClientInfo{
+			Addr:    f["addr"],
+			LAddr:   f["laddr"],
+			Name:    f["name"],
+			User:    f["user"],
+			Flags:   f["flags"],
+			Cmd:     f["cmd"],
+			LibName: f["lib-name"],
+			LibVer:  f["lib-ver"],
+			Fields:  f,
+		}
+		var age, idle, db, sub, psub, multi int64
+		ints := map[string]*int64{
+			"id": &ci.ID, "age": &age, "idle": &idle, "db": &db,
+			"sub": &sub, "psub": &psub, "multi": &multi,
+		}
+		for k, p := range ints {
+			if v, ok := f[k]; ok {
+				i, err := strconv.ParseInt(v, 10, 64)
+				if err != nil {
+					return nil, ParseError
+				}
+				*p = i
+			}
+		}
+		ci.Age, ci.Idle = time.Duration(age)*time.Second, time.Duration(idle)*time.Second
+		ci.DB, ci.Sub, ci.PSub, ci.Multi = int(db), int(sub), int(psub), int(multi)
+		clients = append(clients, ci)
+	}
+	return clients, nil
+}
@@ -0,0 +1,73 @@
+package redis
+
+import (
+	"fmt"
+	. "launchpad.net/gocheck"
+	"time"
+)
+
+type ClientsSuite struct{}
+
+var _ = Suite(&ClientsSuite{})
+
+func (s *ClientsSuite) TestClientList(c *C) {
+	list := "id=3 addr=127.0.0.1:52555 laddr=127.0.0.1:6379 fd=8 name=worker age=60 idle=2 " +
+		"flags=N db=1 sub=0 psub=0 multi=-1 cmd=client|list user=default lib-name=radix\n" +
+		"id=4 addr=10.0.0.5:6000 fd=9 name= age=1 idle=1 flags=P db=0 sub=2 psub=1 cmd=subscribe\n"
+	cl, f := fakeClient(fmt.Sprintf("$%d\r\n%s\r\n", len(list), list))
+	l, err := cl.ClientList(ClientNormal)
+	c.Assert(err, IsNil)
+	c.Check(f.out.String(), Equals,
+		"*4\r\n$6\r\nclient\r\n$4\r\nlist\r\n$4\r\ntype\r\n$6\r\nnormal\r\n")
+	c.Assert(l, HasLen, 2)
+	c.Check(l[0].ID, Equals, int64(3))
+	c.Check(l[0].Addr, Equals, "127.0.0.1:52555")
+	c.Check(l[0].LAddr, Equals, "127.0.0.1:6379")
+	c.Check(l[0].Name, Equals, "worker")
+	c.Check(l[0].User, Equals, "default")
+	c.Check(l[0].Age, Equals, time.Minute)
+	c.Check(l[0].Idle, Equals, 2*time.Second)
+	c.Check(l[0].Flags, Equals, "N")
+	c.Check(l[0].DB, Equals, 1)
+	c.Check(l[0].Multi, Equals, -1)
+	c.Check(l[0].Cmd, Equals, "client|list")
+	c.Check(l[0].LibName, Equals, "radix")
+	c.Check(l[0].Fields["fd"], Equals, "8")
+	c.Check(l[1].Name, Equals, "")
+	c.Check(l[1].Sub, Equals, 2)
+	c.Check(l[1].PSub, Equals, 1)
+
+	_, err = parseClientList("id=x addr=1\n")
+	c.Check(err, Equals, ParseError)
+}
+
+func (s *ClientsSuite) TestClientKill(c *C) {
+	cl, f := fakeClient(":2\r\n:1\r\n")
+	n, err := cl.ClientKill(&ClientKillFilter{Type: ClientPubSub, User: "app"})
+	c.Assert(err, IsNil)
+	c.Check(n, Equals, int64(2))
+	c.Check(f.out.String(), Equals, "*6\r\n$6\r\nclient\r\n$4\r\nkill\r\n"+
+		"$4\r\ntype\r\n$6\r\npubsub\r\n$4\r\nuser\r\n$3\r\napp\r\n")
+	f.out.Reset()
+	_, err = cl.ClientKill(&ClientKillFilter{ID: 7, Addr: "1.2.3.4:5", LAddr: "l:1",
+		KillSelf: true})
+	c.Assert(err, IsNil)
+	c.Check(f.out.String(), Equals, "*10\r\n$6\r\nclient\r\n$4\r\nkill\r\n"+
+		"$2\r\nid\r\n$1\r\n7\r\n$4\r\naddr\r\n$9\r\n1.2.3.4:5\r\n$5\r\nladdr\r\n$3\r\nl:1\r\n"+
+		"$6\r\nskipme\r\n$2\r\nno\r\n")
+	_, err = cl.ClientKill(&ClientKillFilter{KillSelf: true})
+	c.Check(err, ErrorMatches, "empty client kill filter")
+}
+
+func (s *ClientsSuite) TestClientPause(c *C) {
+	cl, f := fakeClient("+OK\r\n+OK\r\n+OK\r\n+OK\r\n")
+	c.Check(cl.ClientPause(1500*time.Millisecond, true), IsNil)
+	c.Check(cl.ClientPause(time.Second, false), IsNil)
+	c.Check(cl.ClientUnpause(), IsNil)
+	c.Check(cl.ClientNoEvict(true), IsNil)
+	c.Check(f.out.String(), Equals,
+		"*4\r\n$6\r\nclient\r\n$5\r\npause\r\n$4\r\n1500\r\n$5\r\nwrite\r\n"+
+			"*3\r\n$6\r\nclient\r\n$5\r\npause\r\n$4\r\n1000\r\n"+
+			"*2\r\n$6\r\nclient\r\n$7\r\nunpause\r\n"+
+			"*3\r\n$6\r\nclient\r\n$8\r\nno-evict\r\n$2\r\non\r\n")
+}
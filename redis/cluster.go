@@ -0,0 +1,853 @@
+package redis
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"math/rand"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+)
+
+//* Cluster
+
+const (
+	numSlots     = 16384
+	maxRedirects = 16
+)
+
+var NoClusterNodeError error = errors.New("no cluster node available")
+var SlotNotServedError error = errors.New("hash slot is not served by any node")
+var TooManyRedirectsError error = errors.New("too many cluster redirects")
+
+// keyless holds the commands that are not routed by a key.
+var keyless = map[string]bool{
+	"auth": true, "bgrewriteaof": true, "bgsave": true, "client": true, "cluster": true,
+	"command": true, "config": true, "dbsize": true, "debug": true, "discard": true,
+	"echo": true, "exec": true, "flushall": true, "flushdb": true, "function": true,
+	"info": true, "lastsave": true, "latency": true, "monitor": true, "multi": true,
+	"ping": true, "psubscribe": true, "publish": true, "pubsub": true, "punsubscribe": true,
+	"quit": true, "randomkey": true, "readonly": true, "readwrite": true, "role": true,
+	"save": true, "script": true, "select": true, "shutdown": true, "slowlog": true,
+	"subscribe": true, "time": true, "unsubscribe": true, "unwatch": true, "wait": true,
+}
+
+// readOnly holds the commands that may be served by replicas.
+var readOnly = map[string]bool{
+	"bitcount": true, "bitfield_ro": true, "bitpos": true, "dump": true, "eval_ro": true,
+	"evalsha_ro": true, "exists": true, "fcall_ro": true, "geodist": true, "geohash": true,
+	"geopos": true, "georadius_ro": true, "georadiusbymember_ro": true, "geosearch": true,
+	"get": true, "getbit": true, "getrange": true, "hexists": true, "hget": true,
+	"hgetall": true, "hkeys": true, "hlen": true, "hmget": true, "hrandfield": true,
+	"hscan": true, "hstrlen": true, "hvals": true, "lindex": true, "llen": true, "lpos": true,
+	"lrange": true, "mget": true, "memory": true, "object": true, "pfcount": true,
+	"pttl": true, "scard": true, "sdiff": true, "sinter": true, "sintercard": true,
+	"sismember": true, "smembers": true, "smismember": true, "sort_ro": true,
+	"srandmember": true, "sscan": true, "strlen": true, "substr": true, "sunion": true,
+	"ttl": true, "type": true, "xinfo": true, "xlen": true, "xpending": true, "xrange": true,
+	"xread": true, "xrevrange": true, "zcard": true, "zcount": true, "zdiff": true,
+	"zinter": true, "zintercard": true, "zlexcount": true, "zmscore": true,
+	"zrandmember": true, "zrange": true, "zrangebylex": true, "zrangebyscore": true,
+	"zrank": true, "zrevrange": true, "zrevrangebylex": true, "zrevrangebyscore": true,
+	"zrevrank": true, "zscan": true, "zscore": true, "zunion": true,
+}
+
+/*
+ReadPolicy describes where a ClusterClient sends read-only commands.
+
+Possible values are:
+
+ReadMaster -- the master of the slot (default)
+ReadPreferReplica -- a random replica of the slot, or the master if it has none
+ReadRandom -- a random node among the master and replicas of the slot
+ReadNearest -- the node of the slot with the lowest round-trip time
+ReadSameZone -- like ReadNearest among the nodes in Zone (all nodes if none), preferring replicas
+*/
+type ReadPolicy uint8
+
+const (
+	ReadMaster ReadPolicy = iota
+	ReadPreferReplica
+	ReadRandom
+	ReadNearest
+	ReadSameZone
+)
+
+// CrossSlotError is returned for multi-key commands whose keys do not all belong
+// to the same hash slot (ClusterClient) or server (RingClient) as the first key.
+type CrossSlotError struct {
+	Cmd  string   // Command name
+	Key  string   // First key of the command
+	Keys []string // Keys that belong elsewhere
+}
+
+func (e *CrossSlotError) Error() string {
+	q := make([]string, len(e.Keys))
+	for i, k := range e.Keys {
+		q[i] = strconv.Quote(k)
+	}
+	return fmt.Sprintf("%s: keys %s are not in the same shard as %q",
+		strings.ToUpper(e.Cmd), strings.Join(q, ", "), e.Key)
+}
+
+// slotRange describes a range of hash slots and the nodes serving it.
+type slotRange struct {
+	start, end int
+	addrs      []string // master first, then replicas
+}
+
+// ClusterClient describes a Redis Cluster client.
+// It keeps a Client for each cluster node it has talked to and routes each command
+// to the master serving the hash slot of the command's key.
+// Read-only commands can be routed to replicas by setting ReadPolicy.
+// Round-trip times used by ReadNearest and ReadSameZone are measured when the slot map
+// is reloaded.
+//
+// The slot map is reloaded before the next command whenever a MOVED redirection,
+// a READONLY or CLUSTERDOWN error or a connection failure is seen, and after
+// RefreshInterval has passed since the last reload. Commands failing with
+// READONLY or CLUSTERDOWN are retried according to Retry.
+// Like Client, ClusterClient is not safe for concurrent use.
+type ClusterClient struct {
+	RefreshInterval time.Duration     // Periodic slot map reload interval, 0 disables
+	ReadPolicy      ReadPolicy        // Routing of read-only commands
+	Zone            string            // Zone of the client, used by ReadSameZone
+	Zones           map[string]string // Zones of the nodes by address, used by ReadSameZone
+	Retry           RetryPolicy       // Retrying of commands failing with topology errors
+	Quarantine      QuarantinePolicy
+	network         string
+	timeout         time.Duration
+	clients         map[string]*Client
+	replicas        map[string]bool
+	readonly        map[string]bool // replicas whose connection is in READONLY mode
+	rtt             map[string]time.Duration
+	health          quarantine
+	stats           nodeStats
+	slots           [numSlots]*slotRange
+	stale           bool
+	refreshed       time.Time
+	pending         []*request
+	completed       []*Reply
+}
+
+// DialClusterTimeout connects to the Redis Cluster that the given node belongs to
+// with the given timeout and loads the slot map of the cluster.
+func DialClusterTimeout(network, addr string, timeout time.Duration) (*ClusterClient, error) {
+	cc := &ClusterClient{
+		RefreshInterval: time.Minute,
+		Retry:           DefaultRetryPolicy,
+		Quarantine:      DefaultQuarantinePolicy,
+		network:         network,
+		timeout:         timeout,
+		clients:         map[string]*Client{},
+		replicas:        map[string]bool{},
+		readonly:        map[string]bool{},
+		rtt:             map[string]time.Duration{},
+		health:          quarantine{},
+		stats:           nodeStats{},
+	}
+	if err := cc.refreshFrom(addr); err != nil {
+		cc.Close()
+		return nil, err
+	}
+	return cc, nil
+}
+
+// DialCluster connects to the Redis Cluster that the given node belongs to.
+func DialCluster(network, addr string) (*ClusterClient, error) {
+	return DialClusterTimeout(network, addr, time.Duration(0))
+}
+
+//* Public methods
+
+// Close closes the connections to all cluster nodes.
+func (cc *ClusterClient) Close() error {
+	var err error
+	for addr, c := range cc.clients {
+		if e := c.Close(); e != nil {
+			err = e
+		}
+		cc.drop(addr)
+	}
+	return err
+}
+
+// Cmd calls the given Redis command on the node serving the command's key.
+// Commands without a key are sent to an arbitrary node.
+// MGET, MSET, DEL, UNLINK, EXISTS and TOUCH with keys in several hash slots are split
+// by slot and executed in parallel, with the replies merged in key order; note that
+// a split MSET is not atomic. Other multi-key commands whose keys span several hash
+// slots return an error reply with a *CrossSlotError without contacting the cluster.
+// MOVED and ASK redirections are followed up to a limit, after which
+// an error reply with TooManyRedirectsError is returned.
+func (cc *ClusterClient) Cmd(cmd string, args ...interface{}) *Reply {
+	if err := checkKeys(cmd, args, slotOf); err != nil {
+		if reqs, merge, ok := splitKeys(cmd, args, slotOf); ok {
+			return merge(cc.pipeline(reqs))
+		}
+		return &Reply{Type: ErrorReply, Err: err}
+	}
+	return cc.do(cmd, args, nil)
+}
+
+// Append adds the given call to the pipeline queue.
+// Use GetReply() to read the reply.
+func (cc *ClusterClient) Append(cmd string, args ...interface{}) {
+	cc.appendRequest(&request{cmd: cmd, args: args})
+}
+
+// GetReply returns the reply for the next request in the pipeline queue.
+// Error reply with PipelineQueueEmptyError is returned,
+// if the pipeline queue is empty.
+//
+// The queued requests are split by node and the pipeline of each node is
+// executed concurrently. Redirected requests are retried one by one afterwards.
+func (cc *ClusterClient) GetReply() *Reply {
+	if len(cc.completed) > 0 {
+		r := cc.completed[0]
+		cc.completed = cc.completed[1:]
+		return r
+	}
+	cc.completed = nil
+
+	if len(cc.pending) == 0 {
+		return &Reply{Type: ErrorReply, Err: PipelineQueueEmptyError}
+	}
+
+	reqs := cc.pending
+	cc.pending = nil
+	replies := cc.pipeline(reqs)
+	cc.completed = replies[1:]
+	return replies[0]
+}
+
+// Refresh reloads the slot map from the first cluster node that answers.
+// Connections to nodes that are no longer part of the cluster are closed.
+func (cc *ClusterClient) Refresh() error {
+	cc.stale = false
+	cc.refreshed = time.Now()
+	err := NoClusterNodeError
+	for _, addr := range cc.nodes() {
+		if err = cc.refreshFrom(addr); err == nil {
+			return nil
+		}
+	}
+	return err
+}
+
+//* Private methods
+
+func (cc *ClusterClient) appendRequest(req *request) {
+	cc.pending = append(cc.pending, req)
+}
+
+// nodes returns the addresses of all known cluster nodes.
+func (cc *ClusterClient) nodes() []string {
+	var addrs []string
+	seen := map[string]bool{}
+	for _, r := range cc.slots {
+		if r == nil {
+			continue
+		}
+		for _, addr := range r.addrs {
+			if !seen[addr] {
+				seen[addr] = true
+				addrs = append(addrs, addr)
+			}
+		}
+	}
+	for addr := range cc.clients {
+		if !seen[addr] {
+			seen[addr] = true
+			addrs = append(addrs, addr)
+		}
+	}
+	return addrs
+}
+
+// masters returns the addresses of the masters in the slot map in slot order.
+func (cc *ClusterClient) masters() []string {
+	var addrs []string
+	seen := map[string]bool{}
+	var prev *slotRange
+	for _, r := range cc.slots {
+		if r == nil || r == prev {
+			continue
+		}
+		prev = r
+		if !seen[r.addrs[0]] {
+			seen[r.addrs[0]] = true
+			addrs = append(addrs, r.addrs[0])
+		}
+	}
+	return addrs
+}
+
+// drop forgets the connection to the given node.
+func (cc *ClusterClient) drop(addr string) {
+	delete(cc.clients, addr)
+	delete(cc.readonly, addr)
+}
+
+// client returns the Client for the given node, connecting to it if needed.
+func (cc *ClusterClient) client(addr string) (*Client, error) {
+	if c, ok := cc.clients[addr]; ok {
+		return c, nil
+	}
+	c, err := DialTimeout(cc.network, addr, cc.timeout)
+	cc.stats.dialed(addr, err)
+	if err != nil {
+		logEvent(slog.LevelWarn, "redis: connection to node failed", "addr", addr, "error", err)
+		return nil, err
+	}
+	cc.clients[addr] = c
+	return c, nil
+}
+
+// addrFor returns the address of the node that should receive the given command.
+func (cc *ClusterClient) addrFor(cmd string, args []interface{}) (string, error) {
+	key, ok := commandKey(cmd, args)
+	if !ok {
+		for addr := range cc.clients {
+			return addr, nil
+		}
+		for _, addr := range cc.nodes() {
+			return addr, nil
+		}
+		return "", NoClusterNodeError
+	}
+	r := cc.slots[keySlot(key)]
+	if r == nil {
+		return "", SlotNotServedError
+	}
+	ro := readOnly[strings.ToLower(cmd)]
+	addr := r.addrs[0]
+	if cc.ReadPolicy != ReadMaster && ro {
+		addr = cc.readAddr(r.addrs)
+	}
+	if ro && cc.health.blocked(addr) {
+		for _, a := range r.addrs {
+			if !cc.health.blocked(a) {
+				return a, nil
+			}
+		}
+	}
+	return addr, nil
+}
+
+// readAddr picks the node of the given master and replicas for a read-only command.
+func (cc *ClusterClient) readAddr(addrs []string) string {
+	switch cc.ReadPolicy {
+	case ReadPreferReplica:
+		if len(addrs) > 1 {
+			return addrs[1+rand.Intn(len(addrs)-1)]
+		}
+	case ReadRandom:
+		return addrs[rand.Intn(len(addrs))]
+	case ReadNearest:
+		return cc.nearest(addrs)
+	case ReadSameZone:
+		var local []string
+		for _, addr := range addrs {
+			if cc.Zones[addr] == cc.Zone {
+				local = append(local, addr)
+			}
+		}
+		if len(local) == 0 {
+			local = addrs
+		}
+		// prefer replicas
+		if len(local) > 1 && local[0] == addrs[0] {
+			local = local[1:]
+		}
+		return cc.nearest(local)
+	}
+	return addrs[0]
+}
+
+// nearest returns the node with the lowest measured round-trip time,
+// or a random node if none has been measured.
+func (cc *ClusterClient) nearest(addrs []string) string {
+	best := ""
+	for _, addr := range addrs {
+		if rtt, ok := cc.rtt[addr]; ok && (best == "" || rtt < cc.rtt[best]) {
+			best = addr
+		}
+	}
+	if best == "" {
+		return addrs[rand.Intn(len(addrs))]
+	}
+	return best
+}
+
+// conn returns the Client for the given node, enabling READONLY mode on replicas.
+func (cc *ClusterClient) conn(addr string) (*Client, error) {
+	if cc.health.blocked(addr) {
+		return nil, QuarantinedError
+	}
+	c, err := cc.client(addr)
+	if err != nil {
+		cc.health.failed(cc.Quarantine, addr)
+		cc.stale = true
+		return nil, err
+	}
+	if cc.replicas[addr] && !cc.readonly[addr] {
+		if r := c.Cmd("readonly"); r.Type == ErrorReply {
+			return nil, r.Err
+		}
+		cc.readonly[addr] = true
+	}
+	return c, nil
+}
+
+// observe records the health and statistics of the given node from the given reply,
+// which took d to arrive, and marks the slot map stale on connection failures and
+// topology errors.
+func (cc *ClusterClient) observe(addr string, c *Client, r *Reply, d time.Duration) {
+	cc.stats.record(addr, r, d)
+	if r.Type != ErrorReply || !isConnError(r.Err) {
+		cc.health.succeeded(addr)
+	}
+	if r.Type != ErrorReply {
+		return
+	}
+	if isConnError(r.Err) {
+		logEvent(slog.LevelWarn, "redis: dropped connection to node", "addr", addr, "error",
+			r.Err)
+		cc.health.failed(cc.Quarantine, addr)
+		c.Close()
+		if cc.clients[addr] == c {
+			cc.drop(addr)
+		}
+		cc.stale = true
+	} else if isTopologyError(r) {
+		cc.stale = true
+	}
+}
+
+// cmdAt calls the given command on the given node, preceded by ASKING if asking is set.
+func (cc *ClusterClient) cmdAt(addr string, asking bool, cmd string, args []interface{}) *Reply {
+	c, err := cc.conn(addr)
+	if err != nil {
+		return &Reply{Type: ErrorReply, Err: err}
+	}
+
+	var r *Reply
+	start := time.Now()
+	if asking {
+		c.Append("asking")
+		c.Append(cmd, args...)
+		c.GetReply()
+		r = c.GetReply()
+	} else {
+		r = c.Cmd(cmd, args...)
+	}
+	cc.observe(addr, c, r, time.Since(start))
+	return r
+}
+
+// do calls the given command, retrying it on topology errors according to cc.Retry.
+// If r is not nil, it is the reply of the first attempt.
+func (cc *ClusterClient) do(cmd string, args []interface{}, r *Reply) *Reply {
+	for i := 0; ; i++ {
+		if r == nil {
+			cc.maybeRefresh()
+			addr, err := cc.addrFor(cmd, args)
+			if err != nil {
+				return &Reply{Type: ErrorReply, Err: err}
+			}
+			r = cc.follow(addr, cmd, args, cc.cmdAt(addr, false, cmd, args))
+		}
+		if !isTopologyError(r) || !cc.Retry.wait(i) {
+			return r
+		}
+		logEvent(slog.LevelInfo, "redis: retrying command", "cmd", cmd, "attempt", i+1,
+			"error", r.Err)
+		cc.stale = true
+		r = nil
+	}
+}
+
+// follow follows the MOVED and ASK redirections of the given reply of the node at the
+// given address to the given command.
+func (cc *ClusterClient) follow(addr, cmd string, args []interface{}, r *Reply) *Reply {
+	for i := 0; i < maxRedirects; i++ {
+		host, _, _ := net.SplitHostPort(addr)
+		ask, slot, to, ok := parseRedirect(r, host)
+		if !ok {
+			return r
+		}
+		if !ask {
+			cc.moved(slot, to)
+		}
+		addr = to
+		r = cc.cmdAt(addr, ask, cmd, args)
+	}
+	if _, _, _, ok := parseRedirect(r, ""); ok {
+		return &Reply{Type: ErrorReply, Err: TooManyRedirectsError}
+	}
+	return r
+}
+
+// pipeline executes the given requests with one pipeline per node
+// and returns the replies in the order of the requests.
+func (cc *ClusterClient) pipeline(reqs []*request) []*Reply {
+	cc.maybeRefresh()
+	replies := make([]*Reply, len(reqs))
+	batches := map[string][]int{}
+	for i, req := range reqs {
+		if err := checkKeys(req.cmd, req.args, slotOf); err != nil {
+			if sub, merge, ok := splitKeys(req.cmd, req.args, slotOf); ok {
+				// the requests queued before run first
+				cc.sendBatches(reqs, replies, batches)
+				batches = map[string][]int{}
+				replies[i] = merge(cc.pipeline(sub))
+			} else {
+				replies[i] = &Reply{Type: ErrorReply, Err: err}
+			}
+			continue
+		}
+		addr, err := cc.addrFor(req.cmd, req.args)
+		if err != nil {
+			replies[i] = &Reply{Type: ErrorReply, Err: err}
+			continue
+		}
+		batches[addr] = append(batches[addr], i)
+	}
+	cc.sendBatches(reqs, replies, batches)
+	return replies
+}
+
+// sendBatches sends the given batches of requests, given by index per node, and stores
+// the replies at the index of their request, following redirections.
+func (cc *ClusterClient) sendBatches(reqs []*request, replies []*Reply,
+	batches map[string][]int) {
+	// connect sequentially, as only the node pipelines may run concurrently
+	clients := map[string]*Client{}
+	for addr, idx := range batches {
+		c, err := cc.conn(addr)
+		if err != nil {
+			for _, i := range idx {
+				replies[i] = &Reply{Type: ErrorReply, Err: err}
+			}
+			delete(batches, addr)
+			continue
+		}
+		clients[addr] = c
+	}
+
+	elapsed := runBatches(reqs, replies, batches, clients)
+	for addr, idx := range batches {
+		for _, i := range idx {
+			cc.observe(addr, clients[addr], replies[i], elapsed[i])
+			replies[i] = cc.do(reqs[i].cmd, reqs[i].args,
+				cc.follow(addr, reqs[i].cmd, reqs[i].args, replies[i]))
+			if fb := reqs[i].fallback; fb != nil && isNoScript(replies[i]) {
+				replies[i] = cc.do(fb.cmd, fb.args, nil)
+			}
+		}
+	}
+}
+
+// maybeRefresh reloads the slot map if it is stale or RefreshInterval has passed.
+func (cc *ClusterClient) maybeRefresh() {
+	if cc.stale || (cc.RefreshInterval > 0 && time.Since(cc.refreshed) >= cc.RefreshInterval) {
+		cc.Refresh()
+	}
+}
+
+// moved records that the given slot is now served by the given master
+// and marks the rest of the slot map stale.
+func (cc *ClusterClient) moved(slot int, addr string) {
+	cc.stale = true
+	if r := cc.slots[slot]; r != nil && r.addrs[0] == addr {
+		return
+	}
+	cc.slots[slot] = &slotRange{start: slot, end: slot, addrs: []string{addr}}
+}
+
+// refreshFrom loads the slot map from the given node.
+func (cc *ClusterClient) refreshFrom(addr string) error {
+	c, err := cc.client(addr)
+	if err != nil {
+		return err
+	}
+	host, _, _ := net.SplitHostPort(addr)
+	csr, err := parseClusterSlots(c.Cmd("cluster", "slots"), host)
+	if err != nil {
+		return err
+	}
+	ranges := make([]*slotRange, len(csr))
+	for i, r := range csr {
+		if len(r.Nodes) == 0 {
+			return ParseError
+		}
+		ranges[i] = &slotRange{start: r.Start, end: r.End}
+		for _, n := range r.Nodes {
+			ranges[i].addrs = append(ranges[i].addrs, n.Addr)
+		}
+	}
+	var slots [numSlots]*slotRange
+	known := map[string]bool{}
+	replicas := map[string]bool{}
+	for _, r := range ranges {
+		for s := r.start; s <= r.end; s++ {
+			slots[s] = r
+		}
+		for _, addr := range r.addrs {
+			known[addr] = true
+		}
+		for _, addr := range r.addrs[1:] {
+			replicas[addr] = true
+		}
+	}
+	for _, r := range ranges {
+		delete(replicas, r.addrs[0])
+	}
+	cc.slots = slots
+	cc.replicas = replicas
+	cc.refreshed = time.Now()
+
+	for addr, c := range cc.clients {
+		if !known[addr] {
+			c.Close()
+			cc.drop(addr)
+		}
+	}
+	for addr := range cc.rtt {
+		if !known[addr] {
+			delete(cc.rtt, addr)
+		}
+	}
+	for addr := range cc.stats {
+		if !known[addr] {
+			delete(cc.stats, addr)
+		}
+	}
+	if cc.ReadPolicy == ReadNearest || cc.ReadPolicy == ReadSameZone {
+		for addr := range known {
+			cc.measure(addr)
+		}
+	}
+	return nil
+}
+
+// measure records the round-trip time of a PING to the given node.
+func (cc *ClusterClient) measure(addr string) {
+	c, err := cc.client(addr)
+	if err != nil {
+		return
+	}
+	start := time.Now()
+	if r := c.Cmd("ping"); r.Type != ErrorReply {
+		cc.rtt[addr] = time.Since(start)
+	}
+}
+
+// isConnError returns true if the given reply error is a connection failure
+// rather than an error sent by the server.
+func isConnError(err error) bool {
+	if err == io.EOF || err == io.ErrUnexpectedEOF {
+		return true
+	}
+	_, ok := err.(net.Error)
+	return ok
+}
+
+// parseRedirect parses a MOVED or ASK error reply. An address without a host, sent when
+// the node does not know it, gets the given host of the node that sent the reply.
+func parseRedirect(r *Reply, host string) (ask bool, slot int, addr string, ok bool) {
+	if r.Type != ErrorReply || r.Err == nil {
+		return
+	}
+	f := strings.Fields(r.Err.Error())
+	if len(f) != 3 || (f[0] != "MOVED" && f[0] != "ASK") {
+		return
+	}
+	slot, err := strconv.Atoi(f[1])
+	if err != nil || slot < 0 || slot >= numSlots {
+		return
+	}
+	addr = f[2]
+	if strings.HasPrefix(addr, ":") {
+		addr = host + addr
+	}
+	return f[0] == "ASK", slot, addr, true
+}
+
+// commandKey returns the key that the given command is routed by, if any.
+func commandKey(cmd string, args []interface{}) ([]byte, bool) {
+	cmd = strings.ToLower(cmd)
+	if keyless[cmd] {
+		return nil, false
+	}
+	args = flattenArgs(args)
+	i := 0
+	switch cmd {
+	case "eval", "evalsha", "eval_ro", "evalsha_ro", "fcall", "fcall_ro":
+		// script numkeys key [key ...]
+		if len(args) < 3 || string(argBytes(args[1])) == "0" {
+			return nil, false
+		}
+		i = 2
+	case "memory", "object", "xinfo", "xgroup":
+		// subcommand key
+		i = 1
+	case "xread", "xreadgroup":
+		// ... STREAMS key [key ...] id [id ...]
+		i = -1
+		for j, arg := range args {
+			if strings.ToLower(string(argBytes(arg))) == "streams" {
+				i = j + 1
+				break
+			}
+		}
+	}
+	if i < 0 || i >= len(args) {
+		return nil, false
+	}
+	return argBytes(args[i]), true
+}
+
+// commandKeys returns all keys of the given multi-key command,
+// or the key the command is routed by for other commands.
+func commandKeys(cmd string, args []interface{}) [][]byte {
+	cmd = strings.ToLower(cmd)
+	args = flattenArgs(args)
+	// numkeys returns the keys of a "numkeys key [key ...]" argument list.
+	numkeys := func(args []interface{}) []interface{} {
+		if len(args) == 0 {
+			return nil
+		}
+		n, err := strconv.Atoi(string(argBytes(args[0])))
+		if err != nil || n < 0 {
+			return nil
+		}
+		if n > len(args)-1 {
+			n = len(args) - 1
+		}
+		return args[1 : 1+n]
+	}
+
+	var keys []interface{}
+	switch cmd {
+	case "del", "exists", "mget", "pfcount", "pfmerge", "sdiff", "sdiffstore", "sinter",
+		"sinterstore", "sunion", "sunionstore", "touch", "unlink", "watch":
+		keys = args
+	case "blmove", "brpoplpush", "copy", "geosearchstore", "lmove", "rename", "renamenx",
+		"rpoplpush", "smove", "zrangestore":
+		keys = args
+		if len(keys) > 2 {
+			keys = keys[:2]
+		}
+	case "blpop", "brpop", "bzpopmin", "bzpopmax":
+		if len(args) > 0 {
+			keys = args[:len(args)-1]
+		}
+	case "mset", "msetnx":
+		for i := 0; i < len(args); i += 2 {
+			keys = append(keys, args[i])
+		}
+	case "bitop":
+		if len(args) > 1 {
+			keys = args[1:]
+		}
+	case "zdiffstore", "zinterstore", "zunionstore":
+		if len(args) > 0 {
+			keys = append([]interface{}{args[0]}, numkeys(args[1:])...)
+		}
+	case "lmpop", "sintercard", "zdiff", "zinter", "zmpop", "zunion":
+		keys = numkeys(args)
+	case "blmpop", "bzmpop", "eval", "eval_ro", "evalsha", "evalsha_ro", "fcall", "fcall_ro":
+		if len(args) > 0 {
+			keys = numkeys(args[1:])
+		}
+	case "xread", "xreadgroup":
+		for i, arg := range args {
+			if strings.ToLower(string(argBytes(arg))) == "streams" {
+				rest := args[i+1:]
+				keys = rest[:len(rest)/2]
+				break
+			}
+		}
+	default:
+		if k, ok := commandKey(cmd, args); ok {
+			return [][]byte{k}
+		}
+		return nil
+	}
+
+	bkeys := make([][]byte, len(keys))
+	for i, k := range keys {
+		bkeys[i] = argBytes(k)
+	}
+	return bkeys
+}
+
+// checkKeys returns a *CrossSlotError if the keys of the given command
+// do not all have the same shard, as returned by the given function.
+func checkKeys(cmd string, args []interface{}, shard func(key []byte) string) error {
+	keys := commandKeys(cmd, args)
+	if len(keys) < 2 {
+		return nil
+	}
+	first := shard(keys[0])
+	var bad []string
+	for _, k := range keys[1:] {
+		if shard(k) != first {
+			bad = append(bad, string(k))
+		}
+	}
+	if bad == nil {
+		return nil
+	}
+	return &CrossSlotError{Cmd: cmd, Key: string(keys[0]), Keys: bad}
+}
+
+// slotOf returns the hash slot of the given key as a string.
+func slotOf(key []byte) string {
+	return strconv.Itoa(keySlot(key))
+}
+
+// keySlot returns the hash slot of the given key, honoring hash tags.
+func keySlot(key []byte) int {
+	return int(crc16(hashTag(key)) % numSlots)
+}
+
+// hashTag returns the part of the given key that is hashed:
+// the content of the first non-empty {...} section, or else the whole key.
+func hashTag(key []byte) []byte {
+	for i, b := range key {
+		if b == '{' {
+			for j := i + 1; j < len(key); j++ {
+				if key[j] == '}' {
+					if j > i+1 {
+						return key[i+1 : j]
+					}
+					break
+				}
+			}
+			break
+		}
+	}
+	return key
+}
+
+// crc16 implements the CRC16-CCITT (XMODEM) checksum used by Redis Cluster.
+func crc16(b []byte) uint16 {
+	var crc uint16
+	for _, v := range b {
+		crc ^= uint16(v) << 8
+		for i := 0; i < 8; i++ {
+			if crc&0x8000 != 0 {
+				crc = crc<<1 ^ 0x1021
+			} else {
+				crc <<= 1
+			}
+		}
+	}
+	return crc
+}
@@ -0,0 +1,287 @@
+package redis
+
+import (
+	"errors"
+	. "launchpad.net/gocheck"
+	"strings"
+	"time"
+)
+
+type ClusterSuite struct{}
+
+var _ = Suite(&ClusterSuite{})
+
+// fakeCluster returns a ClusterClient with a single node serving all slots.
+func fakeCluster(replies string) (*ClusterClient, *fakeConn) {
+	c, f := fakeClient(replies)
+	cc := &ClusterClient{
+		clients:  map[string]*Client{"127.0.0.1:7000": c},
+		replicas: map[string]bool{},
+		readonly: map[string]bool{},
+		rtt:      map[string]time.Duration{},
+		health:   quarantine{},
+		stats:    nodeStats{},
+	}
+	r := &slotRange{0, numSlots - 1, []string{"127.0.0.1:7000"}}
+	for i := range cc.slots {
+		cc.slots[i] = r
+	}
+	return cc, f
+}
+
+func (s *ClusterSuite) TestKeySlot(c *C) {
+	c.Check(crc16([]byte("123456789")), Equals, uint16(0x31c3))
+	c.Check(keySlot([]byte("foo")), Equals, 12182)
+	c.Check(keySlot([]byte("bar")), Equals, 5061)
+	c.Check(keySlot([]byte("{user1000}.following")), Equals, keySlot([]byte("user1000")))
+	c.Check(keySlot([]byte("{user1000}.followers")), Equals, keySlot([]byte("user1000")))
+	c.Check(keySlot([]byte("foo{}{bar}")), Equals, keySlot([]byte("foo{}{bar}")))
+	c.Check(keySlot([]byte("foo{{bar}}zap")), Equals, keySlot([]byte("{bar")))
+	c.Check(keySlot([]byte("\x00\r\n{a\x00\r\n}\xff")), Equals, keySlot([]byte("a\x00\r\n")))
+	k, _ := commandKey("SET", []interface{}{[]byte("\xff{\x00}\r\n"), "v"})
+	c.Check(k, DeepEquals, []byte("\xff{\x00}\r\n"))
+}
+
+func (s *ClusterSuite) TestCommandKey(c *C) {
+	k, ok := commandKey("GET", []interface{}{"foo"})
+	c.Check(ok, Equals, true)
+	c.Check(string(k), Equals, "foo")
+
+	k, ok = commandKey("mget", []interface{}{[]string{"a", "b"}})
+	c.Check(string(k), Equals, "a")
+
+	_, ok = commandKey("ping", nil)
+	c.Check(ok, Equals, false)
+
+	_, ok = commandKey("eval", []interface{}{"return 1", 0})
+	c.Check(ok, Equals, false)
+
+	k, _ = commandKey("evalsha", []interface{}{"abc", 1, "foo"})
+	c.Check(string(k), Equals, "foo")
+
+	k, _ = commandKey("xread", []interface{}{"count", 1, "STREAMS", "s1", "0"})
+	c.Check(string(k), Equals, "s1")
+
+	k, _ = commandKey("XGROUP", []interface{}{"CREATE", "s1", "g", "$"})
+	c.Check(string(k), Equals, "s1")
+}
+
+func (s *ClusterSuite) TestCmd(c *C) {
+	cc, f := fakeCluster("$3\r\nbar\r\n")
+	v, err := cc.Cmd("get", "foo").Str()
+	c.Assert(err, IsNil)
+	c.Check(v, Equals, "bar")
+	c.Check(f.out.String(), Equals, "*2\r\n$3\r\nget\r\n$3\r\nfoo\r\n")
+
+	cc.slots[keySlot([]byte("foo"))] = nil
+	r := cc.Cmd("get", "foo")
+	c.Check(r.Err, Equals, SlotNotServedError)
+}
+
+func (s *ClusterSuite) TestParseRedirect(c *C) {
+	ask, slot, addr, ok := parseRedirect(&Reply{Type: ErrorReply,
+		Err: errors.New("MOVED 3999 127.0.0.1:6381")}, "10.0.0.1")
+	c.Check(ok, Equals, true)
+	c.Check(ask, Equals, false)
+	c.Check(slot, Equals, 3999)
+	c.Check(addr, Equals, "127.0.0.1:6381")
+
+	// Redis 7 leaves out the host of nodes whose address it does not know
+	_, _, addr, ok = parseRedirect(&Reply{Type: ErrorReply, Err: errors.New("MOVED 1 :6381")},
+		"10.0.0.1")
+	c.Check(ok, Equals, true)
+	c.Check(addr, Equals, "10.0.0.1:6381")
+
+	ask, _, _, ok = parseRedirect(&Reply{Type: ErrorReply, Err: errors.New("ASK 1 a:1")}, "")
+	c.Check(ok, Equals, true)
+	c.Check(ask, Equals, true)
+
+	_, _, _, ok = parseRedirect(&Reply{Type: ErrorReply, Err: errors.New("ERR foo bar")}, "")
+	c.Check(ok, Equals, false)
+}
+
+func (s *ClusterSuite) TestRedirect(c *C) {
+	cc, _ := fakeCluster("-MOVED 12182 127.0.0.1:7001\r\n")
+	moved, _ := fakeClient("-ASK 12182 127.0.0.1:7002\r\n")
+	ask, f := fakeClient("+OK\r\n$3\r\nbar\r\n")
+	cc.clients["127.0.0.1:7001"] = moved
+	cc.clients["127.0.0.1:7002"] = ask
+
+	v, err := cc.Cmd("get", "foo").Str()
+	c.Assert(err, IsNil)
+	c.Check(v, Equals, "bar")
+	c.Check(f.out.String(), Equals, "*1\r\n$6\r\nasking\r\n*2\r\n$3\r\nget\r\n$3\r\nfoo\r\n")
+	c.Check(cc.slots[12182].addrs, DeepEquals, []string{"127.0.0.1:7001"})
+	c.Check(cc.slots[0].addrs, DeepEquals, []string{"127.0.0.1:7000"})
+
+	loop, _ := fakeClient(strings.Repeat("-MOVED 12182 127.0.0.1:7001\r\n", maxRedirects+1))
+	cc.clients["127.0.0.1:7001"] = loop
+	cc.stale = false
+	r := cc.Cmd("get", "foo")
+	c.Check(r.Err, Equals, TooManyRedirectsError)
+}
+
+func (s *ClusterSuite) TestRefresh(c *C) {
+	cc, _ := fakeCluster("-MOVED 12182 127.0.0.1:7001\r\n" +
+		"*1\r\n*3\r\n:0\r\n:16383\r\n*2\r\n$9\r\n127.0.0.1\r\n:7001\r\n")
+	node, f := fakeClient("$3\r\nbar\r\n$3\r\nbaz\r\n")
+	cc.clients["127.0.0.1:7001"] = node
+
+	v, _ := cc.Cmd("get", "foo").Str()
+	c.Check(v, Equals, "bar")
+	c.Check(cc.stale, Equals, true)
+
+	// the next command reloads the slot map and drops the departed node
+	v, _ = cc.Cmd("get", "zap").Str()
+	c.Check(v, Equals, "baz")
+	c.Check(cc.stale, Equals, false)
+	c.Check(cc.slots[0].addrs, DeepEquals, []string{"127.0.0.1:7001"})
+	_, ok := cc.clients["127.0.0.1:7000"]
+	c.Check(ok, Equals, false)
+	c.Check(strings.Count(f.out.String(), "get"), Equals, 2)
+}
+
+func (s *ClusterSuite) TestConnFailure(c *C) {
+	cc, _ := fakeCluster("")
+	r := cc.Cmd("get", "foo")
+	c.Check(r.Type, Equals, ErrorReply)
+	c.Check(cc.stale, Equals, true)
+	c.Check(len(cc.clients), Equals, 0)
+
+	cc, _ = fakeCluster("-CLUSTERDOWN The cluster is down\r\n")
+	cc.Cmd("get", "foo")
+	c.Check(cc.stale, Equals, true)
+}
+
+func (s *ClusterSuite) TestReadPolicy(c *C) {
+	cc, _ := fakeCluster("$1\r\nm\r\n+OK\r\n")
+	replica, f := fakeClient("+OK\r\n$1\r\nr\r\n$1\r\nr\r\n")
+	cc.clients["127.0.0.1:7001"] = replica
+	cc.replicas["127.0.0.1:7001"] = true
+	r := &slotRange{0, numSlots - 1, []string{"127.0.0.1:7000", "127.0.0.1:7001"}}
+	for i := range cc.slots {
+		cc.slots[i] = r
+	}
+
+	v, _ := cc.Cmd("get", "foo").Str()
+	c.Check(v, Equals, "m")
+
+	cc.ReadPolicy = ReadPreferReplica
+	v, _ = cc.Cmd("get", "foo").Str()
+	c.Check(v, Equals, "r")
+	c.Check(cc.readonly["127.0.0.1:7001"], Equals, true)
+	c.Check(cc.Cmd("set", "foo", "bar").Type, Equals, StatusReply)
+
+	cc.ReadPolicy = ReadNearest
+	cc.rtt["127.0.0.1:7000"] = 2 * time.Millisecond
+	cc.rtt["127.0.0.1:7001"] = time.Millisecond
+	v, _ = cc.Cmd("GET", "foo").Str()
+	c.Check(v, Equals, "r")
+	c.Check(strings.Count(f.out.String(), "readonly"), Equals, 1)
+}
+
+func (s *ClusterSuite) TestCommandKeys(c *C) {
+	str := func(keys [][]byte) []string {
+		l := make([]string, len(keys))
+		for i, k := range keys {
+			l[i] = string(k)
+		}
+		return l
+	}
+	c.Check(str(commandKeys("MGET", []interface{}{"a", []string{"b", "c"}})), DeepEquals,
+		[]string{"a", "b", "c"})
+	c.Check(str(commandKeys("mset", []interface{}{"a", 1, "b", 2})), DeepEquals,
+		[]string{"a", "b"})
+	c.Check(str(commandKeys("blpop", []interface{}{"a", "b", 0})), DeepEquals,
+		[]string{"a", "b"})
+	c.Check(str(commandKeys("zunionstore", []interface{}{"d", 2, "a", "b", "weights", 1, 2})),
+		DeepEquals, []string{"d", "a", "b"})
+	c.Check(str(commandKeys("eval", []interface{}{"return 1", 2, "a", "b", "arg"})), DeepEquals,
+		[]string{"a", "b"})
+	c.Check(str(commandKeys("xread", []interface{}{"streams", "a", "b", "0", "0"})), DeepEquals,
+		[]string{"a", "b"})
+	c.Check(str(commandKeys("get", []interface{}{"a"})), DeepEquals, []string{"a"})
+	c.Check(commandKeys("ping", nil), HasLen, 0)
+}
+
+func (s *ClusterSuite) TestCrossSlot(c *C) {
+	cc, f := fakeCluster("$1\r\n1\r\n")
+	r := cc.Cmd("sunion", "foo", "bar", "{foo}.x", "zap")
+	c.Assert(r.Type, Equals, ErrorReply)
+	e, ok := r.Err.(*CrossSlotError)
+	c.Assert(ok, Equals, true)
+	c.Check(e.Key, Equals, "foo")
+	c.Check(e.Keys, DeepEquals, []string{"bar", "zap"})
+	c.Check(e.Error(), Equals, `SUNION: keys "bar", "zap" are not in the same shard as "foo"`)
+	c.Check(f.out.Len(), Equals, 0)
+
+	c.Check(cc.Cmd("sunion", "{foo}.a", "{foo}.b").Type, Equals, BulkReply)
+}
+
+func (s *ClusterSuite) TestPipeline(c *C) {
+	cc, fa := fakeCluster("$1\r\nb\r\n-MOVED 5061 127.0.0.1:7001\r\n")
+	node, fb := fakeClient("$1\r\nf\r\n$1\r\nz\r\n$1\r\nB\r\n")
+	cc.clients["127.0.0.1:7001"] = node
+	for i := numSlots / 2; i < numSlots; i++ {
+		cc.slots[i] = &slotRange{numSlots / 2, numSlots - 1, []string{"127.0.0.1:7001"}}
+	}
+
+	cc.Append("get", "foo") // slot 12182
+	cc.Append("get", "bar") // slot 5061
+	cc.Append("sunion", "foo", "bar")
+	cc.Append("get", "qux") // slot 9995
+	cc.Append("get", "bar")
+	v, _ := cc.GetReply().Str()
+	c.Check(v, Equals, "f")
+	v, _ = cc.GetReply().Str()
+	c.Check(v, Equals, "b")
+	_, ok := cc.GetReply().Err.(*CrossSlotError)
+	c.Check(ok, Equals, true)
+	v, _ = cc.GetReply().Str()
+	c.Check(v, Equals, "z")
+	v, _ = cc.GetReply().Str()
+	c.Check(v, Equals, "B")
+	c.Check(cc.GetReply().Err, Equals, PipelineQueueEmptyError)
+
+	c.Check(strings.Count(fa.out.String(), "$3\r\nget"), Equals, 2)
+	c.Check(strings.Count(fb.out.String(), "$3\r\nget"), Equals, 3)
+}
+
+func (s *ClusterSuite) TestPipelineSplitOrder(c *C) {
+	cc, f := fakeCluster("+OK\r\n*1\r\n$1\r\n1\r\n*1\r\n$-1\r\n$1\r\n1\r\n")
+	cc.Append("set", "a", "1")
+	cc.Append("mget", "a", "b")
+	cc.Append("get", "a")
+	c.Check(cc.GetReply().Err, IsNil)
+	r := cc.GetReply()
+	c.Assert(r.Elems, HasLen, 2)
+	v, _ := r.Elems[0].Str()
+	c.Check(v, Equals, "1")
+	c.Check(r.Elems[1].Type, Equals, NilReply)
+	v, _ = cc.GetReply().Str()
+	c.Check(v, Equals, "1")
+
+	// the split MGET runs after the requests queued before it
+	c.Check(f.out.String(), Equals, "*3\r\n$3\r\nset\r\n$1\r\na\r\n$1\r\n1\r\n"+
+		"*2\r\n$4\r\nmget\r\n$1\r\na\r\n*2\r\n$4\r\nmget\r\n$1\r\nb\r\n"+
+		"*2\r\n$3\r\nget\r\n$1\r\na\r\n")
+}
+
+func (s *ClusterSuite) TestReadSameZone(c *C) {
+	cc, _ := fakeCluster("")
+	cc.ReadPolicy = ReadSameZone
+	cc.Zone = "eu-1a"
+	cc.Zones = map[string]string{"m": "eu-1a", "r1": "eu-1b", "r2": "eu-1a", "r3": "eu-1a"}
+
+	c.Check(cc.readAddr([]string{"m", "r1", "r2"}), Equals, "r2")
+	c.Check(cc.readAddr([]string{"m", "r1"}), Equals, "m")
+
+	cc.rtt["r2"] = 2 * time.Millisecond
+	cc.rtt["r3"] = time.Millisecond
+	c.Check(cc.readAddr([]string{"m", "r2", "r3"}), Equals, "r3")
+
+	// no node in the zone
+	cc.Zone = "us-1a"
+	cc.rtt["r1"] = time.Microsecond
+	c.Check(cc.readAddr([]string{"m", "r1", "r2"}), Equals, "r1")
+}
@@ -0,0 +1,383 @@
+package redis
+
+import (
+	"errors"
+	"net"
+	"strconv"
+	"strings"
+)
+
+//* Cluster commands
+
+// SlotRange describes an inclusive range of hash slots.
+type SlotRange struct {
+	Start, End int
+}
+
+// ClusterSlotNode describes a node serving a slot range in CLUSTER SLOTS.
+type ClusterSlotNode struct {
+	Addr string // host:port
+	ID   string // Node ID, empty if not reported
+}
+
+// ClusterSlotRange describes a slot range in CLUSTER SLOTS.
+// The first node is the master, the rest are its replicas.
+type ClusterSlotRange struct {
+	SlotRange
+	Nodes []ClusterSlotNode
+}
+
+// ClusterNode describes a node in CLUSTER NODES.
+type ClusterNode struct {
+	ID          string
+	Addr        string // host:port used by clients
+	BusPort     int    // Cluster bus port
+	Hostname    string
+	Flags       []string       // myself, master, slave, fail?, fail, handshake, noaddr, ...
+	MasterID    string         // ID of the master of a replica, empty for masters
+	PingSent    int64          // Unix time in milliseconds of the pending ping, 0 if none
+	PongRecv    int64          // Unix time in milliseconds of the last pong
+	ConfigEpoch int64          // Configuration epoch
+	Connected   bool           // State of the cluster bus link
+	Slots       []SlotRange    // Served slots
+	Migrating   map[int]string // Slots being migrated, mapped to the target node ID
+	Importing   map[int]string // Slots being imported, mapped to the source node ID
+}
+
+// HasFlag returns true if the node has the given flag.
+func (n *ClusterNode) HasFlag(flag string) bool {
+	for _, f := range n.Flags {
+		if f == flag {
+			return true
+		}
+	}
+	return false
+}
+
+// ClusterShardNode describes a node of a shard in CLUSTER SHARDS.
+type ClusterShardNode struct {
+	ID                string
+	Endpoint          string
+	IP                string
+	Hostname          string
+	Port              int
+	TLSPort           int
+	Role              string // master or replica
+	ReplicationOffset int64
+	Health            string // online, failed or loading
+}
+
+// ClusterShard describes a shard in CLUSTER SHARDS.
+type ClusterShard struct {
+	Slots []SlotRange
+	Nodes []ClusterShardNode
+}
+
+// ClusterInfo holds the fields of CLUSTER INFO.
+type ClusterInfo struct {
+	State         string // ok or fail
+	SlotsAssigned int
+	SlotsOK       int
+	SlotsPfail    int
+	SlotsFail     int
+	KnownNodes    int
+	Size          int // Number of masters serving slots
+	CurrentEpoch  int64
+	MyEpoch       int64
+	Fields        map[string]string // All fields, including the ones above
+}
+
+//* Public methods
+
+// ClusterSlots returns the slot ranges of the cluster and the nodes serving them.
+func (c *Client) ClusterSlots() ([]ClusterSlotRange, error) {
+	host, _, _ := net.SplitHostPort(c.conn.RemoteAddr().String())
+	return parseClusterSlots(c.Cmd("cluster", "slots"), host)
+}
+
+// ClusterNodes returns the nodes of the cluster as seen by the connected node.
+func (c *Client) ClusterNodes() ([]ClusterNode, error) {
+	s, err := c.Cmd("cluster", "nodes").Str()
+	if err != nil {
+		return nil, err
+	}
+	return parseClusterNodes(s)
+}
+
+// ClusterShards returns the shards of the cluster (Redis 7.0 or later).
+func (c *Client) ClusterShards() ([]ClusterShard, error) {
+	return parseClusterShards(c.cmdSince(Version{7, 0, 0}, "cluster", "shards"))
+}
+
+// ClusterInfo returns the cluster state as seen by the connected node.
+func (c *Client) ClusterInfo() (*ClusterInfo, error) {
+	s, err := c.Cmd("cluster", "info").Str()
+	if err != nil {
+		return nil, err
+	}
+	return parseClusterInfo(s)
+}
+
+//* Parsing
+
+// parseClusterSlots parses a CLUSTER SLOTS reply.
+// Nodes that are reported without an IP are assumed to live on the given host.
+func parseClusterSlots(r *Reply, host string) ([]ClusterSlotRange, error) {
+	if r.Type == ErrorReply {
+		return nil, r.Err
+	}
+	if r.Type != MultiReply {
+		return nil, errors.New("reply type is not MultiReply")
+	}
+
+	ranges := make([]ClusterSlotRange, 0, len(r.Elems))
+	for _, e := range r.Elems {
+		if e.Type != MultiReply || len(e.Elems) < 2 {
+			return nil, ParseError
+		}
+		start, err := e.Elems[0].Int()
+		if err != nil {
+			return nil, err
+		}
+		end, err := e.Elems[1].Int()
+		if err != nil {
+			return nil, err
+		}
+		if start < 0 || end >= numSlots || start > end {
+			return nil, ParseError
+		}
+		sr := ClusterSlotRange{SlotRange: SlotRange{start, end}}
+		for _, n := range e.Elems[2:] {
+			if n.Type != MultiReply || len(n.Elems) < 2 {
+				return nil, ParseError
+			}
+			ip, err := n.Elems[0].Str()
+			if err != nil {
+				return nil, err
+			}
+			port, err := n.Elems[1].Int()
+			if err != nil {
+				return nil, err
+			}
+			if ip == "" {
+				ip = host
+			}
+			node := ClusterSlotNode{Addr: net.JoinHostPort(ip, strconv.Itoa(port))}
+			if len(n.Elems) > 2 {
+				node.ID, _ = n.Elems[2].Str()
+			}
+			sr.Nodes = append(sr.Nodes, node)
+		}
+		ranges = append(ranges, sr)
+	}
+	return ranges, nil
+}
+
+// parseClusterNodes parses the output of CLUSTER NODES.
+func parseClusterNodes(s string) ([]ClusterNode, error) {
+	var nodes []ClusterNode
+	for _, line := range strings.Split(s, "\n") {
+		f := strings.Fields(line)
+		if len(f) == 0 {
+			continue
+		}
+		if len(f) < 8 {
+			return nil, ParseError
+		}
+
+		n := ClusterNode{ID: f[0], Connected: f[7] == "connected"}
+		// ip:port@cport[,hostname]
+		addr := f[1]
+		if i := strings.IndexByte(addr, ','); i >= 0 {
+			addr, n.Hostname = addr[:i], addr[i+1:]
+		}
+		if i := strings.IndexByte(addr, '@'); i >= 0 {
+			n.BusPort, _ = strconv.Atoi(addr[i+1:])
+			addr = addr[:i]
+		}
+		n.Addr = addr
+		if f[2] != "noflags" {
+			n.Flags = strings.Split(f[2], ",")
+		}
+		if f[3] != "-" {
+			n.MasterID = f[3]
+		}
+		var err error
+		if n.PingSent, err = strconv.ParseInt(f[4], 10, 64); err != nil {
+			return nil, ParseError
+		}
+		if n.PongRecv, err = strconv.ParseInt(f[5], 10, 64); err != nil {
+			return nil, ParseError
+		}
+		if n.ConfigEpoch, err = strconv.ParseInt(f[6], 10, 64); err != nil {
+			return nil, ParseError
+		}
+
+		for _, slot := range f[8:] {
+			if strings.HasPrefix(slot, "[") {
+				// [slot->-target] or [slot-<-source]
+				slot = strings.Trim(slot, "[]")
+				if i := strings.Index(slot, "->-"); i >= 0 {
+					num, err := strconv.Atoi(slot[:i])
+					if err != nil {
+						return nil, ParseError
+					}
+					if n.Migrating == nil {
+						n.Migrating = map[int]string{}
+					}
+					n.Migrating[num] = slot[i+3:]
+				} else if i := strings.Index(slot, "-<-"); i >= 0 {
+					num, err := strconv.Atoi(slot[:i])
+					if err != nil {
+						return nil, ParseError
+					}
+					if n.Importing == nil {
+						n.Importing = map[int]string{}
+					}
+					n.Importing[num] = slot[i+3:]
+				}
+				continue
+			}
+			sr, err := parseSlotRange(slot)
+			if err != nil {
+				return nil, err
+			}
+			n.Slots = append(n.Slots, sr)
+		}
+		nodes = append(nodes, n)
+	}
+	return nodes, nil
+}
+
+// parseSlotRange parses a "start-end" or "slot" slot range.
+func parseSlotRange(s string) (SlotRange, error) {
+	var sr SlotRange
+	var err error
+	start, end := s, s
+	if i := strings.IndexByte(s, '-'); i >= 0 {
+		start, end = s[:i], s[i+1:]
+	}
+	if sr.Start, err = strconv.Atoi(start); err != nil {
+		return sr, ParseError
+	}
+	if sr.End, err = strconv.Atoi(end); err != nil {
+		return sr, ParseError
+	}
+	return sr, nil
+}
+
+// parseClusterShards parses a CLUSTER SHARDS reply.
+func parseClusterShards(r *Reply) ([]ClusterShard, error) {
+	if r.Type == ErrorReply {
+		return nil, r.Err
+	}
+	if r.Type != MultiReply {
+		return nil, errors.New("reply type is not MultiReply")
+	}
+
+	shards := make([]ClusterShard, 0, len(r.Elems))
+	for _, e := range r.Elems {
+		m, err := e.pairs()
+		if err != nil {
+			return nil, err
+		}
+		var shard ClusterShard
+		if slots, ok := m["slots"]; ok {
+			if len(slots.Elems)%2 != 0 {
+				return nil, ParseError
+			}
+			for i := 0; i < len(slots.Elems); i += 2 {
+				start, err := slots.Elems[i].Int()
+				if err != nil {
+					return nil, err
+				}
+				end, err := slots.Elems[i+1].Int()
+				if err != nil {
+					return nil, err
+				}
+				shard.Slots = append(shard.Slots, SlotRange{start, end})
+			}
+		}
+		if nodes, ok := m["nodes"]; ok {
+			for _, ne := range nodes.Elems {
+				nm, err := ne.pairs()
+				if err != nil {
+					return nil, err
+				}
+				var n ClusterShardNode
+				str := func(k string) string {
+					if v, ok := nm[k]; ok {
+						s, _ := v.Str()
+						return s
+					}
+					return ""
+				}
+				num := func(k string) int64 {
+					if v, ok := nm[k]; ok {
+						i, _ := v.Int64()
+						return i
+					}
+					return 0
+				}
+				n.ID = str("id")
+				n.Endpoint = str("endpoint")
+				n.IP = str("ip")
+				n.Hostname = str("hostname")
+				n.Port = int(num("port"))
+				n.TLSPort = int(num("tls-port"))
+				n.Role = str("role")
+				n.ReplicationOffset = num("replication-offset")
+				n.Health = str("health")
+				shard.Nodes = append(shard.Nodes, n)
+			}
+		}
+		shards = append(shards, shard)
+	}
+	return shards, nil
+}
+
+// parseClusterInfo parses the output of CLUSTER INFO.
+func parseClusterInfo(s string) (*ClusterInfo, error) {
+	f := parseInfoFields(s)
+	info := &ClusterInfo{State: f["cluster_state"], Fields: f}
+	ints := map[string]*int{
+		"cluster_slots_assigned": &info.SlotsAssigned,
+		"cluster_slots_ok":       &info.SlotsOK,
+		"cluster_slots_pfail":    &info.SlotsPfail,
+		"cluster_slots_fail":     &info.SlotsFail,
+		"cluster_known_nodes":    &info.KnownNodes,
+		"cluster_size":           &info.Size,
+	}
+	for k, p := range ints {
+		if v, ok := f[k]; ok {
+			i, err := strconv.Atoi(v)
+			if err != nil {
+				return nil, ParseError
+			}
+			*p = i
+		}
+	}
+	if v, ok := f["cluster_current_epoch"]; ok {
+		info.CurrentEpoch, _ = strconv.ParseInt(v, 10, 64)
+	}
+	if v, ok := f["cluster_my_epoch"]; ok {
+		info.MyEpoch, _ = strconv.ParseInt(v, 10, 64)
+	}
+	return info, nil
+}
+
+// parseInfoFields parses "key:value" lines, as returned by INFO-like commands.
+// Blank lines and lines starting with '#' are skipped.
+func parseInfoFields(s string) map[string]string {
+	fields := map[string]string{}
+	for _, line := range strings.Split(s, "\n") {
+		line = strings.TrimRight(line, "\r")
+		if line == "" || line[0] == '#' {
+			continue
+		}
+		if i := strings.IndexByte(line, ':'); i >= 0 {
+			fields[line[:i]] = line[i+1:]
+		}
+	}
+	return fields
+}
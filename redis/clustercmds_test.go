@@ -0,0 +1,101 @@
+package redis
+
+import (
+	. "launchpad.net/gocheck"
+)
+
+type ClusterCmdsSuite struct{}
+
+var _ = Suite(&ClusterCmdsSuite{})
+
+func bulk(s string) *Reply {
+	return &Reply{Type: BulkReply, buf: []byte(s)}
+}
+
+func integer(i int64) *Reply {
+	return &Reply{Type: IntegerReply, int: i}
+}
+
+func multi(elems ...*Reply) *Reply {
+	return &Reply{Type: MultiReply, Elems: elems}
+}
+
+func (s *ClusterCmdsSuite) TestParseClusterSlots(c *C) {
+	r := multi(
+		multi(integer(0), integer(5460),
+			multi(bulk("10.0.0.1"), integer(7000), bulk("id1")),
+			multi(bulk("10.0.0.2"), integer(7001))),
+		multi(integer(5461), integer(16383),
+			multi(bulk(""), integer(7002))))
+	ranges, err := parseClusterSlots(r, "10.0.0.9")
+	c.Assert(err, IsNil)
+	c.Check(ranges, DeepEquals, []ClusterSlotRange{
+		{SlotRange{0, 5460}, []ClusterSlotNode{{"10.0.0.1:7000", "id1"}, {"10.0.0.2:7001", ""}}},
+		{SlotRange{5461, 16383}, []ClusterSlotNode{{"10.0.0.9:7002", ""}}},
+	})
+}
+
+func (s *ClusterCmdsSuite) TestParseClusterNodes(c *C) {
+	out := "07c37dfeb2352e0 127.0.0.1:30004@31004,host4 slave e7d1eecce10fd6b " +
+		"0 1426238317239 4 connected\n" +
+		"e7d1eecce10fd6b 127.0.0.1:30001@31001 myself,master - 0 0 1 connected 0-5460 5500 " +
+		"[5461->-292f8b365bb7edb] [5462-<-07c37dfeb2352e0]\n"
+	nodes, err := parseClusterNodes(out)
+	c.Assert(err, IsNil)
+	c.Assert(nodes, HasLen, 2)
+
+	c.Check(nodes[0].ID, Equals, "07c37dfeb2352e0")
+	c.Check(nodes[0].Addr, Equals, "127.0.0.1:30004")
+	c.Check(nodes[0].BusPort, Equals, 31004)
+	c.Check(nodes[0].Hostname, Equals, "host4")
+	c.Check(nodes[0].HasFlag("slave"), Equals, true)
+	c.Check(nodes[0].MasterID, Equals, "e7d1eecce10fd6b")
+	c.Check(nodes[0].PongRecv, Equals, int64(1426238317239))
+	c.Check(nodes[0].ConfigEpoch, Equals, int64(4))
+	c.Check(nodes[0].Connected, Equals, true)
+
+	c.Check(nodes[1].HasFlag("myself"), Equals, true)
+	c.Check(nodes[1].MasterID, Equals, "")
+	c.Check(nodes[1].Slots, DeepEquals, []SlotRange{{0, 5460}, {5500, 5500}})
+	c.Check(nodes[1].Migrating, DeepEquals, map[int]string{5461: "292f8b365bb7edb"})
+	c.Check(nodes[1].Importing, DeepEquals, map[int]string{5462: "07c37dfeb2352e0"})
+
+	_, err = parseClusterNodes("foo bar\n")
+	c.Check(err, Equals, ParseError)
+}
+
+func (s *ClusterCmdsSuite) TestParseClusterShards(c *C) {
+	r := multi(multi(
+		bulk("slots"), multi(integer(0), integer(5460), integer(6000), integer(6001)),
+		bulk("nodes"), multi(multi(
+			bulk("id"), bulk("abc"),
+			bulk("port"), integer(30001),
+			bulk("ip"), bulk("127.0.0.1"),
+			bulk("endpoint"), bulk("127.0.0.1"),
+			bulk("role"), bulk("master"),
+			bulk("replication-offset"), integer(72156),
+			bulk("health"), bulk("online")))))
+	shards, err := parseClusterShards(r)
+	c.Assert(err, IsNil)
+	c.Assert(shards, HasLen, 1)
+	c.Check(shards[0].Slots, DeepEquals, []SlotRange{{0, 5460}, {6000, 6001}})
+	c.Check(shards[0].Nodes, DeepEquals, []ClusterShardNode{{
+		ID: "abc", Endpoint: "127.0.0.1", IP: "127.0.0.1", Port: 30001,
+		Role: "master", ReplicationOffset: 72156, Health: "online",
+	}})
+}
+
+func (s *ClusterCmdsSuite) TestParseClusterInfo(c *C) {
+	info, err := parseClusterInfo("cluster_state:ok\r\ncluster_slots_assigned:16384\r\n" +
+		"cluster_slots_ok:16384\r\ncluster_known_nodes:6\r\ncluster_size:3\r\n" +
+		"cluster_current_epoch:6\r\ncluster_my_epoch:2\r\n")
+	c.Assert(err, IsNil)
+	c.Check(info.State, Equals, "ok")
+	c.Check(info.SlotsAssigned, Equals, 16384)
+	c.Check(info.SlotsOK, Equals, 16384)
+	c.Check(info.KnownNodes, Equals, 6)
+	c.Check(info.Size, Equals, 3)
+	c.Check(info.CurrentEpoch, Equals, int64(6))
+	c.Check(info.MyEpoch, Equals, int64(2))
+	c.Check(info.Fields["cluster_size"], Equals, "3")
+}
@@ -0,0 +1,220 @@
+package redis
+
+import (
+	"errors"
+	"strings"
+)
+
+//* Command introspection
+
+// ArityError is returned, without sending the command, when a client with
+// ValidateCommands set calls a command with the wrong number of arguments.
+type ArityError struct {
+	Cmd   string // Command name, "container|subcommand" for subcommands
+	Arity int    // Arity as reported by COMMAND INFO, negative for a minimum
+	Args  int    // Number of arguments given, including the command name
+}
+
+func (e *ArityError) Error() string {
+	return "wrong number of arguments for '" + e.Cmd + "' command"
+}
+
+// CommandInfo describes a command, as returned by COMMAND INFO.
+type CommandInfo struct {
+	Name          string
+	Arity         int      // Number of arguments with the name, -N for at least N
+	Flags         []string // Such as "write", "readonly" or "denyoom"
+	FirstKey      int      // Position of the first key, 0 if there are no keys
+	LastKey       int      // Position of the last key, negative from the end
+	KeyStep       int      // Step between the positions of the keys
+	ACLCategories []string // Redis 6.0 or later
+	// Subcommands by name, such as "get" for CONFIG GET (Redis 7.0 or later)
+	Subcommands map[string]*CommandInfo
+}
+
+// CommandDoc is the documentation of a command, as returned by COMMAND DOCS
+// (Redis 7.0 or later).
+type CommandDoc struct {
+	Summary    string
+	Since      string // Server version that added the command
+	Group      string // Such as "string" or "server"
+	Complexity string
+}
+
+// CommandInfo returns the description of the given commands, or of all commands if
+// none are given, by lower case name. Unknown commands are omitted.
+func (c *Client) CommandInfo(names ...string) (map[string]*CommandInfo, error) {
+	var r *Reply
+	if len(names) == 0 {
+		r = c.Cmd("command")
+	} else {
+		r = c.Cmd("command", "info", names)
+	}
+	return parseCommandInfos(r)
+}
+
+// CommandDocs returns the documentation of the given commands, or of all commands if
+// none are given, by lower case name (Redis 7.0 or later).
+func (c *Client) CommandDocs(names ...string) (map[string]CommandDoc, error) {
+	m, err := c.cmdSince(Version{7, 0, 0}, "command", "docs", names).pairs()
+	if err != nil {
+		return nil, err
+	}
+	docs := make(map[string]CommandDoc, len(m))
+	for name, r := range m {
+		dm, err := r.pairs()
+		if err != nil {
+			return nil, err
+		}
+		var d CommandDoc
+		for k, p := range map[string]*string{
+			"summary": &d.Summary, "since": &d.Since, "group": &d.Group,
+			"complexity": &d.Complexity,
+		} {
+			if v, ok := dm[k]; ok {
+				*p, _ = v.Str()
+			}
+		}
+		docs[strings.ToLower(name)] = d
+	}
+	return docs, nil
+}
+
+// Commands returns the description of all commands of the server, fetched with
+// COMMAND on the first call and cached until the client connects to another server.
+func (c *Client) Commands() (map[string]*CommandInfo, error) {
+	if c.commands == nil {
+		c.commands = map[string]*CommandInfo{} // don't validate COMMAND itself
+		cmds, err := c.CommandInfo()
+		if err != nil {
+			c.commands = nil
+			return nil, err
+		}
+		c.commands = cmds
+	}
+	return c.commands, nil
+}
+
+// validateRequests checks the number of arguments of the given requests against the
+// cached command table. Unknown commands, such as module commands loaded later, are
+// not checked.
+func (c *Client) validateRequests(requests []*request) error {
+	cmds, err := c.Commands()
+	if err != nil {
+		return err
+	}
+	for _, req := range requests {
+		info, ok := cmds[strings.ToLower(req.cmd)]
+		if !ok {
+			continue
+		}
+		args := flattenArgs(req.args)
+		if len(info.Subcommands) > 0 && len(args) > 0 {
+			// the arity of subcommands counts the container command too
+			sub, ok := info.Subcommands[strings.ToLower(string(argBytes(args[0])))]
+			if ok {
+				info = sub
+			}
+		}
+		if err := info.check(len(args) + 1); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// check returns an *ArityError if a call with n arguments, including the command name,
+// does not match the arity or the key positions of the command.
+func (info *CommandInfo) check(n int) error {
+	if info.Arity >= 0 && n != info.Arity || info.Arity < 0 && n < -info.Arity {
+		return &ArityError{Cmd: info.Name, Arity: info.Arity, Args: n}
+	}
+	// variadic commands with key groups, such as MSET key value [key value...], must
+	// be given whole groups
+	if info.Arity < 0 && info.FirstKey > 0 && info.LastKey == -1 && info.KeyStep > 1 &&
+		(n-info.FirstKey)%info.KeyStep != 0 {
+		return &ArityError{Cmd: info.Name, Arity: info.Arity, Args: n}
+	}
+	return nil
+}
+
+// parseCommandInfos parses the reply of COMMAND or COMMAND INFO.
+func parseCommandInfos(r *Reply) (map[string]*CommandInfo, error) {
+	if r.Type == ErrorReply {
+		return nil, r.Err
+	}
+	if r.Type != MultiReply {
+		return nil, errors.New("reply type is not MultiReply")
+	}
+	cmds := make(map[string]*CommandInfo, len(r.Elems))
+	for _, e := range r.Elems {
+		if e.Type == NilReply {
+			continue
+		}
+		info, err := parseCommandInfo(e)
+		if err != nil {
+			return nil, err
+		}
+		cmds[info.Name] = info
+	}
+	return cmds, nil
+}
+
+// parseCommandInfo parses a command of the reply of COMMAND INFO:
+// name, arity, flags, first key, last key, key step, ACL categories, tips,
+// key specifications and subcommands, the last four being optional.
+func parseCommandInfo(r *Reply) (*CommandInfo, error) {
+	if r.Type != MultiReply || len(r.Elems) < 6 {
+		return nil, errors.New("reply is not a command description")
+	}
+	name, err := r.Elems[0].Str()
+	if err != nil {
+		return nil, err
+	}
+	info := &CommandInfo{Name: strings.ToLower(name)}
+	ints := map[int]*int{
+		1: &info.Arity, 3: &info.FirstKey, 4: &info.LastKey, 5: &info.KeyStep,
+	}
+	for i, p := range ints {
+		if *p, err = r.Elems[i].Int(); err != nil {
+			return nil, err
+		}
+	}
+	if info.Flags, err = parseStatusList(r.Elems[2]); err != nil {
+		return nil, err
+	}
+	if len(r.Elems) > 6 {
+		if info.ACLCategories, err = parseStatusList(r.Elems[6]); err != nil {
+			return nil, err
+		}
+	}
+	if len(r.Elems) > 9 && len(r.Elems[9].Elems) > 0 {
+		info.Subcommands = map[string]*CommandInfo{}
+		for _, e := range r.Elems[9].Elems {
+			sub, err := parseCommandInfo(e)
+			if err != nil {
+				return nil, err
+			}
+			// subcommands are named "container|subcommand"
+			info.Subcommands[sub.Name[strings.IndexByte(sub.Name, '|')+1:]] = sub
+		}
+	}
+	return info, nil
+}
+
+// parseStatusList parses a multi bulk reply of status or bulk replies, such as the
+// flags of COMMAND INFO.
+func parseStatusList(r *Reply) ([]string, error) {
+	if r.Type != MultiReply {
+		return nil, errors.New("reply type is not MultiReply")
+	}
+	l := make([]string, len(r.Elems))
+	for i, e := range r.Elems {
+		s, err := e.Str()
+		if err != nil {
+			return nil, err
+		}
+		l[i] = s
+	}
+	return l, nil
+}
@@ -0,0 +1,85 @@
+package redis
+
+import (
+	. "launchpad.net/gocheck"
+)
+
+type CommandsSuite struct{}
+
+var _ = Suite(&CommandsSuite{})
+
+// commandTable is a COMMAND reply describing GET, MSET and CONFIG GET.
+const commandTable = "*3\r\n" +
+	"*10\r\n$3\r\nget\r\n:2\r\n*2\r\n+readonly\r\n+fast\r\n:1\r\n:1\r\n:1\r\n" +
+	"*2\r\n$5\r\n@read\r\n$7\r\n@string\r\n*0\r\n*0\r\n*0\r\n" +
+	"*7\r\n$4\r\nmset\r\n:-3\r\n*1\r\n+write\r\n:1\r\n:-1\r\n:2\r\n*1\r\n$6\r\n@write\r\n" +
+	"*10\r\n$6\r\nconfig\r\n:-2\r\n*0\r\n:0\r\n:0\r\n:0\r\n*1\r\n$5\r\n@slow\r\n*0\r\n*0\r\n" +
+	"*1\r\n*10\r\n$10\r\nconfig|get\r\n:-3\r\n*0\r\n:0\r\n:0\r\n:0\r\n*0\r\n*0\r\n*0\r\n*0\r\n"
+
+func (s *CommandsSuite) TestCommandInfo(c *C) {
+	cl, f := fakeClient(commandTable + "*2\r\n" +
+		"*6\r\n$3\r\nGET\r\n:2\r\n*0\r\n:1\r\n:1\r\n:1\r\n$-1\r\n")
+	cmds, err := cl.CommandInfo()
+	c.Assert(err, IsNil)
+	c.Check(cmds, HasLen, 3)
+	c.Check(cmds["get"], DeepEquals, &CommandInfo{Name: "get", Arity: 2,
+		Flags: []string{"readonly", "fast"}, FirstKey: 1, LastKey: 1, KeyStep: 1,
+		ACLCategories: []string{"@read", "@string"}})
+	c.Check(cmds["mset"].LastKey, Equals, -1)
+	c.Check(cmds["config"].Subcommands["get"].Name, Equals, "config|get")
+	c.Check(cmds["config"].Subcommands["get"].Arity, Equals, -3)
+
+	cmds, err = cl.CommandInfo("get", "nope")
+	c.Assert(err, IsNil)
+	c.Check(cmds, HasLen, 1)
+	c.Check(cmds["get"].Arity, Equals, 2)
+	c.Check(f.out.String(), Equals, "*1\r\n$7\r\ncommand\r\n"+
+		"*4\r\n$7\r\ncommand\r\n$4\r\ninfo\r\n$3\r\nget\r\n$4\r\nnope\r\n")
+}
+
+func (s *CommandsSuite) TestCommandDocs(c *C) {
+	cl, f := fakeClient("*2\r\n$3\r\nget\r\n*8\r\n$7\r\nsummary\r\n$9\r\nGet a key\r\n" +
+		"$5\r\nsince\r\n$5\r\n1.0.0\r\n$5\r\ngroup\r\n$6\r\nstring\r\n" +
+		"$10\r\ncomplexity\r\n$4\r\nO(1)\r\n")
+	docs, err := cl.CommandDocs("get")
+	c.Assert(err, IsNil)
+	c.Check(docs, DeepEquals, map[string]CommandDoc{
+		"get": {Summary: "Get a key", Since: "1.0.0", Group: "string", Complexity: "O(1)"},
+	})
+	c.Check(f.out.String(), Equals, "*3\r\n$7\r\ncommand\r\n$4\r\ndocs\r\n$3\r\nget\r\n")
+}
+
+func (s *CommandsSuite) TestValidateCommands(c *C) {
+	cl, f := fakeClient(commandTable + "$1\r\nv\r\n+OK\r\n$1\r\n5\r\n+PONG\r\n")
+	cl.ValidateCommands = true
+
+	_, err := cl.Cmd("get", "k").Str()
+	c.Assert(err, IsNil)
+	c.Check(f.out.String(), Equals, "*1\r\n$7\r\ncommand\r\n*2\r\n$3\r\nget\r\n$1\r\nk\r\n")
+	f.out.Reset()
+
+	err = cl.Cmd("GET", "a", "b").Err
+	c.Check(err, DeepEquals, &ArityError{Cmd: "get", Arity: 2, Args: 3})
+	c.Check(err, ErrorMatches, "wrong number of arguments for 'get' command")
+	c.Check(cl.Cmd("mset", "a", "1", "b").Err, DeepEquals,
+		&ArityError{Cmd: "mset", Arity: -3, Args: 4})
+	c.Check(cl.Cmd("mset", []string{"a"}).Err, DeepEquals,
+		&ArityError{Cmd: "mset", Arity: -3, Args: 2})
+	c.Check(cl.Cmd("config", "get").Err, DeepEquals,
+		&ArityError{Cmd: "config|get", Arity: -3, Args: 2})
+	c.Check(f.out.Len(), Equals, 0)
+
+	c.Check(cl.Cmd("mset", map[string]string{"a": "1"}).Err, IsNil)
+	c.Check(cl.Cmd("config", "get", "maxmemory").Err, IsNil)
+	c.Check(cl.Cmd("ping").Err, IsNil)
+
+	cl.Append("get", "k")
+	cl.Append("get")
+	c.Check(cl.GetReply().Err, FitsTypeOf, &ArityError{})
+	c.Check(cl.GetReply().Err, FitsTypeOf, &ArityError{})
+
+	cl, _ = fakeClient("-NOPERM no permissions\r\n")
+	cl.ValidateCommands = true
+	c.Check(cl.Cmd("get", "k").Err, ErrorMatches, "NOPERM no permissions")
+	c.Check(cl.commands, IsNil)
+}
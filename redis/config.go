@@ -0,0 +1,79 @@
+package redis
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+//* Config
+
+// ConfigGet returns the configuration parameters matching any of the given glob-style
+// patterns, by name. More than one pattern requires Redis 7.0 or later.
+func (c *Client) ConfigGet(patterns ...string) (map[string]string, error) {
+	if len(patterns) == 0 {
+		return nil, errors.New("at least one pattern must be given")
+	}
+	return c.Cmd("config", "get", patterns).Hash()
+}
+
+// ConfigGetInt returns the value of the given configuration parameter as an integer,
+// see ParseConfigSize.
+func (c *Client) ConfigGetInt(param string) (int64, error) {
+	m, err := c.ConfigGet(param)
+	if err != nil {
+		return 0, err
+	}
+	v, ok := m[param]
+	if !ok {
+		return 0, errors.New("unknown configuration parameter " + param)
+	}
+	return ParseConfigSize(v)
+}
+
+// ConfigSet sets the given configuration parameter. The value is encoded as any
+// other argument, so sizes can be given in bytes.
+func (c *Client) ConfigSet(param string, value interface{}) error {
+	return c.Cmd("config", "set", param, value).Err
+}
+
+// ConfigRewrite rewrites the configuration file of the server to reflect the
+// current configuration.
+func (c *Client) ConfigRewrite() error {
+	return c.Cmd("config", "rewrite").Err
+}
+
+// ConfigResetStat resets the statistics reported by INFO, see Client.Info.
+func (c *Client) ConfigResetStat() error {
+	return c.Cmd("config", "resetstat").Err
+}
+
+// configUnits are the size suffixes accepted by the server, in the order they must
+// be tried.
+var configUnits = []struct {
+	suffix string
+	mul    int64
+}{
+	{"kb", 1 << 10}, {"mb", 1 << 20}, {"gb", 1 << 30},
+	{"k", 1e3}, {"m", 1e6}, {"g", 1e9}, {"b", 1},
+}
+
+// ParseConfigSize parses an integer configuration value, with an optional size
+// suffix as in redis.conf: "1k" is 1000 bytes, "1kb" is 1024 bytes, and likewise
+// for m, mb, g and gb. Suffixes are case insensitive. It returns ParseError if the
+// value is not an integer.
+func ParseConfigSize(s string) (int64, error) {
+	s = strings.ToLower(s)
+	mul := int64(1)
+	for _, u := range configUnits {
+		if strings.HasSuffix(s, u.suffix) {
+			s, mul = s[:len(s)-len(u.suffix)], u.mul
+			break
+		}
+	}
+	i, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return 0, ParseError
+	}
+	return i * mul, nil
+}
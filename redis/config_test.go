@@ -0,0 +1,55 @@
+package redis
+
+import (
+	. "launchpad.net/gocheck"
+)
+
+type ConfigSuite struct{}
+
+var _ = Suite(&ConfigSuite{})
+
+func (s *ConfigSuite) TestConfigGet(c *C) {
+	cl, f := fakeClient("*4\r\n$9\r\nmaxmemory\r\n$3\r\n2gb\r\n$7\r\ntimeout\r\n$1\r\n0\r\n" +
+		"*2\r\n$9\r\nmaxmemory\r\n$10\r\n2147483648\r\n*0\r\n")
+	m, err := cl.ConfigGet("max*", "timeout")
+	c.Assert(err, IsNil)
+	c.Check(m, DeepEquals, map[string]string{"maxmemory": "2gb", "timeout": "0"})
+	c.Check(f.out.String(), Equals,
+		"*4\r\n$6\r\nconfig\r\n$3\r\nget\r\n$4\r\nmax*\r\n$7\r\ntimeout\r\n")
+
+	n, err := cl.ConfigGetInt("maxmemory")
+	c.Assert(err, IsNil)
+	c.Check(n, Equals, int64(2<<30))
+	_, err = cl.ConfigGetInt("nope")
+	c.Check(err, ErrorMatches, "unknown configuration parameter nope")
+	_, err = cl.ConfigGet()
+	c.Check(err, NotNil)
+}
+
+func (s *ConfigSuite) TestConfigSet(c *C) {
+	cl, f := fakeClient("+OK\r\n+OK\r\n+OK\r\n-ERR Unknown option\r\n")
+	c.Check(cl.ConfigSet("maxmemory", 1<<20), IsNil)
+	c.Check(f.out.String(), Equals,
+		"*4\r\n$6\r\nconfig\r\n$3\r\nset\r\n$9\r\nmaxmemory\r\n$7\r\n1048576\r\n")
+	f.out.Reset()
+	c.Check(cl.ConfigRewrite(), IsNil)
+	c.Check(cl.ConfigResetStat(), IsNil)
+	c.Check(f.out.String(), Equals, "*2\r\n$6\r\nconfig\r\n$7\r\nrewrite\r\n"+
+		"*2\r\n$6\r\nconfig\r\n$9\r\nresetstat\r\n")
+	c.Check(cl.ConfigSet("nope", "1"), ErrorMatches, "ERR Unknown option")
+}
+
+func (s *ConfigSuite) TestParseConfigSize(c *C) {
+	for in, out := range map[string]int64{
+		"0": 0, "100": 100, "-1": -1, "1k": 1000, "1kb": 1024, "2KB": 2048,
+		"3m": 3e6, "3mb": 3 << 20, "1g": 1e9, "2gb": 2 << 30, "5b": 5,
+	} {
+		n, err := ParseConfigSize(in)
+		c.Check(err, IsNil)
+		c.Check(n, Equals, out)
+	}
+	for _, in := range []string{"", "gb", "1.5gb", "yes", "1tb"} {
+		_, err := ParseConfigSize(in)
+		c.Check(err, Equals, ParseError)
+	}
+}
@@ -0,0 +1,109 @@
+package redis
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+//* Counters
+
+var CounterOverflowError error = errors.New("increment or decrement would overflow")
+var NotNumericError error = errors.New("value is not a number")
+
+// IncrBy increments the integer value of the given key by the given amount, starting
+// from 0 for missing keys, and returns the new value. Values that are not integers fail
+// with NotNumericError and results that do not fit into an int64 with
+// CounterOverflowError.
+func (c *Client) IncrBy(key string, n int64) (int64, error) {
+	v, err := c.Cmd("incrby", key, n).Int64()
+	return v, counterError(err)
+}
+
+// DecrBy is like IncrBy, but decrements.
+func (c *Client) DecrBy(key string, n int64) (int64, error) {
+	v, err := c.Cmd("decrby", key, n).Int64()
+	return v, counterError(err)
+}
+
+// IncrByFloat is like IncrBy for floating point values.
+func (c *Client) IncrByFloat(key string, f float64) (float64, error) {
+	v, err := parseFloat(c.Cmd("incrbyfloat", key, formatFloat(f)))
+	return v, counterError(err)
+}
+
+// counterError classifies the errors of counter commands.
+func counterError(err error) error {
+	if err == nil {
+		return nil
+	}
+	msg := err.Error()
+	switch {
+	case strings.Contains(msg, "would overflow"), strings.Contains(msg, "would produce NaN"):
+		return CounterOverflowError
+	case strings.Contains(msg, "not an integer"), strings.Contains(msg, "not a valid float"):
+		return NotNumericError
+	}
+	return err
+}
+
+// Counter is an integer counter stored at a key.
+// If TTL is set, each update also sets the time to live of the key to TTL, so counters
+// that stop being updated expire. A TTL under a millisecond is rounded up to one.
+// Like Client, Counter is not safe for concurrent use.
+type Counter struct {
+	TTL time.Duration
+	c   *Client
+	key string
+}
+
+// NewCounter returns the counter at the given key.
+func NewCounter(c *Client, key string) *Counter {
+	return &Counter{c: c, key: key}
+}
+
+// Key returns the key of the counter.
+func (ct *Counter) Key() string {
+	return ct.key
+}
+
+// Get returns the value of the counter, 0 if the key does not exist.
+func (ct *Counter) Get() (int64, error) {
+	s, ok, err := optStr(ct.c.Cmd("get", ct.key))
+	if !ok {
+		return 0, err
+	}
+	n, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return 0, NotNumericError
+	}
+	return n, nil
+}
+
+// Incr increments the counter by the given amount and returns the new value.
+func (ct *Counter) Incr(n int64) (int64, error) {
+	return ct.update("incrby", n)
+}
+
+// Decr decrements the counter by the given amount and returns the new value.
+func (ct *Counter) Decr(n int64) (int64, error) {
+	return ct.update("decrby", n)
+}
+
+// update runs the given counter command and refreshes the time to live.
+func (ct *Counter) update(cmd string, n int64) (int64, error) {
+	if ct.TTL <= 0 {
+		v, err := ct.c.Cmd(cmd, ct.key, n).Int64()
+		return v, counterError(err)
+	}
+	replies := ct.c.flush([]*request{
+		{cmd: cmd, args: []interface{}{ct.key, n}},
+		{cmd: "pexpire", args: []interface{}{ct.key, ttlMillis(ct.TTL)}},
+	})
+	v, err := replies[0].Int64()
+	if r := replies[1]; r.Type == ErrorReply && err == nil {
+		err = r.Err
+	}
+	return v, counterError(err)
+}
@@ -0,0 +1,72 @@
+package redis
+
+import (
+	. "launchpad.net/gocheck"
+	"strings"
+	"time"
+)
+
+type CounterSuite struct{}
+
+var _ = Suite(&CounterSuite{})
+
+func (s *CounterSuite) TestIncr(c *C) {
+	cl, _ := fakeClient(":5\r\n-ERR increment or decrement would overflow\r\n" +
+		"-ERR value is not an integer or out of range\r\n$3\r\n1.5\r\n" +
+		"-ERR value is not a valid float\r\n")
+	n, err := cl.IncrBy("k", 5)
+	c.Assert(err, IsNil)
+	c.Check(n, Equals, int64(5))
+	_, err = cl.IncrBy("k", 1<<62)
+	c.Check(err, Equals, CounterOverflowError)
+	_, err = cl.DecrBy("s", 1)
+	c.Check(err, Equals, NotNumericError)
+	f, err := cl.IncrByFloat("k", 0.5)
+	c.Assert(err, IsNil)
+	c.Check(f, Equals, 1.5)
+	_, err = cl.IncrByFloat("s", 0.5)
+	c.Check(err, Equals, NotNumericError)
+}
+
+func (s *CounterSuite) TestCounter(c *C) {
+	cl, f := fakeClient("$-1\r\n:1\r\n:0\r\n:1\r\n")
+	ct := NewCounter(cl, "hits")
+	n, err := ct.Get()
+	c.Assert(err, IsNil)
+	c.Check(n, Equals, int64(0))
+	n, err = ct.Incr(1)
+	c.Assert(err, IsNil)
+	c.Check(n, Equals, int64(1))
+
+	ct.TTL = time.Minute
+	n, err = ct.Decr(1)
+	c.Assert(err, IsNil)
+	c.Check(n, Equals, int64(0))
+	c.Check(strings.HasSuffix(f.out.String(), "*3\r\n$6\r\ndecrby\r\n$4\r\nhits\r\n$1\r\n1\r\n"+
+		"*3\r\n$7\r\npexpire\r\n$4\r\nhits\r\n$5\r\n60000\r\n"), Equals, true)
+}
+
+func (s *CounterSuite) TestCounterKeepsPipeline(c *C) {
+	cl, f := fakeClient(":2\r\n:1\r\n$3\r\nbar\r\n")
+	ct := NewCounter(cl, "hits")
+	ct.TTL = time.Minute
+	cl.Append("get", "foo")
+	n, err := ct.Incr(2)
+	c.Assert(err, IsNil)
+	c.Check(n, Equals, int64(2))
+	c.Check(strings.HasPrefix(f.out.String(), "*3\r\n$6\r\nincrby\r\n"), Equals, true)
+	v, err := cl.GetReply().Str()
+	c.Assert(err, IsNil)
+	c.Check(v, Equals, "bar")
+	c.Check(cl.GetReply().Err, Equals, PipelineQueueEmptyError)
+}
+
+func (s *CounterSuite) TestCounterShortTTL(c *C) {
+	cl, f := fakeClient(":1\r\n:1\r\n")
+	ct := NewCounter(cl, "hits")
+	ct.TTL = time.Microsecond
+	_, err := ct.Incr(1)
+	c.Assert(err, IsNil)
+	c.Check(strings.HasSuffix(f.out.String(), "*3\r\n$7\r\npexpire\r\n$4\r\nhits\r\n$1\r\n1\r\n"),
+		Equals, true)
+}
@@ -0,0 +1,85 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+//* Debug
+
+var DebugDisabledError error = errors.New("debug commands are not enabled on the client")
+
+// DebugObjectInfo is the parsed output of DEBUG OBJECT.
+type DebugObjectInfo struct {
+	Refcount         int64
+	Encoding         string
+	SerializedLength int64         // Length of the value when saved to disk
+	Idle             time.Duration // Time since the last access
+	Fields           map[string]string
+}
+
+// DebugSleep makes the server sleep for the given duration, blocking all clients.
+// The read timeout of the client is extended by the duration. It fails with
+// DebugDisabledError unless EnableDebugCommands is set.
+func (c *Client) DebugSleep(d time.Duration) error {
+	if !c.EnableDebugCommands {
+		return DebugDisabledError
+	}
+	return c.blockingCmd(context.Background(), d, "debug", "sleep",
+		formatFloat(d.Seconds())).Err
+}
+
+// DebugObject returns low level information about the value at the given key. It
+// fails with DebugDisabledError unless EnableDebugCommands is set.
+func (c *Client) DebugObject(key string) (*DebugObjectInfo, error) {
+	if !c.EnableDebugCommands {
+		return nil, DebugDisabledError
+	}
+	s, err := c.Cmd("debug", "object", key).Str()
+	if err != nil {
+		return nil, err
+	}
+	return parseDebugObject(s)
+}
+
+// DebugSetActiveExpire enables or disables the active expiration of keys, so that
+// keys only expire when accessed. It fails with DebugDisabledError unless
+// EnableDebugCommands is set.
+func (c *Client) DebugSetActiveExpire(on bool) error {
+	if !c.EnableDebugCommands {
+		return DebugDisabledError
+	}
+	return c.Cmd("debug", "set-active-expire", on).Err
+}
+
+// parseDebugObject parses the output of DEBUG OBJECT, of the form
+// "Value at:0x7f6b refcount:1 encoding:embstr serializedlength:4 lru:1 lru_seconds_idle:5".
+func parseDebugObject(s string) (*DebugObjectInfo, error) {
+	info := &DebugObjectInfo{Fields: map[string]string{}}
+	for _, kv := range strings.Fields(s) {
+		if i := strings.IndexByte(kv, ':'); i >= 0 {
+			info.Fields[kv[:i]] = kv[i+1:]
+		}
+	}
+	info.Encoding = info.Fields["encoding"]
+	var idle int64
+	ints := map[string]*int64{
+		"refcount":         &info.Refcount,
+		"serializedlength": &info.SerializedLength,
+		"lru_seconds_idle": &idle,
+	}
+	for k, p := range ints {
+		if v, ok := info.Fields[k]; ok {
+			i, err := strconv.ParseInt(v, 10, 64)
+			if err != nil {
+				return nil, ParseError
+			}
+			*p = i
+		}
+	}
+	info.Idle = time.Duration(idle) * time.Second
+	return info, nil
+}
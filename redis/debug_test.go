@@ -0,0 +1,45 @@
+package redis
+
+import (
+	. "launchpad.net/gocheck"
+	"time"
+)
+
+type DebugSuite struct{}
+
+var _ = Suite(&DebugSuite{})
+
+func (s *DebugSuite) TestDebugDisabled(c *C) {
+	cl, f := fakeClient("")
+	c.Check(cl.DebugSleep(time.Second), Equals, DebugDisabledError)
+	_, err := cl.DebugObject("k")
+	c.Check(err, Equals, DebugDisabledError)
+	c.Check(cl.DebugSetActiveExpire(false), Equals, DebugDisabledError)
+	c.Check(f.out.Len(), Equals, 0)
+}
+
+func (s *DebugSuite) TestDebug(c *C) {
+	obj := "Value at:0x7f6b8c0 refcount:2 encoding:listpack serializedlength:12 lru:123 " +
+		"lru_seconds_idle:30"
+	cl, f := fakeClient("+OK\r\n+" + obj + "\r\n+OK\r\n-ERR no such key\r\n")
+	cl.EnableDebugCommands = true
+	cl.timeout = time.Second
+	c.Check(cl.DebugSleep(1500*time.Millisecond), IsNil)
+	c.Check(cl.timeout, Equals, time.Second)
+	info, err := cl.DebugObject("k")
+	c.Assert(err, IsNil)
+	c.Check(info.Refcount, Equals, int64(2))
+	c.Check(info.Encoding, Equals, "listpack")
+	c.Check(info.SerializedLength, Equals, int64(12))
+	c.Check(info.Idle, Equals, 30*time.Second)
+	c.Check(info.Fields["lru"], Equals, "123")
+	c.Check(cl.DebugSetActiveExpire(false), IsNil)
+	c.Check(f.out.String(), Equals, "*3\r\n$5\r\ndebug\r\n$5\r\nsleep\r\n$3\r\n1.5\r\n"+
+		"*3\r\n$5\r\ndebug\r\n$6\r\nobject\r\n$1\r\nk\r\n"+
+		"*3\r\n$5\r\ndebug\r\n$17\r\nset-active-expire\r\n$1\r\n0\r\n")
+	_, err = cl.DebugObject("missing")
+	c.Check(err, ErrorMatches, "ERR no such key")
+
+	_, err = parseDebugObject("Value at:0x1 refcount:x")
+	c.Check(err, Equals, ParseError)
+}
@@ -0,0 +1,147 @@
+package redis
+
+import (
+	"time"
+)
+
+//* Expiration
+
+// ExpireCondition is a condition of setting a time to live (Redis 7.0 or later).
+type ExpireCondition string
+
+const (
+	ExpireAlways ExpireCondition = ""   // Always set the time to live
+	ExpireNX     ExpireCondition = "nx" // Only if the key has no time to live
+	ExpireXX     ExpireCondition = "xx" // Only if the key has a time to live
+	ExpireGT     ExpireCondition = "gt" // Only if the new time to live is greater
+	ExpireLT     ExpireCondition = "lt" // Only if the new time to live is less
+)
+
+// TTL results of keys without time to live and of missing keys.
+const (
+	NoExpiry   time.Duration = -1
+	KeyMissing time.Duration = -2
+)
+
+// Expire sets the time to live of the given key, in seconds if it is whole seconds and
+// in milliseconds otherwise, and returns false if the key does not exist or the
+// condition is not met.
+func (c *Client) Expire(key string, ttl time.Duration, cond ExpireCondition) (bool, error) {
+	if ttl%time.Second != 0 {
+		return c.PExpire(key, ttl, cond)
+	}
+	return c.expire(cond, "expire", key, int64(ttl/time.Second))
+}
+
+// PExpire is like Expire, but in milliseconds. Times to live under a millisecond are
+// rounded up to one, so that they do not delete the key.
+func (c *Client) PExpire(key string, ttl time.Duration, cond ExpireCondition) (bool, error) {
+	return c.expire(cond, "pexpire", key, ttlMillis(ttl))
+}
+
+// ExpireAt makes the given key expire at the given time, in seconds if it is a whole
+// second and in milliseconds otherwise, and returns false if the key does not exist or
+// the condition is not met.
+func (c *Client) ExpireAt(key string, t time.Time, cond ExpireCondition) (bool, error) {
+	if t.Nanosecond() != 0 {
+		ms := t.UnixNano() / int64(time.Millisecond)
+		return c.expire(cond, "pexpireat", key, ms)
+	}
+	return c.expire(cond, "expireat", key, t.Unix())
+}
+
+// Persist removes the time to live of the given key and returns false if the key does
+// not exist or has no time to live.
+func (c *Client) Persist(key string) (bool, error) {
+	return c.Cmd("persist", key).Bool()
+}
+
+// TTL returns the time to live of the given key in milliseconds precision, NoExpiry if
+// it has none and KeyMissing if the key does not exist.
+func (c *Client) TTL(key string) (time.Duration, error) {
+	return parseTTL(c.Cmd("pttl", key))
+}
+
+// ttlMillis returns the given time to live in milliseconds, at least 1 if it is positive.
+func ttlMillis(ttl time.Duration) int64 {
+	ms := int64(ttl / time.Millisecond)
+	if ms == 0 && ttl > 0 {
+		ms = 1
+	}
+	return ms
+}
+
+// expire calls the given expiration command, conditions requiring Redis 7.0.
+func (c *Client) expire(cond ExpireCondition, cmd, key string, n int64) (bool, error) {
+	if cond == ExpireAlways {
+		return c.Cmd(cmd, key, n).Bool()
+	}
+	return c.cmdSince(Version{7, 0, 0}, cmd, key, n, string(cond)).Bool()
+}
+
+// parseTTL parses a PTTL reply.
+func parseTTL(r *Reply) (time.Duration, error) {
+	ms, err := r.Int64()
+	if err != nil {
+		return 0, err
+	}
+	if ms < 0 {
+		return time.Duration(ms), nil
+	}
+	return time.Duration(ms) * time.Millisecond, nil
+}
+
+// TTLs returns the time to live of each of the given keys, see TTL, with one pipeline.
+func (c *Client) TTLs(keys ...string) (map[string]time.Duration, error) {
+	ttls := make(map[string]time.Duration, len(keys))
+	return ttls, c.pttls(keys, ttls)
+}
+
+// ScanTTLs returns the time to live of each key matching the given pattern, see Scan.
+// The keys are scanned with the given COUNT hint and their times to live are read
+// with a pipeline per page. Keys that expire during the scan are left out.
+func (c *Client) ScanTTLs(match string, count int) (map[string]time.Duration, error) {
+	ttls := map[string]time.Duration{}
+	it := c.Scan(match, count)
+	var keys []string
+	for it.Next() {
+		keys = append(keys, it.Val())
+		if len(it.buf) > 0 {
+			continue
+		}
+		if err := c.pttls(keys, ttls); err != nil {
+			return nil, err
+		}
+		keys = keys[:0]
+	}
+	if err := it.Err(); err != nil {
+		return nil, err
+	}
+	for k, ttl := range ttls {
+		if ttl == KeyMissing {
+			delete(ttls, k)
+		}
+	}
+	return ttls, nil
+}
+
+// pttls reads the times to live of the given keys into ttls with a pipeline.
+func (c *Client) pttls(keys []string, ttls map[string]time.Duration) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	reqs := make([]*request, len(keys))
+	for i, k := range keys {
+		reqs[i] = &request{cmd: "pttl", args: []interface{}{k}}
+	}
+	var err error
+	for i, r := range c.flush(reqs) {
+		k := keys[i]
+		ttl, e := parseTTL(r)
+		if e != nil && err == nil {
+			err = e
+		}
+		ttls[k] = ttl
+	}
+	return err
+}
@@ -0,0 +1,85 @@
+package redis
+
+import (
+	. "launchpad.net/gocheck"
+	"time"
+)
+
+type ExpireSuite struct{}
+
+var _ = Suite(&ExpireSuite{})
+
+func (s *ExpireSuite) TestExpire(c *C) {
+	cl, f := fakeClient(":1\r\n:0\r\n:1\r\n:1\r\n")
+	ok, err := cl.Expire("k", 90*time.Second, ExpireGT)
+	c.Assert(err, IsNil)
+	c.Check(ok, Equals, true)
+	ok, err = cl.PExpire("k", 1500*time.Millisecond, ExpireAlways)
+	c.Assert(err, IsNil)
+	c.Check(ok, Equals, false)
+	_, err = cl.ExpireAt("k", time.Unix(100, 0), ExpireNX)
+	c.Assert(err, IsNil)
+	_, err = cl.ExpireAt("k", time.Unix(100, int64(5*time.Millisecond)), ExpireAlways)
+	c.Assert(err, IsNil)
+	c.Check(f.out.String(), Equals, "*4\r\n$6\r\nexpire\r\n$1\r\nk\r\n$2\r\n90\r\n$2\r\ngt\r\n"+
+		"*3\r\n$7\r\npexpire\r\n$1\r\nk\r\n$4\r\n1500\r\n"+
+		"*4\r\n$8\r\nexpireat\r\n$1\r\nk\r\n$3\r\n100\r\n$2\r\nnx\r\n"+
+		"*3\r\n$9\r\npexpireat\r\n$1\r\nk\r\n$6\r\n100005\r\n")
+}
+
+func (s *ExpireSuite) TestExpireSubSecond(c *C) {
+	cl, f := fakeClient(":1\r\n")
+	ok, err := cl.Expire("k", 500*time.Millisecond, ExpireAlways)
+	c.Assert(err, IsNil)
+	c.Check(ok, Equals, true)
+	c.Check(f.out.String(), Equals, "*3\r\n$7\r\npexpire\r\n$1\r\nk\r\n$3\r\n500\r\n")
+
+	// never 0, which would delete the key
+	cl, f = fakeClient(":1\r\n")
+	_, err = cl.Expire("k", time.Microsecond, ExpireAlways)
+	c.Assert(err, IsNil)
+	c.Check(f.out.String(), Equals, "*3\r\n$7\r\npexpire\r\n$1\r\nk\r\n$1\r\n1\r\n")
+}
+
+func (s *ExpireSuite) TestTTL(c *C) {
+	cl, _ := fakeClient(":1500\r\n:-1\r\n:-2\r\n")
+	ttl, err := cl.TTL("k")
+	c.Assert(err, IsNil)
+	c.Check(ttl, Equals, 1500*time.Millisecond)
+	ttl, _ = cl.TTL("k")
+	c.Check(ttl, Equals, NoExpiry)
+	ttl, _ = cl.TTL("k")
+	c.Check(ttl, Equals, KeyMissing)
+}
+
+func (s *ExpireSuite) TestTTLs(c *C) {
+	cl, _ := fakeClient(":1000\r\n:-2\r\n")
+	ttls, err := cl.TTLs("a", "b")
+	c.Assert(err, IsNil)
+	c.Check(ttls, DeepEquals, map[string]time.Duration{"a": time.Second, "b": KeyMissing})
+}
+
+func (s *ExpireSuite) TestTTLsKeepsPipeline(c *C) {
+	cl, f := fakeClient(":1000\r\n$3\r\nbar\r\n")
+	cl.Append("get", "foo")
+	ttls, err := cl.TTLs("a")
+	c.Assert(err, IsNil)
+	c.Check(ttls, DeepEquals, map[string]time.Duration{"a": time.Second})
+	c.Check(f.out.String(), Equals, "*2\r\n$4\r\npttl\r\n$1\r\na\r\n")
+	v, err := cl.GetReply().Str()
+	c.Assert(err, IsNil)
+	c.Check(v, Equals, "bar")
+	c.Check(cl.GetReply().Err, Equals, PipelineQueueEmptyError)
+}
+
+func (s *ExpireSuite) TestScanTTLs(c *C) {
+	cl, f := fakeClient("*2\r\n$1\r\n7\r\n*2\r\n$1\r\na\r\n$1\r\nb\r\n:-1\r\n:-2\r\n" +
+		"*2\r\n$1\r\n0\r\n*1\r\n$1\r\nc\r\n:5\r\n")
+	ttls, err := cl.ScanTTLs("", 0)
+	c.Assert(err, IsNil)
+	c.Check(ttls, DeepEquals, map[string]time.Duration{"a": NoExpiry,
+		"c": 5 * time.Millisecond})
+	c.Check(f.out.String(), Equals, "*2\r\n$4\r\nscan\r\n$1\r\n0\r\n"+
+		"*2\r\n$4\r\npttl\r\n$1\r\na\r\n*2\r\n$4\r\npttl\r\n$1\r\nb\r\n"+
+		"*2\r\n$4\r\nscan\r\n$1\r\n7\r\n*2\r\n$4\r\npttl\r\n$1\r\nc\r\n")
+}
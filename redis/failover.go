@@ -0,0 +1,232 @@
+package redis
+
+import (
+	"log/slog"
+	"sync"
+	"time"
+)
+
+//* Failover
+
+// FailoverPolicy describes when a FailoverClient switches between its primary and standby.
+// The client fails over after Failures consecutive failed health checks and, if FailBack
+// is set, fails back after as many consecutive successful ones.
+// Zero Interval and Failures are replaced with the values of DefaultFailoverPolicy.
+// The health checks time out after the timeout of the client, or Interval if it has none.
+type FailoverPolicy struct {
+	Interval time.Duration // Interval of the primary health checks
+	Failures int
+	FailBack bool // Switch back to the primary once it has recovered
+	Promote  bool // Send REPLICAOF NO ONE to the standby when failing over
+}
+
+// DefaultFailoverPolicy is the failover policy used by DialFailover.
+var DefaultFailoverPolicy = FailoverPolicy{Interval: time.Second, Failures: 3}
+
+// FailoverClient describes a client for a primary server with a warm standby,
+// for replicated setups without Sentinel.
+// The primary is health-checked with PING in the background. After sustained failure,
+// the command in progress fails with FailoverError and the following commands are sent
+// to the standby.
+// Like Client, FailoverClient is not safe for concurrent use.
+type FailoverClient struct {
+	network   string
+	primary   string
+	standby   string
+	timeout   time.Duration
+	policy    FailoverPolicy
+	mu        sync.Mutex // guards the fields below
+	client    *Client
+	active    string
+	switched  bool
+	closed    bool
+	failures  int
+	successes int
+}
+
+// DialFailoverTimeout connects to the given primary, or to the standby if the primary
+// is unreachable, with the given timeout and failover policy.
+func DialFailoverTimeout(network, primary, standby string, timeout time.Duration,
+	policy FailoverPolicy) (*FailoverClient, error) {
+	if policy.Interval <= 0 {
+		policy.Interval = DefaultFailoverPolicy.Interval
+	}
+	if policy.Failures <= 0 {
+		policy.Failures = DefaultFailoverPolicy.Failures
+	}
+	fc := &FailoverClient{
+		network: network,
+		primary: primary,
+		standby: standby,
+		timeout: timeout,
+		policy:  policy,
+		active:  primary,
+	}
+	c, err := DialTimeout(network, primary, timeout)
+	if err != nil {
+		if c, err = DialTimeout(network, standby, timeout); err != nil {
+			return nil, err
+		}
+		fc.active = standby
+	}
+	fc.client = c
+	go fc.check()
+	return fc, nil
+}
+
+// DialFailover connects to the given primary, or to the standby if the primary
+// is unreachable, with DefaultFailoverPolicy.
+func DialFailover(network, primary, standby string) (*FailoverClient, error) {
+	return DialFailoverTimeout(network, primary, standby, time.Duration(0),
+		DefaultFailoverPolicy)
+}
+
+//* Public methods
+
+// Close closes the connection and stops the health checks.
+func (fc *FailoverClient) Close() error {
+	fc.mu.Lock()
+	defer fc.mu.Unlock()
+	fc.closed = true
+	if fc.client == nil {
+		return nil
+	}
+	err := fc.client.Close()
+	fc.client = nil
+	return err
+}
+
+// Addr returns the address of the server in use.
+func (fc *FailoverClient) Addr() string {
+	fc.mu.Lock()
+	defer fc.mu.Unlock()
+	return fc.active
+}
+
+// Cmd calls the given Redis command on the server in use.
+func (fc *FailoverClient) Cmd(cmd string, args ...interface{}) *Reply {
+	c, err := fc.conn()
+	if err != nil {
+		return &Reply{Type: ErrorReply, Err: err}
+	}
+	r := c.Cmd(cmd, args...)
+	if r.Type == ErrorReply && isConnError(r.Err) {
+		logEvent(slog.LevelWarn, "redis: dropped connection", "addr", c.addr(), "error", r.Err)
+		fc.mu.Lock()
+		failover := fc.switched
+		if fc.client == c {
+			fc.client = nil
+		}
+		fc.mu.Unlock()
+		c.Close()
+		if failover {
+			r = &Reply{Type: ErrorReply, Err: FailoverError}
+		}
+	}
+	return r
+}
+
+//* Private methods
+
+// conn returns the Client of the server in use, connecting to it if needed.
+func (fc *FailoverClient) conn() (*Client, error) {
+	fc.mu.Lock()
+	c, addr := fc.client, fc.active
+	fc.mu.Unlock()
+	if c != nil {
+		return c, nil
+	}
+
+	c, err := DialTimeout(fc.network, addr, fc.timeout)
+	if err != nil {
+		return nil, err
+	}
+	fc.mu.Lock()
+	defer fc.mu.Unlock()
+	if fc.active != addr || fc.closed {
+		// switched while connecting
+		c.Close()
+		return nil, FailoverError
+	}
+	fc.client, fc.switched = c, false
+	return c, nil
+}
+
+// check health-checks the primary until fc is closed. The checks time out after the
+// timeout of the client, or the check interval if it has none, so that an unresponsive
+// primary fails them.
+func (fc *FailoverClient) check() {
+	timeout := fc.timeout
+	if timeout <= 0 {
+		timeout = fc.policy.Interval
+	}
+	var hc *Client
+	defer func() {
+		if hc != nil {
+			hc.Close()
+		}
+	}()
+	for {
+		time.Sleep(fc.policy.Interval)
+		fc.mu.Lock()
+		closed := fc.closed
+		fc.mu.Unlock()
+		if closed {
+			return
+		}
+
+		var err error
+		if hc == nil {
+			hc, err = DialTimeout(fc.network, fc.primary, timeout)
+		}
+		if err == nil {
+			if r := hc.Cmd("ping"); r.Type == ErrorReply {
+				hc.Close()
+				hc, err = nil, r.Err
+			}
+		}
+		if to := fc.observe(err == nil); to == fc.standby && fc.policy.Promote {
+			s, err := DialTimeout(fc.network, fc.standby, timeout)
+			if err == nil {
+				err = s.ReplicaOf("")
+				s.Close()
+			}
+			if err != nil {
+				logEvent(slog.LevelError, "redis: promoting standby failed", "addr", fc.standby,
+					"error", err)
+			}
+		}
+	}
+}
+
+// observe records the result of a health check of the primary and switches servers
+// according to the policy. The address switched to, if any, is returned.
+func (fc *FailoverClient) observe(ok bool) string {
+	fc.mu.Lock()
+	defer fc.mu.Unlock()
+	if ok {
+		fc.failures = 0
+		fc.successes++
+	} else {
+		fc.successes = 0
+		fc.failures++
+	}
+
+	to := ""
+	switch {
+	case fc.active == fc.primary && fc.failures >= fc.policy.Failures:
+		to = fc.standby
+	case fc.active == fc.standby && fc.policy.FailBack && fc.successes >= fc.policy.Failures:
+		to = fc.primary
+	default:
+		return ""
+	}
+	logEvent(slog.LevelWarn, "redis: failing over", "from", fc.active, "to", to)
+	fc.active, fc.switched = to, true
+	if fc.client != nil {
+		// fail the command in progress, the next one connects to the new server
+		fc.client.Close()
+		fc.client = nil
+	}
+	return to
+}
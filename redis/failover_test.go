@@ -0,0 +1,94 @@
+package redis
+
+import (
+	. "launchpad.net/gocheck"
+	"time"
+)
+
+type FailoverSuite struct{}
+
+var _ = Suite(&FailoverSuite{})
+
+func (s *FailoverSuite) TestObserve(c *C) {
+	fc := &FailoverClient{primary: "p", standby: "s", active: "p",
+		policy: FailoverPolicy{Failures: 2, FailBack: true}}
+	c.Check(fc.observe(false), Equals, "")
+	c.Check(fc.observe(true), Equals, "")
+	c.Check(fc.observe(false), Equals, "")
+	c.Check(fc.observe(false), Equals, "s")
+	c.Check(fc.Addr(), Equals, "s")
+	c.Check(fc.observe(true), Equals, "")
+	c.Check(fc.observe(true), Equals, "p")
+	c.Check(fc.Addr(), Equals, "p")
+
+	fc.policy.FailBack = false
+	fc.observe(false)
+	fc.observe(false)
+	for i := 0; i < 5; i++ {
+		c.Check(fc.observe(true), Equals, "")
+	}
+	c.Check(fc.Addr(), Equals, "s")
+}
+
+func (s *FailoverSuite) TestFailover(c *C) {
+	// the primary answers one health check and then goes away
+	primary := fakeServer(c, "", "+PONG\r\n")
+	standby := fakeServer(c, "$3\r\nbar\r\n")
+	fc, err := DialFailoverTimeout("tcp", primary, standby, 50*time.Millisecond,
+		FailoverPolicy{Interval: 10 * time.Millisecond, Failures: 2})
+	c.Assert(err, IsNil)
+	defer fc.Close()
+	c.Check(fc.Addr(), Equals, primary)
+
+	for i := 0; i < 100 && fc.Addr() == primary; i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+	c.Check(fc.Addr(), Equals, standby)
+	v, err := fc.Cmd("get", "foo").Str()
+	c.Assert(err, IsNil)
+	c.Check(v, Equals, "bar")
+}
+
+func (s *FailoverSuite) TestUnresponsivePrimary(c *C) {
+	// the primary accepts connections but never replies
+	primary := fakeServer(c, "", "")
+	standby := fakeServer(c, "")
+	fc, err := DialFailoverTimeout("tcp", primary, standby, 0,
+		FailoverPolicy{Interval: 10 * time.Millisecond, Failures: 2})
+	c.Assert(err, IsNil)
+	defer fc.Close()
+
+	for i := 0; i < 100 && fc.Addr() == primary; i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+	c.Check(fc.Addr(), Equals, standby)
+}
+
+func (s *FailoverSuite) TestZeroPolicy(c *C) {
+	primary := fakeServer(c, "")
+	fc, err := DialFailoverTimeout("tcp", primary, "127.0.0.1:1", 0, FailoverPolicy{})
+	c.Assert(err, IsNil)
+	defer fc.Close()
+	c.Check(fc.policy.Interval, Equals, DefaultFailoverPolicy.Interval)
+	c.Check(fc.policy.Failures, Equals, DefaultFailoverPolicy.Failures)
+
+	// a healthy primary is kept
+	c.Check(fc.observe(true), Equals, "")
+	c.Check(fc.Addr(), Equals, primary)
+
+	primary = fakeServer(c, "")
+	fc, err = DialFailoverTimeout("tcp", primary, "127.0.0.1:1", 0,
+		FailoverPolicy{Interval: -time.Second, Failures: -1, FailBack: true})
+	c.Assert(err, IsNil)
+	defer fc.Close()
+	c.Check(fc.policy, Equals, FailoverPolicy{Interval: time.Second, Failures: 3,
+		FailBack: true})
+}
+
+func (s *FailoverSuite) TestDialStandby(c *C) {
+	standby := fakeServer(c, "")
+	fc, err := DialFailover("tcp", "127.0.0.1:1", standby)
+	c.Assert(err, IsNil)
+	defer fc.Close()
+	c.Check(fc.Addr(), Equals, standby)
+}
@@ -2,130 +2,275 @@ package redis
 
 import (
 	"bytes"
+	"context"
+	"encoding"
 	"fmt"
+	"math/big"
 	"reflect"
+	"sort"
 	"strconv"
+	"time"
 )
 
 var delim []byte = []byte{'\r', '\n'}
 
 type request struct {
-	cmd  string
-	args []interface{}
+	cmd      string
+	args     []interface{}
+	fallback *request // called instead if the request fails with NOSCRIPT
+	ctx      context.Context
 }
 
-// formatArg formats the given argument to a Redis-styled argument byte slice.
-func formatArg(v interface{}) []byte {
-	var b, bs []byte
+//* Argument encoding
+
+// ArgTypeError is the error of commands with arguments of unsupported types.
+type ArgTypeError struct {
+	Type reflect.Type
+}
+
+func (e *ArgTypeError) Error() string {
+	return "unsupported argument type " + e.Type.String()
+}
+
+// TimeFormat is an encoding of time.Time arguments.
+type TimeFormat int
+
+const (
+	TimeUnix      TimeFormat = iota // Unix time in seconds, the default
+	TimeUnixMilli                   // Unix time in milliseconds
+	TimeRFC3339                     // time.RFC3339Nano
+)
 
+// Command arguments are encoded as follows:
+//
+//   - strings and byte slices as they are, nil and nil pointers as empty strings
+//   - integers in decimal, floats in the shortest representation, booleans as 1 or 0
+//   - time.Time in the TimeArgFormat, time.Duration in whole DurationArgUnits
+//   - *big.Int in decimal
+//   - encoding.TextMarshaler, encoding.BinaryMarshaler and fmt.Stringer values as their
+//     text, binary and string form, in that order of preference
+//   - pointers as the values they point to
+//   - slices and arrays as their elements, maps as their key value pairs ordered by key
+//   - structs as their field name value pairs, see PairsArg
+//
+// Slices, maps and structs are expanded in place, even when nested, so that e.g.
+//
+//	c.Cmd("hset", "user:1", User{Name: "ann", Age: 30})
+//	c.Cmd("xadd", "events", "*", map[string]interface{}{"type": "login"})
+//
+// send HSET user:1 name ann age 30 and XADD events * type login.
+//
+// Commands with arguments of other types fail with an *ArgTypeError without being sent.
+// Set TimeArgFormat and DurationArgUnit before using any client, they must not be
+// changed concurrently.
+var (
+	TimeArgFormat   TimeFormat    = TimeUnix
+	DurationArgUnit time.Duration = time.Millisecond
+)
+
+// encodeArg appends the Redis arguments of the given value to args.
+func encodeArg(args [][]byte, v interface{}) ([][]byte, error) {
 	switch vt := v.(type) {
+	case nil:
+		return append(args, []byte{}), nil
 	case []byte:
-		bs = vt
+		return append(args, vt), nil
 	case string:
-		bs = []byte(vt)
+		return append(args, []byte(vt)), nil
 	case bool:
 		if vt {
-			bs = []byte{'1'}
-		} else {
-			bs = []byte{'0'}
+			return append(args, []byte{'1'}), nil
 		}
-	case nil:
-		// empty byte slice
+		return append(args, []byte{'0'}), nil
 	case int:
-		bs = []byte(strconv.Itoa(vt))
-	case int8:
-		bs = []byte(strconv.FormatInt(int64(vt), 10))
-	case int16:
-		bs = []byte(strconv.FormatInt(int64(vt), 10))
-	case int32:
-		bs = []byte(strconv.FormatInt(int64(vt), 10))
+		return append(args, []byte(strconv.Itoa(vt))), nil
 	case int64:
-		bs = []byte(strconv.FormatInt(vt, 10))
-	case uint:
-		bs = []byte(strconv.FormatUint(uint64(vt), 10))
-	case uint8:
-		bs = []byte(strconv.FormatUint(uint64(vt), 10))
-	case uint16:
-		bs = []byte(strconv.FormatUint(uint64(vt), 10))
-	case uint32:
-		bs = []byte(strconv.FormatUint(uint64(vt), 10))
-	case uint64:
-		bs = []byte(strconv.FormatUint(vt, 10))
-	default:
-		// Fallback to reflect-based.
-		switch reflect.TypeOf(vt).Kind() {
-		case reflect.Slice:
-			rv := reflect.ValueOf(vt)
-			for i := 0; i < rv.Len(); i++ {
-				bs = append(bs, formatArg(rv.Index(i).Interface())...)
-			}
-
-			return bs
-		case reflect.Map:
-			rv := reflect.ValueOf(vt)
-			keys := rv.MapKeys()
-			for _, k := range keys {
-				bs = append(bs, formatArg(k.Interface())...)
-				bs = append(bs, formatArg(rv.MapIndex(k).Interface())...)
-			}
+		return append(args, []byte(strconv.FormatInt(vt, 10))), nil
+	case float64:
+		return append(args, []byte(strconv.FormatFloat(vt, 'g', -1, 64))), nil
+	case time.Time:
+		switch TimeArgFormat {
+		case TimeUnixMilli:
+			ms := vt.UnixNano() / int64(time.Millisecond)
+			return append(args, []byte(strconv.FormatInt(ms, 10))), nil
+		case TimeRFC3339:
+			return append(args, []byte(vt.Format(time.RFC3339Nano))), nil
+		}
+		return append(args, []byte(strconv.FormatInt(vt.Unix(), 10))), nil
+	case time.Duration:
+		n := int64(vt / DurationArgUnit)
+		return append(args, []byte(strconv.FormatInt(n, 10))), nil
+	case *big.Int:
+		if vt == nil {
+			return append(args, []byte{}), nil
+		}
+		return append(args, []byte(vt.String())), nil
+	}
 
-			return bs
-		default:
-			var buf bytes.Buffer
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return append(args, []byte{}), nil
+		}
+		// *time.Time and *time.Duration are marshalers, but encode like their values
+		switch ev := rv.Elem().Interface().(type) {
+		case []byte, string, bool, int, int64, float64, time.Time, time.Duration:
+			return encodeArg(args, ev)
+		}
+	}
+	switch vt := v.(type) {
+	case encoding.TextMarshaler:
+		b, err := vt.MarshalText()
+		return append(args, b), err
+	case encoding.BinaryMarshaler:
+		b, err := vt.MarshalBinary()
+		return append(args, b), err
+	case fmt.Stringer:
+		return append(args, []byte(vt.String())), nil
+	}
 
-			fmt.Fprint(&buf, v)
-			bs = buf.Bytes()
+	var err error
+	switch rv.Kind() {
+	case reflect.Ptr:
+		return encodeArg(args, rv.Elem().Interface())
+	case reflect.String:
+		return append(args, []byte(rv.String())), nil
+	case reflect.Bool:
+		return encodeArg(args, rv.Bool())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return encodeArg(args, rv.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Uintptr:
+		return append(args, []byte(strconv.FormatUint(rv.Uint(), 10))), nil
+	case reflect.Float32:
+		return append(args, []byte(strconv.FormatFloat(rv.Float(), 'g', -1, 32))), nil
+	case reflect.Float64:
+		return encodeArg(args, rv.Float())
+	case reflect.Slice, reflect.Array:
+		if rv.Type().Elem().Kind() == reflect.Uint8 {
+			b := make([]byte, rv.Len())
+			reflect.Copy(reflect.ValueOf(b), rv)
+			return append(args, b), nil
+		}
+		for i := 0; i < rv.Len() && err == nil; i++ {
+			args, err = encodeArg(args, rv.Index(i).Interface())
+		}
+		return args, err
+	case reflect.Map:
+		type pair struct{ k, v [][]byte }
+		pairs := make([]pair, rv.Len())
+		for i, k := range rv.MapKeys() {
+			if pairs[i].k, err = encodeArg(nil, k.Interface()); err != nil {
+				return nil, err
+			}
+			if pairs[i].v, err = encodeArg(nil, rv.MapIndex(k).Interface()); err != nil {
+				return nil, err
+			}
 		}
+		sort.Slice(pairs, func(i, j int) bool {
+			return bytes.Compare(bytes.Join(pairs[i].k, nil), bytes.Join(pairs[j].k, nil)) < 0
+		})
+		for _, p := range pairs {
+			args = append(append(args, p.k...), p.v...)
+		}
+		return args, nil
+	case reflect.Struct:
+		pairs, err := PairsArg(v)
+		if err != nil {
+			return nil, err
+		}
+		return encodeArg(args, pairs)
 	}
+	return nil, &ArgTypeError{rv.Type()}
+}
 
+// appendBulk appends the given argument as a Redis bulk string to b.
+func appendBulk(b, arg []byte) []byte {
 	b = append(b, '$')
-	b = append(b, []byte(strconv.Itoa(len(bs)))...)
-	b = append(b, delim...)
-	b = append(b, bs...)
+	b = strconv.AppendInt(b, int64(len(arg)), 10)
 	b = append(b, delim...)
+	b = append(b, arg...)
+	return append(b, delim...)
+}
+
+// formatArg formats the given argument as Redis bulk strings, see encodeArg.
+// Arguments that cannot be encoded are left out.
+func formatArg(v interface{}) []byte {
+	args, _ := encodeArg(nil, v)
+	var b []byte
+	for _, arg := range args {
+		b = appendBulk(b, arg)
+	}
 	return b
 }
 
+// argBytes returns the given non-slice, non-map argument as it is sent to Redis.
+func argBytes(v interface{}) []byte {
+	args, _ := encodeArg(nil, v)
+	if len(args) == 0 {
+		return nil
+	}
+	return args[0]
+}
+
+// flattenArgs expands slice, array, map and struct arguments in place, like encodeArg
+// does, except that map pairs are not ordered.
+func flattenArgs(args []interface{}) []interface{} {
+	var flat []interface{}
+	for _, arg := range args {
+		switch arg.(type) {
+		case []byte, string, nil, time.Time, encoding.TextMarshaler, encoding.BinaryMarshaler,
+			fmt.Stringer:
+			flat = append(flat, arg)
+			continue
+		}
+		rv := reflect.ValueOf(arg)
+		switch rv.Kind() {
+		case reflect.Slice, reflect.Array:
+			if rv.Type().Elem().Kind() == reflect.Uint8 {
+				flat = append(flat, arg)
+				continue
+			}
+			for i := 0; i < rv.Len(); i++ {
+				flat = append(flat, flattenArgs([]interface{}{rv.Index(i).Interface()})...)
+			}
+		case reflect.Map:
+			for _, k := range rv.MapKeys() {
+				flat = append(flat, flattenArgs([]interface{}{k.Interface()})...)
+				flat = append(flat, flattenArgs([]interface{}{rv.MapIndex(k).Interface()})...)
+			}
+		case reflect.Struct:
+			pairs, err := PairsArg(arg)
+			if err != nil {
+				flat = append(flat, arg)
+				continue
+			}
+			flat = append(flat, flattenArgs(pairs)...)
+		default:
+			flat = append(flat, arg)
+		}
+	}
+	return flat
+}
+
 // createRequest creates a request string from the given requests.
-func createRequest(requests ...*request) []byte {
+func createRequest(requests ...*request) ([]byte, error) {
 	var total []byte
-
 	for _, req := range requests {
-		var s []byte
-
-		// Calculate number of arguments.
-		argsLen := 1
+		args := [][]byte{[]byte(req.cmd)}
+		var err error
 		for _, arg := range req.args {
-			kind := reflect.TypeOf(arg).Kind()
-			switch kind {
-			case reflect.Slice:
-				argsLen += reflect.ValueOf(arg).Len()
-			case reflect.Map:
-				argsLen += reflect.ValueOf(arg).Len() * 2
-			default:
-				argsLen++
+			if args, err = encodeArg(args, arg); err != nil {
+				return nil, err
 			}
 		}
 
-		// number of arguments
-		s = append(s, '*')
-		s = append(s, []byte(strconv.Itoa(argsLen))...)
-		s = append(s, delim...)
-
-		// command
-		s = append(s, '$')
-		s = append(s, []byte(strconv.Itoa(len(req.cmd)))...)
-		s = append(s, delim...)
-		s = append(s, []byte(req.cmd)...)
-		s = append(s, delim...)
-
-		// arguments
-		for _, arg := range req.args {
-			s = append(s, formatArg(arg)...)
+		total = append(total, '*')
+		total = strconv.AppendInt(total, int64(len(args)), 10)
+		total = append(total, delim...)
+		for _, arg := range args {
+			total = appendBulk(total, arg)
 		}
-
-		total = append(total, s...)
 	}
-
-	return total
+	return total, nil
 }
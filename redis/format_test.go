@@ -2,6 +2,9 @@ package redis
 
 import (
 	. "launchpad.net/gocheck"
+	"math/big"
+	"net"
+	"time"
 )
 
 type FormatSuite struct{}
@@ -30,13 +33,33 @@ func (s *FormatSuite) TestFormatArg(c *C) {
 	c.Check(formatArg(1.5), DeepEquals, []byte("$3\r\n1.5\r\n"))
 }
 
+// mustCreateRequest returns the request string of the given requests.
+func mustCreateRequest(requests ...*request) []byte {
+	b, err := createRequest(requests...)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
 func (s *FormatSuite) TestCreateRequest(c *C) {
-	c.Check(createRequest(&request{cmd: "PING"}), DeepEquals, []byte("*1\r\n$4\r\nPING\r\n"))
-	c.Check(createRequest(&request{
+	c.Check(mustCreateRequest(&request{cmd: "PING"}), DeepEquals,
+		[]byte("*1\r\n$4\r\nPING\r\n"))
+	c.Check(mustCreateRequest(&request{
 		cmd:  "SET",
 		args: []interface{}{"key", 5},
 	}),
 		DeepEquals, []byte("*3\r\n$3\r\nSET\r\n$3\r\nkey\r\n$1\r\n5\r\n"))
+	c.Check(mustCreateRequest(&request{
+		cmd:  "SET",
+		args: []interface{}{"key", []byte("value")},
+	}),
+		DeepEquals, []byte("*3\r\n$3\r\nSET\r\n$3\r\nkey\r\n$5\r\nvalue\r\n"))
+	c.Check(mustCreateRequest(&request{
+		cmd:  "DEL",
+		args: []interface{}{[]interface{}{"a", []string{"b", "c"}}},
+	}),
+		DeepEquals, []byte("*4\r\n$3\r\nDEL\r\n$1\r\na\r\n$1\r\nb\r\n$1\r\nc\r\n"))
 }
 
 func (s *FormatSuite) BenchmarkCreateRequest(c *C) {
@@ -56,3 +79,93 @@ func (s *FormatSuite) BenchmarkCreateRequestSlice(c *C) {
 		})
 	}
 }
+
+func (s *FormatSuite) TestArgBytes(c *C) {
+	c.Check(argBytes("foo"), DeepEquals, []byte("foo"))
+	c.Check(argBytes(5), DeepEquals, []byte("5"))
+	c.Check(argBytes([]byte("a\r\nb")), DeepEquals, []byte("a\r\nb"))
+}
+
+func (s *FormatSuite) TestFlattenArgs(c *C) {
+	c.Check(flattenArgs([]interface{}{"a", []string{"b", "c"}, []byte("d"), 1}), DeepEquals,
+		[]interface{}{"a", "b", "c", []byte("d"), 1})
+	c.Check(flattenArgs([]interface{}{map[string]int{"k": 1}}), DeepEquals,
+		[]interface{}{"k", 1})
+	c.Check(flattenArgs([]interface{}{map[string][]string{"k": {"a", "b"}}}), DeepEquals,
+		[]interface{}{"k", "a", "b"})
+}
+
+func (s *FormatSuite) TestEncodeArg(c *C) {
+	t := time.Unix(1700000000, int64(250*time.Millisecond)).UTC()
+	var nilInt *big.Int
+	args, err := encodeArg(nil, []interface{}{t, 1500 * time.Millisecond, big.NewInt(1 << 62),
+		net.IPv4(10, 0, 0, 1), nilInt, float32(0.25), [2]byte{'h', 'i'},
+		map[string]int{"b": 2, "a": 1}})
+	c.Assert(err, IsNil)
+	l := make([]string, len(args))
+	for i, a := range args {
+		l[i] = string(a)
+	}
+	c.Check(l, DeepEquals, []string{"1700000000", "1500", "4611686018427387904", "10.0.0.1",
+		"", "0.25", "hi", "a", "1", "b", "2"})
+
+	TimeArgFormat, DurationArgUnit = TimeRFC3339, time.Second
+	defer func() { TimeArgFormat, DurationArgUnit = TimeUnix, time.Millisecond }()
+	c.Check(argBytes(t), DeepEquals, []byte("2023-11-14T22:13:20.25Z"))
+	c.Check(argBytes(1500*time.Millisecond), DeepEquals, []byte("1"))
+	TimeArgFormat = TimeUnixMilli
+	c.Check(argBytes(t), DeepEquals, []byte("1700000000250"))
+
+	_, err = encodeArg(nil, func() {})
+	c.Check(err, FitsTypeOf, &ArgTypeError{})
+	_, err = encodeArg(nil, []interface{}{"a", make(chan int)})
+	c.Check(err, ErrorMatches, "unsupported argument type chan int")
+}
+
+func (s *FormatSuite) TestEncodePointer(c *C) {
+	t := time.Unix(1700000000, 0)
+	d := 1500 * time.Millisecond
+	str := "s"
+	c.Check(argBytes(&t), DeepEquals, []byte("1700000000"))
+	c.Check(argBytes(&d), DeepEquals, []byte("1500"))
+	c.Check(argBytes(&str), DeepEquals, []byte("s"))
+
+	TimeArgFormat, DurationArgUnit = TimeUnixMilli, time.Second
+	defer func() { TimeArgFormat, DurationArgUnit = TimeUnix, time.Millisecond }()
+	c.Check(argBytes(&t), DeepEquals, []byte("1700000000000"))
+	c.Check(argBytes(&d), DeepEquals, []byte("1"))
+}
+
+func (s *FormatSuite) TestArgTypeError(c *C) {
+	cl, f := fakeClient("+OK\r\n")
+	r := cl.Cmd("set", "k", func() {})
+	c.Check(r.Err, FitsTypeOf, &ArgTypeError{})
+	c.Check(f.out.Len(), Equals, 0)
+
+	cl.Append("set", "k", complex(1, 1))
+	cl.Append("get", "k")
+	c.Check(cl.GetReply().Err, FitsTypeOf, &ArgTypeError{})
+	c.Check(cl.GetReply().Err, FitsTypeOf, &ArgTypeError{})
+	c.Check(cl.GetReply().Err, Equals, PipelineQueueEmptyError)
+	c.Check(cl.Cmd("ping").Type, Equals, StatusReply)
+}
+
+func (s *FormatSuite) TestEncodeStruct(c *C) {
+	type user struct {
+		Name string   `redis:"name"`
+		Age  int      `redis:"age,omitempty"`
+		Tags []string `redis:"tags,json"`
+	}
+	c.Check(mustCreateRequest(&request{cmd: "hset", args: []interface{}{"u",
+		user{Name: "ann", Tags: []string{"a"}}}}), DeepEquals,
+		[]byte("*6\r\n$4\r\nhset\r\n$1\r\nu\r\n$4\r\nname\r\n$3\r\nann\r\n"+
+			"$4\r\ntags\r\n$5\r\n[\"a\"]\r\n"))
+	c.Check(mustCreateRequest(&request{cmd: "xadd", args: []interface{}{"s", "*",
+		map[string]interface{}{"b": []int{1, 2}, "a": &user{Name: "bob", Age: 3}}}}),
+		DeepEquals, []byte("*13\r\n$4\r\nxadd\r\n$1\r\ns\r\n$1\r\n*\r\n"+
+			"$1\r\na\r\n$4\r\nname\r\n$3\r\nbob\r\n$3\r\nage\r\n$1\r\n3\r\n"+
+			"$4\r\ntags\r\n$4\r\nnull\r\n"+
+			"$1\r\nb\r\n$1\r\n1\r\n$1\r\n2\r\n"))
+	c.Check(flattenArgs([]interface{}{"k", user{Name: "ann"}, time.Unix(0, 0)}), DeepEquals,
+		[]interface{}{"k", "name", "ann", "tags", []byte("null"), time.Unix(0, 0)})
+}
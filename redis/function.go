@@ -0,0 +1,149 @@
+package redis
+
+import (
+	"errors"
+	"time"
+)
+
+//* Functions
+
+// FunctionRestorePolicy describes how FunctionRestore handles existing libraries.
+type FunctionRestorePolicy string
+
+const (
+	RestoreAppend  FunctionRestorePolicy = "APPEND"  // Fail if a library already exists
+	RestoreReplace FunctionRestorePolicy = "REPLACE" // Replace existing libraries
+	RestoreFlush   FunctionRestorePolicy = "FLUSH"   // Delete all libraries first
+)
+
+// FunctionListOptions describes the options of FunctionList.
+type FunctionListOptions struct {
+	LibraryName string // Pattern of the library names, empty for all libraries
+	WithCode    bool   // Return the source code of the libraries
+}
+
+// FunctionInfo describes a function of a library in FUNCTION LIST.
+type FunctionInfo struct {
+	Name        string
+	Description string
+	Flags       []string // no-writes, allow-oom, allow-stale, no-cluster, ...
+}
+
+// FunctionLibrary describes a library in FUNCTION LIST.
+type FunctionLibrary struct {
+	Name      string
+	Engine    string
+	Functions []FunctionInfo
+	Code      string // Only set if requested with WithCode
+}
+
+// FunctionLoad loads the given library (Redis 7.0 or later) and returns its name.
+// An existing library with the same name is replaced if replace is set.
+func (c *Client) FunctionLoad(code string, replace bool) (string, error) {
+	if replace {
+		return c.cmdSince(Version{7, 0, 0}, "function", "load", "replace", code).Str()
+	}
+	return c.cmdSince(Version{7, 0, 0}, "function", "load", code).Str()
+}
+
+// FunctionList returns the loaded libraries.
+func (c *Client) FunctionList(opt FunctionListOptions) ([]FunctionLibrary, error) {
+	args := []interface{}{"list"}
+	if opt.LibraryName != "" {
+		args = append(args, "libraryname", opt.LibraryName)
+	}
+	if opt.WithCode {
+		args = append(args, "withcode")
+	}
+	return parseFunctionList(c.cmdSince(Version{7, 0, 0}, "function", args...))
+}
+
+// FunctionDelete deletes the given library.
+func (c *Client) FunctionDelete(library string) error {
+	return c.cmdSince(Version{7, 0, 0}, "function", "delete", library).Err
+}
+
+// FunctionDump returns a serialized payload of all loaded libraries.
+func (c *Client) FunctionDump() ([]byte, error) {
+	return c.cmdSince(Version{7, 0, 0}, "function", "dump").Bytes()
+}
+
+// FunctionRestore restores the libraries of the given FunctionDump payload.
+// An empty policy means RestoreAppend.
+func (c *Client) FunctionRestore(payload []byte, policy FunctionRestorePolicy) error {
+	if policy == "" {
+		policy = RestoreAppend
+	}
+	return c.cmdSince(Version{7, 0, 0}, "function", "restore", payload, string(policy)).Err
+}
+
+// FCall calls the given function with the given keys and arguments.
+func (c *Client) FCall(function string, keys []string, args ...interface{}) *Reply {
+	start := time.Now()
+	r := c.cmdSince(Version{7, 0, 0}, "fcall", scriptArgs(function, keys, args)...)
+	c.scriptDone(function, start, r)
+	return r
+}
+
+// FCallRO calls the given read-only function with the given keys and arguments.
+func (c *Client) FCallRO(function string, keys []string, args ...interface{}) *Reply {
+	start := time.Now()
+	r := c.cmdSince(Version{7, 0, 0}, "fcall_ro", scriptArgs(function, keys, args)...)
+	c.scriptDone(function, start, r)
+	return r
+}
+
+//* Parsing
+
+// parseFunctionList parses a FUNCTION LIST reply.
+func parseFunctionList(r *Reply) ([]FunctionLibrary, error) {
+	if r.Type == ErrorReply {
+		return nil, r.Err
+	}
+	if r.Type != MultiReply {
+		return nil, errors.New("reply type is not MultiReply")
+	}
+
+	libs := make([]FunctionLibrary, 0, len(r.Elems))
+	for _, e := range r.Elems {
+		m, err := e.pairs()
+		if err != nil {
+			return nil, err
+		}
+		var lib FunctionLibrary
+		lib.Name = pairStr(m, "library_name")
+		lib.Engine = pairStr(m, "engine")
+		lib.Code = pairStr(m, "library_code")
+		if fns, ok := m["functions"]; ok {
+			for _, fe := range fns.Elems {
+				fm, err := fe.pairs()
+				if err != nil {
+					return nil, err
+				}
+				fn := FunctionInfo{
+					Name:        pairStr(fm, "name"),
+					Description: pairStr(fm, "description"),
+				}
+				if flags, ok := fm["flags"]; ok {
+					for _, f := range flags.Elems {
+						s, _ := f.Str()
+						fn.Flags = append(fn.Flags, s)
+					}
+				}
+				lib.Functions = append(lib.Functions, fn)
+			}
+		}
+		libs = append(libs, lib)
+	}
+	return libs, nil
+}
+
+// pairStr returns the string value of the given key of a pairs map,
+// or an empty string if it is missing or not a string.
+func pairStr(m map[string]*Reply, key string) string {
+	if v, ok := m[key]; ok {
+		s, _ := v.Str()
+		return s
+	}
+	return ""
+}
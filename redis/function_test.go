@@ -0,0 +1,50 @@
+package redis
+
+import (
+	. "launchpad.net/gocheck"
+)
+
+type FunctionSuite struct{}
+
+var _ = Suite(&FunctionSuite{})
+
+func (s *FunctionSuite) TestParseFunctionList(c *C) {
+	r := multi(multi(
+		bulk("library_name"), bulk("mylib"),
+		bulk("engine"), bulk("LUA"),
+		bulk("functions"), multi(multi(
+			bulk("name"), bulk("myfunc"),
+			bulk("description"), &Reply{Type: NilReply},
+			bulk("flags"), multi(&Reply{Type: StatusReply, buf: []byte("no-writes")}),
+		)),
+		bulk("library_code"), bulk("#!lua name=mylib"),
+	))
+	libs, err := parseFunctionList(r)
+	c.Assert(err, IsNil)
+	c.Check(libs, DeepEquals, []FunctionLibrary{{
+		Name:      "mylib",
+		Engine:    "LUA",
+		Functions: []FunctionInfo{{Name: "myfunc", Flags: []string{"no-writes"}}},
+		Code:      "#!lua name=mylib",
+	}})
+}
+
+func (s *FunctionSuite) TestCommands(c *C) {
+	cl, f := fakeClient("$5\r\nmylib\r\n*0\r\n+OK\r\n:3\r\n")
+	name, err := cl.FunctionLoad("#!lua name=mylib", true)
+	c.Assert(err, IsNil)
+	c.Check(name, Equals, "mylib")
+	libs, err := cl.FunctionList(FunctionListOptions{LibraryName: "my*", WithCode: true})
+	c.Assert(err, IsNil)
+	c.Check(libs, HasLen, 0)
+	c.Check(cl.FunctionRestore([]byte("x"), ""), IsNil)
+	n, err := cl.FCall("myfunc", []string{"k"}, 1).Int()
+	c.Assert(err, IsNil)
+	c.Check(n, Equals, 3)
+	c.Check(f.out.String(), Equals, "*4\r\n$8\r\nfunction\r\n$4\r\nload\r\n$7\r\nreplace\r\n"+
+		"$16\r\n#!lua name=mylib\r\n"+
+		"*5\r\n$8\r\nfunction\r\n$4\r\nlist\r\n$11\r\nlibraryname\r\n$3\r\nmy*\r\n"+
+		"$8\r\nwithcode\r\n"+
+		"*4\r\n$8\r\nfunction\r\n$7\r\nrestore\r\n$1\r\nx\r\n$6\r\nAPPEND\r\n"+
+		"*5\r\n$5\r\nfcall\r\n$6\r\nmyfunc\r\n$1\r\n1\r\n$1\r\nk\r\n$1\r\n1\r\n")
+}
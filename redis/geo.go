@@ -0,0 +1,181 @@
+package redis
+
+import (
+	"strconv"
+)
+
+//* Geospatial indexes
+
+// Coordinate describes a position on Earth in degrees.
+type Coordinate struct {
+	Lat, Lon float64
+}
+
+// GeoUnit is a unit of distance.
+type GeoUnit string
+
+const (
+	Meters     GeoUnit = "m"
+	Kilometers GeoUnit = "km"
+	Miles      GeoUnit = "mi"
+	Feet       GeoUnit = "ft"
+)
+
+func (u GeoUnit) arg() string {
+	if u == "" {
+		return string(Meters)
+	}
+	return string(u)
+}
+
+// GeoMember describes a member of a geospatial index with its position.
+type GeoMember struct {
+	Member string
+	Coordinate
+}
+
+// GeoLocation describes a member found by GeoSearch.
+type GeoLocation struct {
+	Member string
+	Dist   float64 // Distance from the search center in the unit of the search
+	Coordinate
+}
+
+// GeoAdd adds the given members to the geospatial index at the given key, updating
+// the positions of existing members, and returns the number of added members.
+func (c *Client) GeoAdd(key string, members ...GeoMember) (int64, error) {
+	args := []interface{}{key}
+	for _, m := range members {
+		args = append(args, formatFloat(m.Lon), formatFloat(m.Lat), m.Member)
+	}
+	return c.Cmd("geoadd", args...).Int64()
+}
+
+// GeoPos returns the positions of the given members, nil for missing members.
+func (c *Client) GeoPos(key string, members ...string) ([]*Coordinate, error) {
+	r := c.Cmd("geopos", key, members)
+	if r.Type == ErrorReply {
+		return nil, r.Err
+	}
+	if r.Type != MultiReply {
+		return nil, ParseError
+	}
+	pos := make([]*Coordinate, len(r.Elems))
+	for i, e := range r.Elems {
+		if e.Type == NilReply {
+			continue
+		}
+		coord, err := parseCoordinate(e)
+		if err != nil {
+			return nil, err
+		}
+		pos[i] = &coord
+	}
+	return pos, nil
+}
+
+// GeoDist returns the distance between the given members in the given unit.
+// It returns false if a member is missing.
+func (c *Client) GeoDist(key, member1, member2 string, unit GeoUnit) (float64, bool, error) {
+	s, ok, err := optStr(c.Cmd("geodist", key, member1, member2, unit.arg()))
+	if !ok {
+		return 0, false, err
+	}
+	d, err := strconv.ParseFloat(s, 64)
+	return d, err == nil, err
+}
+
+// GeoSearchOptions describes the options of GeoSearch.
+type GeoSearchOptions struct {
+	Member        string     // Search around this member if set
+	Center        Coordinate // Search around this position otherwise
+	Radius        float64    // Search within this distance of the center
+	Width, Height float64    // Search within this box around the center, if Radius is 0
+	Unit          GeoUnit    // Unit of distances, empty means Meters
+	Desc          bool       // Return the farthest members first instead of the nearest
+	Count         int64      // Return at most this many members, 0 for all
+	Any           bool       // Return the first Count members found, which is faster
+}
+
+func (opt GeoSearchOptions) args() []interface{} {
+	var args []interface{}
+	if opt.Member != "" {
+		args = append(args, "frommember", opt.Member)
+	} else {
+		args = append(args, "fromlonlat", formatFloat(opt.Center.Lon),
+			formatFloat(opt.Center.Lat))
+	}
+	if opt.Radius > 0 {
+		args = append(args, "byradius", formatFloat(opt.Radius))
+	} else {
+		args = append(args, "bybox", formatFloat(opt.Width), formatFloat(opt.Height))
+	}
+	args = append(args, opt.Unit.arg())
+	if opt.Desc {
+		args = append(args, "desc")
+	} else {
+		args = append(args, "asc")
+	}
+	if opt.Count > 0 {
+		args = append(args, "count", opt.Count)
+		if opt.Any {
+			args = append(args, "any")
+		}
+	}
+	return args
+}
+
+// GeoSearch returns the members of the geospatial index at the given key within the
+// given area, with their distances and positions (Redis 6.2 or later).
+func (c *Client) GeoSearch(key string, opt GeoSearchOptions) ([]GeoLocation, error) {
+	args := append([]interface{}{key}, opt.args()...)
+	r := c.cmdSince(Version{6, 2, 0}, "geosearch", append(args, "withdist", "withcoord")...)
+	if r.Type == ErrorReply {
+		return nil, r.Err
+	}
+	if r.Type != MultiReply {
+		return nil, ParseError
+	}
+	locs := make([]GeoLocation, len(r.Elems))
+	for i, e := range r.Elems {
+		if e.Type != MultiReply || len(e.Elems) != 3 {
+			return nil, ParseError
+		}
+		var err error
+		if locs[i].Member, err = e.Elems[0].Str(); err != nil {
+			return nil, err
+		}
+		if locs[i].Dist, err = parseFloat(e.Elems[1]); err != nil {
+			return nil, err
+		}
+		if locs[i].Coordinate, err = parseCoordinate(e.Elems[2]); err != nil {
+			return nil, err
+		}
+	}
+	return locs, nil
+}
+
+// parseCoordinate parses a longitude, latitude pair.
+func parseCoordinate(r *Reply) (Coordinate, error) {
+	if r.Type != MultiReply || len(r.Elems) != 2 {
+		return Coordinate{}, ParseError
+	}
+	lon, err := parseFloat(r.Elems[0])
+	if err != nil {
+		return Coordinate{}, err
+	}
+	lat, err := parseFloat(r.Elems[1])
+	return Coordinate{Lat: lat, Lon: lon}, err
+}
+
+func parseFloat(r *Reply) (float64, error) {
+	s, err := r.Str()
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseFloat(s, 64)
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
@@ -0,0 +1,49 @@
+package redis
+
+import (
+	. "launchpad.net/gocheck"
+)
+
+type GeoSuite struct{}
+
+var _ = Suite(&GeoSuite{})
+
+func (s *GeoSuite) TestGeoAdd(c *C) {
+	cl, f := fakeClient(":1\r\n")
+	n, err := cl.GeoAdd("g", GeoMember{"rome", Coordinate{Lat: 41.9, Lon: 12.5}})
+	c.Assert(err, IsNil)
+	c.Check(n, Equals, int64(1))
+	c.Check(f.out.String(), Equals, "*5\r\n$6\r\ngeoadd\r\n$1\r\ng\r\n$4\r\n12.5\r\n"+
+		"$4\r\n41.9\r\n$4\r\nrome\r\n")
+}
+
+func (s *GeoSuite) TestGeoPosDist(c *C) {
+	cl, _ := fakeClient("*2\r\n*2\r\n$4\r\n12.5\r\n$4\r\n41.9\r\n*-1\r\n" +
+		"$8\r\n166.2742\r\n$-1\r\n")
+	pos, err := cl.GeoPos("g", "rome", "nowhere")
+	c.Assert(err, IsNil)
+	c.Check(pos, DeepEquals, []*Coordinate{{Lat: 41.9, Lon: 12.5}, nil})
+
+	d, ok, err := cl.GeoDist("g", "rome", "palermo", Kilometers)
+	c.Assert(err, IsNil)
+	c.Check(ok, Equals, true)
+	c.Check(d, Equals, 166.2742)
+	_, ok, err = cl.GeoDist("g", "rome", "nowhere", "")
+	c.Assert(err, IsNil)
+	c.Check(ok, Equals, false)
+}
+
+func (s *GeoSuite) TestGeoSearch(c *C) {
+	c.Check(GeoSearchOptions{Member: "rome", Radius: 200, Unit: Kilometers, Count: 5,
+		Any: true}.args(), DeepEquals, []interface{}{"frommember", "rome", "byradius", "200",
+		"km", "asc", "count", int64(5), "any"})
+	c.Check(GeoSearchOptions{Center: Coordinate{Lat: 1, Lon: 2}, Width: 3, Height: 4,
+		Desc: true}.args(), DeepEquals, []interface{}{"fromlonlat", "2", "1", "bybox", "3", "4",
+		"m", "desc"})
+
+	cl, _ := fakeClient("*1\r\n*3\r\n$4\r\nrome\r\n$6\r\n0.0001\r\n" +
+		"*2\r\n$4\r\n12.5\r\n$4\r\n41.9\r\n")
+	locs, err := cl.GeoSearch("g", GeoSearchOptions{Member: "rome", Radius: 1})
+	c.Assert(err, IsNil)
+	c.Check(locs, DeepEquals, []GeoLocation{{"rome", 0.0001, Coordinate{Lat: 41.9, Lon: 12.5}}})
+}
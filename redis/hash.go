@@ -0,0 +1,56 @@
+package redis
+
+import (
+	"errors"
+)
+
+//* Hashes
+
+// HSetStruct sets the fields of the hash at the given key to the fields of the given
+// struct, see PairsArg, and returns the number of fields added.
+// Slice, map and struct fields are encoded as JSON, see PairsArg; use "omitempty" to
+// leave out zero values.
+func (c *Client) HSetStruct(key string, v interface{}) (int64, error) {
+	pairs, err := PairsArg(v)
+	if err != nil {
+		return 0, err
+	}
+	if len(pairs) == 0 {
+		return 0, errors.New("struct has no fields to set")
+	}
+	return c.Cmd("hset", key, pairs).Int64()
+}
+
+// HGetAllStruct stores the fields of the hash at the given key in the struct pointed
+// to by v, see Reply.Decode. Struct fields missing in the hash are left unchanged.
+func (c *Client) HGetAllStruct(key string, v interface{}) error {
+	return c.Cmd("hgetall", key).Decode(v)
+}
+
+// HMGetStruct is like HGetAllStruct, but only gets the hash fields with the given
+// names with HMGET, or the fields of the struct if no names are given.
+func (c *Client) HMGetStruct(key string, v interface{}, fields ...string) error {
+	if len(fields) == 0 {
+		rv, err := structValue(v)
+		if err != nil {
+			return err
+		}
+		for _, f := range structFields(rv.Type()) {
+			fields = append(fields, f.name)
+		}
+	}
+	r := c.Cmd("hmget", key, fields)
+	if r.Type == ErrorReply {
+		return r.Err
+	}
+	if r.Type != MultiReply || len(r.Elems) != len(fields) {
+		return ParseError
+	}
+	pairs := &Reply{Type: MultiReply}
+	for i, e := range r.Elems {
+		if e.Type != NilReply {
+			pairs.Elems = append(pairs.Elems, &Reply{Type: BulkReply, buf: []byte(fields[i])}, e)
+		}
+	}
+	return pairs.Decode(v)
+}
@@ -0,0 +1,80 @@
+package redis
+
+import (
+	. "launchpad.net/gocheck"
+	"strings"
+	"time"
+)
+
+type HashSuite struct{}
+
+var _ = Suite(&HashSuite{})
+
+type hashUser struct {
+	Name  string   `redis:"name"`
+	Age   int      `redis:"age,omitempty"`
+	Tags  []string `redis:"tags,json"`
+	Notes string   `redis:"-"`
+}
+
+func (s *HashSuite) TestHSetStruct(c *C) {
+	cl, f := fakeClient(":2\r\n")
+	n, err := cl.HSetStruct("u", hashUser{Name: "ann", Tags: []string{"a"}})
+	c.Assert(err, IsNil)
+	c.Check(n, Equals, int64(2))
+	c.Check(f.out.String(), Equals, "*6\r\n$4\r\nhset\r\n$1\r\nu\r\n$4\r\nname\r\n$3\r\nann\r\n"+
+		"$4\r\ntags\r\n$5\r\n[\"a\"]\r\n")
+}
+
+func (s *HashSuite) TestHGetAllStruct(c *C) {
+	cl, _ := fakeClient("*6\r\n$4\r\nname\r\n$3\r\nann\r\n$3\r\nage\r\n$2\r\n30\r\n" +
+		"$4\r\ntags\r\n$9\r\n[\"a\",\"b\"]\r\n")
+	var u hashUser
+	c.Assert(cl.HGetAllStruct("u", &u), IsNil)
+	c.Check(u, DeepEquals, hashUser{Name: "ann", Age: 30, Tags: []string{"a", "b"}})
+}
+
+func (s *HashSuite) TestHMGetStruct(c *C) {
+	cl, f := fakeClient("*3\r\n$3\r\nann\r\n$-1\r\n$-1\r\n*1\r\n$2\r\n31\r\n")
+	u := hashUser{Age: 1}
+	c.Assert(cl.HMGetStruct("u", &u), IsNil)
+	c.Check(u, DeepEquals, hashUser{Name: "ann", Age: 1})
+	c.Check(strings.HasPrefix(f.out.String(), "*5\r\n$5\r\nhmget\r\n$1\r\nu\r\n$4\r\nname\r\n"+
+		"$3\r\nage\r\n$4\r\ntags\r\n"), Equals, true)
+
+	c.Assert(cl.HMGetStruct("u", &u, "age"), IsNil)
+	c.Check(u.Age, Equals, 31)
+}
+
+type hashAddr struct{ Street, City string }
+
+type hashProfile struct {
+	Name string
+	Tags []string
+	Home hashAddr
+	Seen time.Time
+}
+
+func (s *HashSuite) TestStructRoundTrip(c *C) {
+	p := hashProfile{Name: "ann", Tags: []string{"a", "b"}, Home: hashAddr{"s", "c"},
+		Seen: time.Unix(5, 0)}
+	cl, f := fakeClient(":4\r\n")
+	_, err := cl.HSetStruct("k", p)
+	c.Assert(err, IsNil)
+	c.Check(f.out.String(), Equals, "*10\r\n$4\r\nhset\r\n$1\r\nk\r\n"+
+		"$4\r\nName\r\n$3\r\nann\r\n$4\r\nTags\r\n$9\r\n[\"a\",\"b\"]\r\n"+
+		"$4\r\nHome\r\n$25\r\n{\"Street\":\"s\",\"City\":\"c\"}\r\n$4\r\nSeen\r\n$1\r\n5\r\n")
+
+	// read back the hash that was set
+	args, err := encodeArg(nil, []interface{}{p})
+	c.Assert(err, IsNil)
+	elems := make([]*Reply, len(args))
+	for i, a := range args {
+		elems[i] = bulk(string(a))
+	}
+	var got hashProfile
+	c.Assert(multi(elems...).Decode(&got), IsNil)
+	c.Check(got.Seen.Equal(p.Seen), Equals, true)
+	got.Seen = p.Seen
+	c.Check(got, DeepEquals, p)
+}
@@ -0,0 +1,149 @@
+package redis
+
+import (
+	"math/bits"
+	"strings"
+	"sync"
+	"time"
+)
+
+//* Client latency tracking
+
+const (
+	subBucketBits = 4
+	subBuckets    = 1 << subBucketBits
+	// latencies are recorded in microseconds, up to 2^32 µs (over an hour)
+	maxExponent    = 32 - subBucketBits
+	latencyBuckets = subBuckets + maxExponent*subBuckets
+)
+
+// CommandLatency is the latency of a command measured by the client, from sending the
+// command to reading its reply, see Client.TrackLatency. Percentiles are accurate to
+// about 6%.
+type CommandLatency struct {
+	Count         int64
+	Mean          time.Duration
+	P50, P95, P99 time.Duration
+	Max           time.Duration
+}
+
+// latencyHistogram is a log-linear histogram of latencies, like HDR histograms: the
+// latencies below 16µs have a bucket each, and every further power of two of
+// microseconds is split in 16 buckets.
+type latencyHistogram struct {
+	counts [latencyBuckets]int64
+	count  int64
+	sum    time.Duration
+	max    time.Duration
+}
+
+// bucketOf returns the bucket of the given latency in microseconds.
+func bucketOf(us uint64) int {
+	if us < subBuckets {
+		return int(us)
+	}
+	e := bits.Len64(us) - subBucketBits - 1
+	if e >= maxExponent {
+		return latencyBuckets - 1
+	}
+	return subBuckets + e*subBuckets + int(us>>uint(e)) - subBuckets
+}
+
+// bucketMax returns the highest latency in microseconds of the given bucket.
+func bucketMax(i int) uint64 {
+	if i < subBuckets {
+		return uint64(i)
+	}
+	e := uint((i - subBuckets) / subBuckets)
+	m := uint64((i-subBuckets)%subBuckets + subBuckets)
+	return (m+1)<<e - 1
+}
+
+func (h *latencyHistogram) record(d time.Duration) {
+	if d < 0 {
+		d = 0
+	}
+	h.counts[bucketOf(uint64(d/time.Microsecond))]++
+	h.count++
+	h.sum += d
+	if d > h.max {
+		h.max = d
+	}
+}
+
+// percentile returns the latency below which the given fraction of the latencies fall.
+func (h *latencyHistogram) percentile(q float64) time.Duration {
+	target := int64(q*float64(h.count) + 0.5)
+	if target < 1 {
+		target = 1
+	}
+	var n int64
+	for i, count := range h.counts {
+		n += count
+		if n >= target {
+			d := time.Duration(bucketMax(i)) * time.Microsecond
+			if d > h.max {
+				d = h.max
+			}
+			return d
+		}
+	}
+	return h.max
+}
+
+func (h *latencyHistogram) report() CommandLatency {
+	l := CommandLatency{Count: h.count, Max: h.max}
+	if h.count > 0 {
+		l.Mean = h.sum / time.Duration(h.count)
+		l.P50, l.P95, l.P99 = h.percentile(.5), h.percentile(.95), h.percentile(.99)
+	}
+	return l
+}
+
+// latencyTracker holds the latency histograms of a client by command name. It is safe
+// for concurrent use, so that reports can be taken while the client is in use.
+type latencyTracker struct {
+	mu    sync.Mutex
+	hists map[string]*latencyHistogram
+}
+
+// record records the latency of the given requests, a pipeline if there are several.
+func (t *latencyTracker) record(requests []*request, d time.Duration) {
+	name := "pipeline"
+	if len(requests) == 1 {
+		name = strings.ToLower(requests[0].cmd)
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.hists == nil {
+		t.hists = map[string]*latencyHistogram{}
+	}
+	h, ok := t.hists[name]
+	if !ok {
+		h = &latencyHistogram{}
+		t.hists[name] = h
+	}
+	h.record(d)
+}
+
+// LatencyReport returns the latency of the commands sent by the client, by lower case
+// command name, since TrackLatency was set or ResetLatency was called. Pipelines are
+// reported as a whole, as "pipeline". Unlike the other methods, LatencyReport can be
+// called while the client is in use.
+func (c *Client) LatencyReport() map[string]CommandLatency {
+	c.latency.mu.Lock()
+	defer c.latency.mu.Unlock()
+	report := make(map[string]CommandLatency, len(c.latency.hists))
+	for name, h := range c.latency.hists {
+		report[name] = h.report()
+	}
+	return report
+}
+
+// ResetLatency forgets the latency recorded by the client. Like LatencyReport, it can be
+// called while the client is in use.
+func (c *Client) ResetLatency() {
+	c.latency.mu.Lock()
+	defer c.latency.mu.Unlock()
+	c.latency.hists = nil
+}
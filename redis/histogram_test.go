@@ -0,0 +1,95 @@
+package redis
+
+import (
+	. "launchpad.net/gocheck"
+	"strings"
+	"sync"
+	"time"
+)
+
+type HistogramSuite struct{}
+
+var _ = Suite(&HistogramSuite{})
+
+func (s *HistogramSuite) TestBuckets(c *C) {
+	prev := -1
+	for us := uint64(0); us < 1<<20; us += 1 + us/64 {
+		i := bucketOf(us)
+		c.Assert(i >= prev, Equals, true)
+		c.Assert(i < latencyBuckets, Equals, true)
+		c.Assert(bucketMax(i) >= us, Equals, true)
+		// buckets are at most 1/16 of their values wide
+		c.Assert(bucketMax(i)-us <= us/subBuckets, Equals, true)
+		prev = i
+	}
+	c.Check(bucketOf(15), Equals, 15)
+	c.Check(bucketOf(16), Equals, 16)
+	c.Check(bucketOf(32), Equals, 32)
+	c.Check(bucketMax(32), Equals, uint64(33))
+	c.Check(bucketOf(1<<40), Equals, latencyBuckets-1)
+}
+
+func (s *HistogramSuite) TestPercentiles(c *C) {
+	h := &latencyHistogram{}
+	for i := 1; i <= 1000; i++ {
+		h.record(time.Duration(i) * time.Millisecond)
+	}
+	l := h.report()
+	c.Check(l.Count, Equals, int64(1000))
+	c.Check(l.Mean, Equals, 500500*time.Microsecond)
+	c.Check(l.Max, Equals, time.Second)
+	for _, p := range []struct {
+		got, want time.Duration
+	}{{l.P50, 500 * time.Millisecond}, {l.P95, 950 * time.Millisecond},
+		{l.P99, 990 * time.Millisecond}} {
+		c.Check(p.got >= p.want, Equals, true)
+		c.Check(p.got <= p.want+p.want/subBuckets, Equals, true)
+	}
+
+	h = &latencyHistogram{}
+	h.record(3 * time.Microsecond)
+	c.Check(h.report(), Equals, CommandLatency{Count: 1, Mean: 3 * time.Microsecond,
+		P50: 3 * time.Microsecond, P95: 3 * time.Microsecond, P99: 3 * time.Microsecond,
+		Max: 3 * time.Microsecond})
+	c.Check((&latencyHistogram{}).report(), Equals, CommandLatency{})
+}
+
+func (s *HistogramSuite) TestLatencyReport(c *C) {
+	cl, _ := fakeClient(strings.Repeat("+OK\r\n", 5))
+	cl.Cmd("ping")
+	c.Check(cl.LatencyReport(), HasLen, 0)
+
+	cl.TrackLatency = true
+	cl.Cmd("PING")
+	cl.Cmd("set", "k", "v")
+	cl.Append("set", "k", "v")
+	cl.Append("set", "k", "v")
+	cl.GetReply()
+	cl.GetReply()
+	report := cl.LatencyReport()
+	c.Check(report, HasLen, 3)
+	c.Check(report["ping"].Count, Equals, int64(1))
+	c.Check(report["set"].Count, Equals, int64(1))
+	c.Check(report["pipeline"].Count, Equals, int64(1))
+
+	cl.ResetLatency()
+	c.Check(cl.LatencyReport(), HasLen, 0)
+}
+
+func (s *HistogramSuite) TestConcurrentReport(c *C) {
+	cl, _ := fakeClient(strings.Repeat("+PONG\r\n", 100))
+	cl.TrackLatency = true
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 100; i++ {
+			cl.LatencyReport()
+		}
+	}()
+	for i := 0; i < 100; i++ {
+		cl.Cmd("ping")
+	}
+	wg.Wait()
+	c.Check(cl.LatencyReport()["ping"].Count, Equals, int64(100))
+}
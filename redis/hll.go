@@ -0,0 +1,56 @@
+package redis
+
+//* HyperLogLog
+
+// PFAdd adds the given elements to the HyperLogLog at the given key and returns true if
+// its estimated cardinality changed.
+func (c *Client) PFAdd(key string, elements ...interface{}) (bool, error) {
+	return c.Cmd("pfadd", key, elements).Bool()
+}
+
+// PFCount returns the estimated number of unique elements added to the HyperLogLogs at
+// the given keys.
+func (c *Client) PFCount(keys ...string) (int64, error) {
+	return c.Cmd("pfcount", keys).Int64()
+}
+
+// PFMerge merges the HyperLogLogs at the given source keys into the one at dst.
+func (c *Client) PFMerge(dst string, srcs ...string) error {
+	return c.Cmd("pfmerge", dst, srcs).Err
+}
+
+// HyperLogLog is a HyperLogLog stored at a key, for approximately counting unique
+// elements with about 12 kB of memory and a standard error of 0.81%.
+type HyperLogLog struct {
+	c   *Client
+	key string
+}
+
+// NewHyperLogLog returns the HyperLogLog at the given key.
+func NewHyperLogLog(c *Client, key string) *HyperLogLog {
+	return &HyperLogLog{c: c, key: key}
+}
+
+// Key returns the key of the HyperLogLog.
+func (h *HyperLogLog) Key() string {
+	return h.key
+}
+
+// Add adds the given elements and returns true if the estimated count changed.
+func (h *HyperLogLog) Add(elements ...interface{}) (bool, error) {
+	return h.c.PFAdd(h.key, elements...)
+}
+
+// Count returns the estimated number of unique elements added.
+func (h *HyperLogLog) Count() (int64, error) {
+	return h.c.PFCount(h.key)
+}
+
+// Merge merges the given HyperLogLogs into this one.
+func (h *HyperLogLog) Merge(others ...*HyperLogLog) error {
+	keys := make([]string, len(others))
+	for i, o := range others {
+		keys[i] = o.key
+	}
+	return h.c.PFMerge(h.key, keys...)
+}
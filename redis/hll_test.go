@@ -0,0 +1,25 @@
+package redis
+
+import (
+	. "launchpad.net/gocheck"
+)
+
+type HLLSuite struct{}
+
+var _ = Suite(&HLLSuite{})
+
+func (s *HLLSuite) TestHyperLogLog(c *C) {
+	cl, f := fakeClient(":1\r\n+OK\r\n:3\r\n")
+	h := NewHyperLogLog(cl, "visitors")
+	changed, err := h.Add("a", "b", "c")
+	c.Assert(err, IsNil)
+	c.Check(changed, Equals, true)
+	c.Assert(h.Merge(NewHyperLogLog(cl, "v1"), NewHyperLogLog(cl, "v2")), IsNil)
+	n, err := h.Count()
+	c.Assert(err, IsNil)
+	c.Check(n, Equals, int64(3))
+	c.Check(f.out.String(), Equals, "*5\r\n$5\r\npfadd\r\n$8\r\nvisitors\r\n"+
+		"$1\r\na\r\n$1\r\nb\r\n$1\r\nc\r\n"+
+		"*4\r\n$7\r\npfmerge\r\n$8\r\nvisitors\r\n$2\r\nv1\r\n$2\r\nv2\r\n"+
+		"*2\r\n$7\r\npfcount\r\n$8\r\nvisitors\r\n")
+}
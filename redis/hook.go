@@ -0,0 +1,106 @@
+package redis
+
+import (
+	"context"
+	"net"
+	"time"
+)
+
+//* Hooks
+
+// Call is a command sent by a client, as passed to hooks.
+type Call struct {
+	Cmd  string
+	Args []interface{}
+}
+
+// Strings returns the arguments of the call as they are sent to Redis, slices, maps
+// and structs being expanded. Arguments that cannot be encoded are left out.
+func (c Call) Strings() []string {
+	var args [][]byte
+	for _, arg := range c.Args {
+		args, _ = encodeArg(args, arg)
+	}
+	l := make([]string, len(args))
+	for i, arg := range args {
+		l[i] = string(arg)
+	}
+	return l
+}
+
+// Hook instruments the commands of a client, such as for tracing, see Client.Hook.
+type Hook interface {
+	// Before is called before the given command, or pipeline of commands, is sent
+	// with the context of the call, see CmdContext. The returned context is passed
+	// to After.
+	Before(ctx context.Context, c *Client, calls []Call) context.Context
+	// After is called with the replies of the calls once they are read, or with
+	// error replies if sending them failed.
+	After(ctx context.Context, c *Client, calls []Call, replies []*Reply)
+}
+
+// Hooks combines hooks. Before is called in order, and After in reverse order.
+type Hooks []Hook
+
+func (hs Hooks) Before(ctx context.Context, c *Client, calls []Call) context.Context {
+	for _, h := range hs {
+		ctx = h.Before(ctx, c, calls)
+	}
+	return ctx
+}
+
+func (hs Hooks) After(ctx context.Context, c *Client, calls []Call, replies []*Reply) {
+	for i := len(hs) - 1; i >= 0; i-- {
+		hs[i].After(ctx, c, calls, replies)
+	}
+}
+
+// CmdContext is like Cmd, passing the given context to the hook of the client. The
+// context does not cancel the command, see the blocking command helpers for that.
+func (c *Client) CmdContext(ctx context.Context, cmd string, args ...interface{}) *Reply {
+	req := &request{cmd: cmd, args: args, ctx: ctx}
+	var r *Reply
+	c.instrument([]*request{req}, func() []*Reply {
+		r = c.cmd(req)
+		return []*Reply{r}
+	})
+	return r
+}
+
+// AppendContext is like Append, passing the given context to the hook of the client.
+// A pipeline is reported with the context of its first request.
+func (c *Client) AppendContext(ctx context.Context, cmd string, args ...interface{}) {
+	c.appendRequest(&request{cmd: cmd, args: args, ctx: ctx})
+}
+
+// RemoteAddr returns the address of the server the client is connected to.
+func (c *Client) RemoteAddr() net.Addr {
+	return c.conn.RemoteAddr()
+}
+
+// instrument calls send, which sends the given requests and returns their replies,
+// between the Before and After calls of the hook of the client, if any, and records its
+// latency if TrackLatency is set.
+func (c *Client) instrument(requests []*request, send func() []*Reply) {
+	var ctx context.Context
+	var calls []Call
+	if c.Hook != nil {
+		ctx = requests[0].ctx
+		if ctx == nil {
+			ctx = context.Background()
+		}
+		calls = make([]Call, len(requests))
+		for i, req := range requests {
+			calls[i] = Call{Cmd: req.cmd, Args: req.args}
+		}
+		ctx = c.Hook.Before(ctx, c, calls)
+	}
+	start := time.Now()
+	replies := send()
+	if c.TrackLatency {
+		c.latency.record(requests, time.Since(start))
+	}
+	if c.Hook != nil {
+		c.Hook.After(ctx, c, calls, replies)
+	}
+}
@@ -0,0 +1,103 @@
+package redis
+
+import (
+	"context"
+	. "launchpad.net/gocheck"
+)
+
+type HookSuite struct{}
+
+var _ = Suite(&HookSuite{})
+
+type ctxKey struct{}
+
+// recordHook records the calls and replies it is given.
+type recordHook struct {
+	ctxs    []interface{}
+	calls   [][]Call
+	replies [][]*Reply
+}
+
+func (h *recordHook) Before(ctx context.Context, c *Client, calls []Call) context.Context {
+	return context.WithValue(ctx, ctxKey{}, len(h.calls))
+}
+
+func (h *recordHook) After(ctx context.Context, c *Client, calls []Call, replies []*Reply) {
+	h.ctxs = append(h.ctxs, ctx.Value(ctxKey{}))
+	h.calls = append(h.calls, calls)
+	h.replies = append(h.replies, replies)
+}
+
+func (s *HookSuite) TestCmd(c *C) {
+	cl, _ := fakeClient("+OK\r\n")
+	h := &recordHook{}
+	cl.Hook = h
+	c.Check(cl.Cmd("set", "k", []int{1, 2}).Err, IsNil)
+
+	c.Assert(h.calls, HasLen, 1)
+	c.Check(h.calls[0], DeepEquals, []Call{{"set", []interface{}{"k", []int{1, 2}}}})
+	c.Check(h.calls[0][0].Strings(), DeepEquals, []string{"k", "1", "2"})
+	c.Check(h.replies[0][0].Type, Equals, StatusReply)
+	c.Check(h.ctxs[0], Equals, 0)
+}
+
+func (s *HookSuite) TestPipeline(c *C) {
+	cl, _ := fakeClient(":1\r\n-ERR wrong\r\n")
+	h := &recordHook{}
+	cl.Hook = h
+	ctx := context.WithValue(context.Background(), ctxKey{}, "caller")
+	cl.AppendContext(ctx, "incr", "a")
+	cl.Append("incr", "b")
+	c.Check(cl.GetReply().Type, Equals, IntegerReply)
+	c.Check(cl.GetReply().Type, Equals, ErrorReply)
+
+	c.Assert(h.calls, HasLen, 1)
+	c.Check(h.calls[0], HasLen, 2)
+	c.Check(h.replies[0], HasLen, 2)
+	c.Check(h.replies[0][1].Err, ErrorMatches, "ERR wrong")
+}
+
+func (s *HookSuite) TestContext(c *C) {
+	cl, _ := fakeClient("+OK\r\n")
+	var got interface{}
+	cl.Hook = &ctxHook{func(ctx context.Context) { got = ctx.Value(ctxKey{}) }}
+	ctx := context.WithValue(context.Background(), ctxKey{}, "caller")
+	cl.CmdContext(ctx, "ping")
+	c.Check(got, Equals, "caller")
+}
+
+type ctxHook struct {
+	before func(ctx context.Context)
+}
+
+func (h *ctxHook) Before(ctx context.Context, c *Client, calls []Call) context.Context {
+	h.before(ctx)
+	return ctx
+}
+
+func (h *ctxHook) After(ctx context.Context, c *Client, calls []Call, replies []*Reply) {}
+
+func (s *HookSuite) TestHooks(c *C) {
+	cl, _ := fakeClient("+OK\r\n")
+	var order []string
+	hook := func(name string) Hook {
+		return &orderHook{name, &order}
+	}
+	cl.Hook = Hooks{hook("a"), hook("b")}
+	cl.Cmd("ping")
+	c.Check(order, DeepEquals, []string{"before a", "before b", "after b", "after a"})
+}
+
+type orderHook struct {
+	name  string
+	order *[]string
+}
+
+func (h *orderHook) Before(ctx context.Context, c *Client, calls []Call) context.Context {
+	*h.order = append(*h.order, "before "+h.name)
+	return ctx
+}
+
+func (h *orderHook) After(ctx context.Context, c *Client, calls []Call, replies []*Reply) {
+	*h.order = append(*h.order, "after "+h.name)
+}
@@ -0,0 +1,191 @@
+package redis
+
+import (
+	"net"
+	"strconv"
+	"strings"
+	"time"
+)
+
+//* Info
+
+// Info is the parsed output of INFO. The typed fields are zero when the section
+// they belong to was not requested or the server does not report them.
+type Info struct {
+	// Server
+	Version string        // redis_version
+	Mode    string        // redis_mode: standalone, sentinel or cluster
+	Uptime  time.Duration // uptime_in_seconds
+
+	// Clients
+	ConnectedClients int64
+	BlockedClients   int64
+
+	// Memory
+	UsedMemory         int64   // Bytes allocated by the server
+	UsedMemoryRSS      int64   // Bytes as seen by the operating system
+	UsedMemoryPeak     int64   // Peak of UsedMemory
+	MaxMemory          int64   // maxmemory, 0 if unlimited
+	FragmentationRatio float64 // mem_fragmentation_ratio
+
+	// Stats
+	TotalCommands  int64 // total_commands_processed
+	OpsPerSec      int64 // instantaneous_ops_per_sec
+	KeyspaceHits   int64
+	KeyspaceMisses int64
+	ExpiredKeys    int64
+	EvictedKeys    int64
+
+	// Replication
+	Role              string        // master or slave
+	ReplOffset        int64         // master_repl_offset
+	MasterLinkStatus  string        // up or down, on replicas only
+	MasterLastIO      time.Duration // master_last_io_seconds_ago, on replicas only
+	ConnectedReplicas int64         // connected_slaves
+	Replicas          []ReplicaInfo // On masters only
+
+	// Keyspace
+	Keyspace map[int]KeyspaceInfo // By database number
+
+	Fields map[string]string // All fields, including the ones above
+}
+
+// ReplicaInfo describes a replica connected to a master, as reported by INFO.
+type ReplicaInfo struct {
+	Addr      string
+	State     string        // online, wait_bgsave, send_bulk...
+	Offset    int64         // Replication offset acknowledged by the replica
+	OffsetLag int64         // Bytes of the replication stream the replica is behind
+	Lag       time.Duration // Time since the last acknowledgement
+}
+
+// KeyspaceInfo holds the key counts of a database, as reported by INFO.
+type KeyspaceInfo struct {
+	Keys    int64
+	Expires int64         // Keys with a TTL
+	AvgTTL  time.Duration // Estimated average TTL of the keys with one
+}
+
+// Info returns the parsed output of INFO for the given sections, or the default
+// sections if none are given.
+func (c *Client) Info(sections ...string) (*Info, error) {
+	s, err := c.Cmd("info", sections).Str()
+	if err != nil {
+		return nil, err
+	}
+	return parseInfo(s)
+}
+
+// parseInfo parses the output of INFO.
+func parseInfo(s string) (*Info, error) {
+	f := parseInfoFields(s)
+	info := &Info{
+		Version:          f["redis_version"],
+		Mode:             f["redis_mode"],
+		Role:             f["role"],
+		MasterLinkStatus: f["master_link_status"],
+		Keyspace:         map[int]KeyspaceInfo{},
+		Fields:           f,
+	}
+	var uptime, lastIO int64
+	ints := map[string]*int64{
+		"uptime_in_seconds":          &uptime,
+		"connected_clients":          &info.ConnectedClients,
+		"blocked_clients":            &info.BlockedClients,
+		"used_memory":                &info.UsedMemory,
+		"used_memory_rss":            &info.UsedMemoryRSS,
+		"used_memory_peak":           &info.UsedMemoryPeak,
+		"maxmemory":                  &info.MaxMemory,
+		"total_commands_processed":   &info.TotalCommands,
+		"instantaneous_ops_per_sec":  &info.OpsPerSec,
+		"keyspace_hits":              &info.KeyspaceHits,
+		"keyspace_misses":            &info.KeyspaceMisses,
+		"expired_keys":               &info.ExpiredKeys,
+		"evicted_keys":               &info.EvictedKeys,
+		"master_repl_offset":         &info.ReplOffset,
+		"master_last_io_seconds_ago": &lastIO,
+		"connected_slaves":           &info.ConnectedReplicas,
+	}
+	for k, p := range ints {
+		if v, ok := f[k]; ok {
+			i, err := strconv.ParseInt(v, 10, 64)
+			if err != nil {
+				return nil, ParseError
+			}
+			*p = i
+		}
+	}
+	info.Uptime = time.Duration(uptime) * time.Second
+	info.MasterLastIO = time.Duration(lastIO) * time.Second
+	if v, ok := f["mem_fragmentation_ratio"]; ok {
+		info.FragmentationRatio, _ = strconv.ParseFloat(v, 64)
+	}
+
+	for i := int64(0); i < info.ConnectedReplicas; i++ {
+		v, ok := f["slave"+strconv.FormatInt(i, 10)]
+		if !ok {
+			break
+		}
+		r, err := parseReplicaInfo(v)
+		if err != nil {
+			return nil, err
+		}
+		r.OffsetLag = info.ReplOffset - r.Offset
+		info.Replicas = append(info.Replicas, r)
+	}
+
+	for k, v := range f {
+		if !strings.HasPrefix(k, "db") {
+			continue
+		}
+		db, err := strconv.Atoi(k[2:])
+		if err != nil {
+			continue
+		}
+		vals := parseInfoValues(v)
+		var ks KeyspaceInfo
+		var ttl int64
+		for name, p := range map[string]*int64{
+			"keys": &ks.Keys, "expires": &ks.Expires, "avg_ttl": &ttl,
+		} {
+			if *p, err = strconv.ParseInt(vals[name], 10, 64); err != nil {
+				return nil, ParseError
+			}
+		}
+		ks.AvgTTL = time.Duration(ttl) * time.Millisecond
+		info.Keyspace[db] = ks
+	}
+	return info, nil
+}
+
+// parseReplicaInfo parses a "slaveN" field of INFO, of the form
+// "ip=127.0.0.1,port=6380,state=online,offset=1234,lag=0".
+func parseReplicaInfo(s string) (ReplicaInfo, error) {
+	vals := parseInfoValues(s)
+	r := ReplicaInfo{Addr: net.JoinHostPort(vals["ip"], vals["port"]), State: vals["state"]}
+	offset, err := strconv.ParseInt(vals["offset"], 10, 64)
+	if err != nil {
+		return r, ParseError
+	}
+	r.Offset = offset
+	if v, ok := vals["lag"]; ok {
+		lag, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return r, ParseError
+		}
+		r.Lag = time.Duration(lag) * time.Second
+	}
+	return r, nil
+}
+
+// parseInfoValues parses a comma separated list of "name=value" pairs, as found in
+// the values of some INFO fields.
+func parseInfoValues(s string) map[string]string {
+	vals := map[string]string{}
+	for _, kv := range strings.Split(s, ",") {
+		if i := strings.IndexByte(kv, '='); i >= 0 {
+			vals[kv[:i]] = kv[i+1:]
+		}
+	}
+	return vals
+}
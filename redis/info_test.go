@@ -0,0 +1,72 @@
+package redis
+
+import (
+	"fmt"
+	. "launchpad.net/gocheck"
+	"time"
+)
+
+type InfoSuite struct{}
+
+var _ = Suite(&InfoSuite{})
+
+const infoText = "# Server\r\nredis_version:7.2.4\r\nredis_mode:standalone\r\n" +
+	"uptime_in_seconds:3600\r\n\r\n# Clients\r\nconnected_clients:12\r\nblocked_clients:1\r\n" +
+	"\r\n# Memory\r\nused_memory:1048576\r\nused_memory_rss:2097152\r\n" +
+	"used_memory_peak:3145728\r\nmaxmemory:0\r\nmem_fragmentation_ratio:2.00\r\n" +
+	"\r\n# Stats\r\ntotal_commands_processed:500\r\ninstantaneous_ops_per_sec:7\r\n" +
+	"keyspace_hits:40\r\nkeyspace_misses:10\r\nexpired_keys:3\r\nevicted_keys:0\r\n" +
+	"\r\n# Replication\r\nrole:master\r\nconnected_slaves:2\r\n" +
+	"slave0:ip=10.0.0.2,port=6380,state=online,offset=900,lag=1\r\n" +
+	"slave1:ip=::1,port=6381,state=wait_bgsave,offset=0\r\n" +
+	"master_repl_offset:1000\r\n" +
+	"\r\n# Keyspace\r\ndb0:keys=10,expires=2,avg_ttl=5000\r\n" +
+	"db3:keys=1,expires=0,avg_ttl=0,subexpiry=0\r\n"
+
+func (s *InfoSuite) TestInfo(c *C) {
+	cl, f := fakeClient(fmt.Sprintf("$%d\r\n%s\r\n", len(infoText), infoText))
+	info, err := cl.Info("server", "replication")
+	c.Assert(err, IsNil)
+	c.Check(f.out.String(), Equals,
+		"*3\r\n$4\r\ninfo\r\n$6\r\nserver\r\n$11\r\nreplication\r\n")
+	c.Check(info.Version, Equals, "7.2.4")
+	c.Check(info.Mode, Equals, "standalone")
+	c.Check(info.Uptime, Equals, time.Hour)
+	c.Check(info.ConnectedClients, Equals, int64(12))
+	c.Check(info.BlockedClients, Equals, int64(1))
+	c.Check(info.UsedMemory, Equals, int64(1<<20))
+	c.Check(info.UsedMemoryRSS, Equals, int64(2<<20))
+	c.Check(info.UsedMemoryPeak, Equals, int64(3<<20))
+	c.Check(info.FragmentationRatio, Equals, 2.0)
+	c.Check(info.TotalCommands, Equals, int64(500))
+	c.Check(info.OpsPerSec, Equals, int64(7))
+	c.Check(info.KeyspaceHits, Equals, int64(40))
+	c.Check(info.ExpiredKeys, Equals, int64(3))
+	c.Check(info.Role, Equals, "master")
+	c.Check(info.ReplOffset, Equals, int64(1000))
+	c.Check(info.Replicas, DeepEquals, []ReplicaInfo{
+		{Addr: "10.0.0.2:6380", State: "online", Offset: 900, OffsetLag: 100, Lag: time.Second},
+		{Addr: "[::1]:6381", State: "wait_bgsave", OffsetLag: 1000},
+	})
+	c.Check(info.Keyspace, DeepEquals, map[int]KeyspaceInfo{
+		0: {Keys: 10, Expires: 2, AvgTTL: 5 * time.Second},
+		3: {Keys: 1},
+	})
+	c.Check(info.Fields["redis_version"], Equals, "7.2.4")
+
+	info, err = parseInfo("role:slave\r\nmaster_link_status:down\r\n" +
+		"master_last_io_seconds_ago:12\r\n")
+	c.Assert(err, IsNil)
+	c.Check(info.MasterLinkStatus, Equals, "down")
+	c.Check(info.MasterLastIO, Equals, 12*time.Second)
+	c.Check(info.Keyspace, HasLen, 0)
+
+	_, err = parseInfo("connected_clients:many\r\n")
+	c.Check(err, Equals, ParseError)
+	_, err = parseInfo("db0:keys=x,expires=0,avg_ttl=0\r\n")
+	c.Check(err, Equals, ParseError)
+
+	cl, _ = fakeClient("-ERR unknown section\r\n")
+	_, err = cl.Info("nope")
+	c.Check(err, ErrorMatches, "ERR unknown section")
+}
@@ -0,0 +1,175 @@
+package redis
+
+import (
+	"fmt"
+	"time"
+)
+
+//* Keys
+
+// ObjectEncoding returns the internal encoding of the value of the given key, such as
+// "listpack" or "hashtable". It returns false if the key does not exist.
+func (c *Client) ObjectEncoding(key string) (string, bool, error) {
+	return optStr(c.Cmd("object", "encoding", key))
+}
+
+// ObjectFreq returns the logarithmic access frequency counter of the given key, which
+// is only available with an LFU maxmemory policy. It returns false if the key does not
+// exist.
+func (c *Client) ObjectFreq(key string) (int64, bool, error) {
+	return optInt64(c.Cmd("object", "freq", key))
+}
+
+// ObjectIdletime returns how long the given key has not been accessed, in seconds
+// precision, which is not available with an LFU maxmemory policy. It returns false if
+// the key does not exist.
+func (c *Client) ObjectIdletime(key string) (time.Duration, bool, error) {
+	n, ok, err := optInt64(c.Cmd("object", "idletime", key))
+	return time.Duration(n) * time.Second, ok, err
+}
+
+// ObjectRefcount returns the number of references to the value of the given key.
+// It returns false if the key does not exist.
+func (c *Client) ObjectRefcount(key string) (int64, bool, error) {
+	return optInt64(c.Cmd("object", "refcount", key))
+}
+
+// Copy copies the value of the key src to the key dst and returns true if it did
+// (Redis 6.2 or later). Without replace, existing dst keys are not overwritten.
+func (c *Client) Copy(src, dst string, replace bool) (bool, error) {
+	return c.cmdSince(Version{6, 2, 0}, "copy", src, dst, replaceArg(replace)).Bool()
+}
+
+// CopyToDB is like Copy, but copies to the key dst of the given database.
+func (c *Client) CopyToDB(src, dst string, db int, replace bool) (bool, error) {
+	return c.cmdSince(Version{6, 2, 0}, "copy", src, dst, "db", db, replaceArg(replace)).Bool()
+}
+
+func replaceArg(replace bool) []interface{} {
+	if replace {
+		return []interface{}{"replace"}
+	}
+	return nil
+}
+
+// Type returns the type of the value of the given key, or "none" if it does not exist.
+func (c *Client) Type(key string) (string, error) {
+	return c.Cmd("type", key).Str()
+}
+
+// DumpKey returns the value of the given key, or nil if it does not exist, depending
+// on its type as a string, a []string for lists and sets, a map[string]string for
+// hashes, a []ScoredMember for sorted sets and a []StreamEntry for streams.
+// The value is read with a second command, so it may have changed type in between.
+// DumpKey is meant for debugging and export tools, as it reads whole values at once.
+func (c *Client) DumpKey(key string) (interface{}, error) {
+	t, err := c.Type(key)
+	if err != nil {
+		return nil, err
+	}
+	switch t {
+	case "none":
+		return nil, nil
+	case "string":
+		return optValue(c.Cmd("get", key).Str())
+	case "list":
+		return optValue(c.Cmd("lrange", key, 0, -1).List())
+	case "set":
+		return optValue(c.Cmd("smembers", key).List())
+	case "hash":
+		return optValue(c.Cmd("hgetall", key).Hash())
+	case "zset":
+		return optValue(c.zrangeWithScores(key, IndexRange{0, -1}, ZRangeOptions{}))
+	case "stream":
+		return optValue(c.XRange(key, "-", "+", 0))
+	}
+	return nil, fmt.Errorf("cannot dump values of type %s", t)
+}
+
+// Dump returns the serialized value of the given key, which Restore restores.
+// It returns false if the key does not exist.
+func (c *Client) Dump(key string) ([]byte, bool, error) {
+	r := c.Cmd("dump", key)
+	if r.Type == NilReply {
+		return nil, false, nil
+	}
+	b, err := r.Bytes()
+	return b, err == nil, err
+}
+
+// DumpWithTTL is like Dump, but also returns the time to live of the key, NoExpiry if it
+// has none, reading both with one pipeline. Pass the time to live as the TTL option of
+// Restore to preserve it.
+func (c *Client) DumpWithTTL(key string) (payload []byte, ttl time.Duration, ok bool,
+	err error) {
+	replies := c.flush([]*request{
+		{cmd: "dump", args: []interface{}{key}},
+		{cmd: "pttl", args: []interface{}{key}},
+	})
+	r := replies[0]
+	ttl, terr := parseTTL(replies[1])
+	if r.Type == NilReply {
+		return nil, 0, false, nil
+	}
+	if payload, err = r.Bytes(); err != nil {
+		return nil, 0, false, err
+	}
+	if terr != nil {
+		return nil, 0, false, terr
+	}
+	return payload, ttl, true, nil
+}
+
+// RestoreOptions describes the options of Restore.
+type RestoreOptions struct {
+	TTL      time.Duration // Expire after this long, 0 or NoExpiry for no time to live
+	ExpireAt time.Time     // Expire at this time (ABSTTL), used if TTL is 0
+	Replace  bool          // Replace an existing key instead of failing
+	IdleTime time.Duration // Set the idle time of the key, for LRU eviction
+	Freq     int64         // Set the access frequency of the key, for LFU eviction, if set
+}
+
+func (opt RestoreOptions) args(key string, payload []byte) []interface{} {
+	var ttl int64
+	if opt.TTL > 0 {
+		ttl = int64(opt.TTL / time.Millisecond)
+	} else if !opt.ExpireAt.IsZero() {
+		ttl = opt.ExpireAt.UnixNano() / int64(time.Millisecond)
+	}
+	args := []interface{}{key, ttl, payload}
+	if opt.Replace {
+		args = append(args, "replace")
+	}
+	if opt.TTL <= 0 && !opt.ExpireAt.IsZero() {
+		args = append(args, "absttl")
+	}
+	if opt.IdleTime > 0 {
+		args = append(args, "idletime", int64(opt.IdleTime/time.Second))
+	}
+	if opt.Freq > 0 {
+		args = append(args, "freq", opt.Freq)
+	}
+	return args
+}
+
+// Restore creates the given key from the given payload of Dump.
+func (c *Client) Restore(key string, payload []byte, opt RestoreOptions) error {
+	return c.Cmd("restore", opt.args(key, payload)...).Err
+}
+
+// optValue returns v, or nil if err is not nil.
+func optValue(v interface{}, err error) (interface{}, error) {
+	if err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// optInt64 returns the integer value of the given reply and false if it is a nil reply.
+func optInt64(r *Reply) (int64, bool, error) {
+	if r.Type == NilReply {
+		return 0, false, nil
+	}
+	n, err := r.Int64()
+	return n, err == nil, err
+}
@@ -0,0 +1,112 @@
+package redis
+
+import (
+	. "launchpad.net/gocheck"
+	"strings"
+	"time"
+)
+
+type KeysSuite struct{}
+
+var _ = Suite(&KeysSuite{})
+
+func (s *KeysSuite) TestObject(c *C) {
+	cl, f := fakeClient("$8\r\nlistpack\r\n$-1\r\n:3\r\n:120\r\n:1\r\n")
+	enc, ok, err := cl.ObjectEncoding("h")
+	c.Assert(err, IsNil)
+	c.Check(ok, Equals, true)
+	c.Check(enc, Equals, "listpack")
+	c.Check(f.out.String(), Equals, "*3\r\n$6\r\nobject\r\n$8\r\nencoding\r\n$1\r\nh\r\n")
+
+	_, ok, err = cl.ObjectEncoding("missing")
+	c.Assert(err, IsNil)
+	c.Check(ok, Equals, false)
+
+	freq, _, err := cl.ObjectFreq("h")
+	c.Assert(err, IsNil)
+	c.Check(freq, Equals, int64(3))
+	idle, _, err := cl.ObjectIdletime("h")
+	c.Assert(err, IsNil)
+	c.Check(idle, Equals, 2*time.Minute)
+	refs, ok, err := cl.ObjectRefcount("h")
+	c.Assert(err, IsNil)
+	c.Check(ok, Equals, true)
+	c.Check(refs, Equals, int64(1))
+}
+
+func (s *KeysSuite) TestCopy(c *C) {
+	cl, f := fakeClient(":1\r\n:0\r\n")
+	ok, err := cl.Copy("a", "b", false)
+	c.Assert(err, IsNil)
+	c.Check(ok, Equals, true)
+	ok, err = cl.CopyToDB("a", "b", 0, true)
+	c.Assert(err, IsNil)
+	c.Check(ok, Equals, false)
+	c.Check(f.out.String(), Equals, "*3\r\n$4\r\ncopy\r\n$1\r\na\r\n$1\r\nb\r\n"+
+		"*6\r\n$4\r\ncopy\r\n$1\r\na\r\n$1\r\nb\r\n$2\r\ndb\r\n$1\r\n0\r\n$7\r\nreplace\r\n")
+}
+
+func (s *KeysSuite) TestDumpKey(c *C) {
+	cl, f := fakeClient("+none\r\n+hash\r\n*2\r\n$1\r\nf\r\n$1\r\nv\r\n" +
+		"+zset\r\n*2\r\n$1\r\na\r\n$1\r\n1\r\n+list\r\n*1\r\n$1\r\nx\r\n" +
+		"+stream\r\n*1\r\n*2\r\n$3\r\n1-0\r\n*2\r\n$1\r\na\r\n$1\r\nb\r\n+unknown\r\n")
+	v, err := cl.DumpKey("k")
+	c.Assert(err, IsNil)
+	c.Check(v, IsNil)
+	v, err = cl.DumpKey("k")
+	c.Assert(err, IsNil)
+	c.Check(v, DeepEquals, map[string]string{"f": "v"})
+	v, err = cl.DumpKey("k")
+	c.Assert(err, IsNil)
+	c.Check(v, DeepEquals, []ScoredMember{{"a", 1}})
+	v, err = cl.DumpKey("k")
+	c.Assert(err, IsNil)
+	c.Check(v, DeepEquals, []string{"x"})
+	v, err = cl.DumpKey("k")
+	c.Assert(err, IsNil)
+	c.Check(v, DeepEquals, []StreamEntry{{ID: "1-0", Fields: map[string]string{"a": "b"}}})
+	_, err = cl.DumpKey("k")
+	c.Check(err, ErrorMatches, "cannot dump values of type unknown")
+	c.Check(strings.Contains(f.out.String(), "$6\r\nzrange\r\n$1\r\nk\r\n$1\r\n0\r\n$2\r\n-1\r\n"),
+		Equals, true)
+}
+
+func (s *KeysSuite) TestDumpRestore(c *C) {
+	cl, f := fakeClient("$3\r\na\x00b\r\n:1500\r\n+OK\r\n$-1\r\n:-2\r\n")
+	payload, ttl, ok, err := cl.DumpWithTTL("k")
+	c.Assert(err, IsNil)
+	c.Check(ok, Equals, true)
+	c.Check(payload, DeepEquals, []byte("a\x00b"))
+	c.Check(ttl, Equals, 1500*time.Millisecond)
+
+	f.out.Reset()
+	c.Assert(cl.Restore("k2", payload, RestoreOptions{TTL: ttl, Replace: true}), IsNil)
+	c.Check(f.out.String(), Equals, "*5\r\n$7\r\nrestore\r\n$2\r\nk2\r\n$4\r\n1500\r\n"+
+		"$3\r\na\x00b\r\n$7\r\nreplace\r\n")
+
+	_, _, ok, err = cl.DumpWithTTL("missing")
+	c.Assert(err, IsNil)
+	c.Check(ok, Equals, false)
+}
+
+func (s *KeysSuite) TestDumpKeepsPipeline(c *C) {
+	cl, f := fakeClient("$1\r\na\r\n:-1\r\n$3\r\nbar\r\n")
+	cl.Append("get", "foo")
+	_, ttl, ok, err := cl.DumpWithTTL("k")
+	c.Assert(err, IsNil)
+	c.Check(ok, Equals, true)
+	c.Check(ttl, Equals, NoExpiry)
+	c.Check(strings.HasPrefix(f.out.String(), "*2\r\n$4\r\ndump\r\n"), Equals, true)
+	v, err := cl.GetReply().Str()
+	c.Assert(err, IsNil)
+	c.Check(v, Equals, "bar")
+	c.Check(cl.GetReply().Err, Equals, PipelineQueueEmptyError)
+}
+
+func (s *KeysSuite) TestRestoreArgs(c *C) {
+	opt := RestoreOptions{ExpireAt: time.Unix(100, 0), IdleTime: time.Minute, Freq: 5}
+	c.Check(opt.args("k", nil), DeepEquals, []interface{}{"k", int64(100000), []byte(nil),
+		"absttl", "idletime", int64(60), "freq", int64(5)})
+	c.Check(RestoreOptions{TTL: NoExpiry}.args("k", nil), DeepEquals,
+		[]interface{}{"k", int64(0), []byte(nil)})
+}
@@ -0,0 +1,102 @@
+package redis
+
+import (
+	"errors"
+	"time"
+)
+
+//* Latency monitor
+
+// LatencyEvent is the latest latency spike of an event, as reported by LATENCY LATEST.
+type LatencyEvent struct {
+	Name   string        // Event name, such as "command" or "fast-command"
+	Time   time.Time     // When the latest spike happened
+	Latest time.Duration // Latency of the latest spike
+	Max    time.Duration // Highest latency recorded for the event
+}
+
+// LatencySample is a latency spike of an event, as reported by LATENCY HISTORY.
+type LatencySample struct {
+	Time    time.Time
+	Latency time.Duration
+}
+
+// LatencyLatest returns the latest latency spike of every event. The latency monitor
+// must be enabled with the latency-monitor-threshold configuration parameter.
+func (c *Client) LatencyLatest() ([]LatencyEvent, error) {
+	r := c.Cmd("latency", "latest")
+	if r.Type == ErrorReply {
+		return nil, r.Err
+	}
+	if r.Type != MultiReply {
+		return nil, errors.New("reply type is not MultiReply")
+	}
+	events := make([]LatencyEvent, len(r.Elems))
+	for i, er := range r.Elems {
+		if er.Type != MultiReply || len(er.Elems) < 4 {
+			return nil, errors.New("reply is not a latency event")
+		}
+		name, err := er.Elems[0].Str()
+		if err != nil {
+			return nil, err
+		}
+		ts, err := parseLatencyInts(er.Elems[1:4])
+		if err != nil {
+			return nil, err
+		}
+		events[i] = LatencyEvent{
+			Name:   name,
+			Time:   time.Unix(ts[0], 0),
+			Latest: time.Duration(ts[1]) * time.Millisecond,
+			Max:    time.Duration(ts[2]) * time.Millisecond,
+		}
+	}
+	return events, nil
+}
+
+// LatencyHistory returns the latency spikes of the given event, oldest first.
+// The server keeps the last 160 spikes of each event.
+func (c *Client) LatencyHistory(event string) ([]LatencySample, error) {
+	r := c.Cmd("latency", "history", event)
+	if r.Type == ErrorReply {
+		return nil, r.Err
+	}
+	if r.Type != MultiReply {
+		return nil, errors.New("reply type is not MultiReply")
+	}
+	samples := make([]LatencySample, len(r.Elems))
+	for i, er := range r.Elems {
+		if er.Type != MultiReply || len(er.Elems) != 2 {
+			return nil, errors.New("reply is not a latency sample")
+		}
+		ts, err := parseLatencyInts(er.Elems)
+		if err != nil {
+			return nil, err
+		}
+		samples[i] = LatencySample{time.Unix(ts[0], 0), time.Duration(ts[1]) * time.Millisecond}
+	}
+	return samples, nil
+}
+
+// LatencyReset deletes the recorded spikes of the given events, or of all events if
+// none are given, and returns the number of events reset.
+func (c *Client) LatencyReset(events ...string) (int64, error) {
+	return c.Cmd("latency", "reset", events).Int64()
+}
+
+// LatencyDoctor returns a human readable analysis of the recorded latency spikes.
+func (c *Client) LatencyDoctor() (string, error) {
+	return c.Cmd("latency", "doctor").Str()
+}
+
+// parseLatencyInts parses the integer elements of a latency reply.
+func parseLatencyInts(elems []*Reply) ([]int64, error) {
+	ints := make([]int64, len(elems))
+	for i, e := range elems {
+		var err error
+		if ints[i], err = e.Int64(); err != nil {
+			return nil, err
+		}
+	}
+	return ints, nil
+}
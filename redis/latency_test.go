@@ -0,0 +1,56 @@
+package redis
+
+import (
+	. "launchpad.net/gocheck"
+	"time"
+)
+
+type LatencySuite struct{}
+
+var _ = Suite(&LatencySuite{})
+
+func (s *LatencySuite) TestLatencyLatest(c *C) {
+	cl, f := fakeClient("*2\r\n*4\r\n$7\r\ncommand\r\n:1700000000\r\n:250\r\n:1000\r\n" +
+		"*4\r\n$4\r\nfork\r\n:1700000100\r\n:12\r\n:30\r\n" +
+		"*1\r\n*2\r\n$1\r\nx\r\n:1\r\n")
+	l, err := cl.LatencyLatest()
+	c.Assert(err, IsNil)
+	c.Check(f.out.String(), Equals, "*2\r\n$7\r\nlatency\r\n$6\r\nlatest\r\n")
+	c.Check(l, DeepEquals, []LatencyEvent{
+		{"command", time.Unix(1700000000, 0), 250 * time.Millisecond, time.Second},
+		{"fork", time.Unix(1700000100, 0), 12 * time.Millisecond, 30 * time.Millisecond},
+	})
+	_, err = cl.LatencyLatest()
+	c.Check(err, ErrorMatches, "reply is not a latency event")
+}
+
+func (s *LatencySuite) TestLatencyHistory(c *C) {
+	cl, f := fakeClient("*2\r\n*2\r\n:1700000000\r\n:15\r\n*2\r\n:1700000005\r\n:20\r\n" +
+		"-ERR unknown subcommand\r\n")
+	l, err := cl.LatencyHistory("command")
+	c.Assert(err, IsNil)
+	c.Check(f.out.String(), Equals,
+		"*3\r\n$7\r\nlatency\r\n$7\r\nhistory\r\n$7\r\ncommand\r\n")
+	c.Check(l, DeepEquals, []LatencySample{
+		{time.Unix(1700000000, 0), 15 * time.Millisecond},
+		{time.Unix(1700000005, 0), 20 * time.Millisecond},
+	})
+	_, err = cl.LatencyHistory("command")
+	c.Check(err, ErrorMatches, "ERR unknown subcommand")
+}
+
+func (s *LatencySuite) TestLatencyReset(c *C) {
+	cl, f := fakeClient(":2\r\n:5\r\n$9\r\nall good.\r\n")
+	n, err := cl.LatencyReset("command", "fork")
+	c.Assert(err, IsNil)
+	c.Check(n, Equals, int64(2))
+	n, err = cl.LatencyReset()
+	c.Assert(err, IsNil)
+	c.Check(n, Equals, int64(5))
+	d, err := cl.LatencyDoctor()
+	c.Assert(err, IsNil)
+	c.Check(d, Equals, "all good.")
+	c.Check(f.out.String(), Equals,
+		"*4\r\n$7\r\nlatency\r\n$5\r\nreset\r\n$7\r\ncommand\r\n$4\r\nfork\r\n"+
+			"*2\r\n$7\r\nlatency\r\n$5\r\nreset\r\n*2\r\n$7\r\nlatency\r\n$6\r\ndoctor\r\n")
+}
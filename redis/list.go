@@ -0,0 +1,58 @@
+package redis
+
+//* Lists
+
+// LPosOptions describes the options of LPos and LPosAll.
+type LPosOptions struct {
+	Rank   int64 // Skip Rank-1 matches, or search from the end if negative, 0 means 1
+	MaxLen int64 // Compare at most this many elements, 0 for all
+}
+
+func (opt LPosOptions) args() []interface{} {
+	var args []interface{}
+	if opt.Rank != 0 {
+		args = append(args, "rank", opt.Rank)
+	}
+	if opt.MaxLen > 0 {
+		args = append(args, "maxlen", opt.MaxLen)
+	}
+	return args
+}
+
+// LPos returns the index of the first element of the list at the given key that equals
+// the given element (Redis 6.0.6 or later). It returns false if there is none.
+func (c *Client) LPos(key string, element interface{}, opt LPosOptions) (int64, bool,
+	error) {
+	return optInt64(c.cmdSince(Version{6, 0, 6}, "lpos", key, element, opt.args()))
+}
+
+// LPosAll returns the indexes of up to count elements of the list at the given key that
+// equal the given element, or of all of them if count is 0.
+func (c *Client) LPosAll(key string, element interface{}, count int64, opt LPosOptions) (
+	[]int64, error) {
+	r := c.cmdSince(Version{6, 0, 6}, "lpos", key, element, opt.args(), "count", count)
+	if r.Type == ErrorReply {
+		return nil, r.Err
+	}
+	if r.Type != MultiReply {
+		return nil, ParseError
+	}
+	indexes := make([]int64, len(r.Elems))
+	for i, e := range r.Elems {
+		n, err := e.Int64()
+		if err != nil {
+			return nil, err
+		}
+		indexes[i] = n
+	}
+	return indexes, nil
+}
+
+// LMPop pops up to count elements from the given side of the first non-empty list of
+// the given keys (Redis 7.0 or later). It returns the key and the elements, or false if
+// all lists are empty.
+func (c *Client) LMPop(side ListSide, count int64, keys ...string) (key string,
+	values []string, ok bool, err error) {
+	return parseMPop(c.cmdSince(Version{7, 0, 0}, "lmpop", len(keys), keys, string(side),
+		"count", count))
+}
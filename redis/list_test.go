@@ -0,0 +1,54 @@
+package redis
+
+import (
+	. "launchpad.net/gocheck"
+)
+
+type ListSuite struct{}
+
+var _ = Suite(&ListSuite{})
+
+func (s *ListSuite) TestLPos(c *C) {
+	cl, f := fakeClient(":2\r\n$-1\r\n*2\r\n:0\r\n:4\r\n")
+	i, ok, err := cl.LPos("l", "a", LPosOptions{Rank: -1})
+	c.Assert(err, IsNil)
+	c.Check(ok, Equals, true)
+	c.Check(i, Equals, int64(2))
+	c.Check(f.out.String(), Equals, "*5\r\n$4\r\nlpos\r\n$1\r\nl\r\n$1\r\na\r\n"+
+		"$4\r\nrank\r\n$2\r\n-1\r\n")
+
+	_, ok, err = cl.LPos("l", "z", LPosOptions{})
+	c.Assert(err, IsNil)
+	c.Check(ok, Equals, false)
+
+	indexes, err := cl.LPosAll("l", "a", 0, LPosOptions{MaxLen: 10})
+	c.Assert(err, IsNil)
+	c.Check(indexes, DeepEquals, []int64{0, 4})
+}
+
+func (s *ListSuite) TestMPop(c *C) {
+	cl, f := fakeClient("*2\r\n$1\r\nl\r\n*1\r\n$1\r\na\r\n" +
+		"*2\r\n$1\r\nz\r\n*2\r\n*2\r\n$1\r\na\r\n$1\r\n1\r\n*2\r\n$1\r\nb\r\n$1\r\n2\r\n" +
+		"*-1\r\n:2\r\n")
+	key, values, ok, err := cl.LMPop(ListRight, 1, "k", "l")
+	c.Assert(err, IsNil)
+	c.Check(ok, Equals, true)
+	c.Check(key, Equals, "l")
+	c.Check(values, DeepEquals, []string{"a"})
+	c.Check(f.out.String(), Equals, "*7\r\n$5\r\nlmpop\r\n$1\r\n2\r\n$1\r\nk\r\n$1\r\nl\r\n"+
+		"$5\r\nright\r\n$5\r\ncount\r\n$1\r\n1\r\n")
+
+	key, members, ok, err := cl.ZMPop(ZMin, 2, "z")
+	c.Assert(err, IsNil)
+	c.Check(ok, Equals, true)
+	c.Check(key, Equals, "z")
+	c.Check(members, DeepEquals, []ScoredMember{{"a", 1}, {"b", 2}})
+
+	_, _, ok, err = cl.ZMPop(ZMax, 1, "empty")
+	c.Assert(err, IsNil)
+	c.Check(ok, Equals, false)
+
+	n, err := cl.SInterCard(0, "s1", "s2")
+	c.Assert(err, IsNil)
+	c.Check(n, Equals, int64(2))
+}
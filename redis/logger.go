@@ -0,0 +1,50 @@
+package redis
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+)
+
+//* Logging
+
+// Logger logs the events that the clients otherwise handle silently: reconnections,
+// dropped connections, failovers, retries, quarantined nodes and protocol errors. The
+// arguments are alternating keys and values, as with log/slog. *slog.Logger implements
+// Logger.
+type Logger interface {
+	Log(ctx context.Context, level slog.Level, msg string, args ...interface{})
+}
+
+var logger struct {
+	sync.RWMutex
+	l Logger
+}
+
+// SetLogger sets the logger of the clients of the package, or disables logging, the
+// default, if l is nil.
+//
+//	redis.SetLogger(slog.Default())
+func SetLogger(l Logger) {
+	logger.Lock()
+	defer logger.Unlock()
+	logger.l = l
+}
+
+// logEvent logs the given event, if a logger is set.
+func logEvent(level slog.Level, msg string, args ...interface{}) {
+	logger.RLock()
+	l := logger.l
+	logger.RUnlock()
+	if l != nil {
+		l.Log(context.Background(), level, msg, args...)
+	}
+}
+
+// addr returns the address of the server of the client, for logging.
+func (c *Client) addr() string {
+	if a := c.conn.RemoteAddr(); a != nil {
+		return a.String()
+	}
+	return ""
+}
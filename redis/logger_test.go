@@ -0,0 +1,108 @@
+package redis
+
+import (
+	"bytes"
+	"context"
+	. "launchpad.net/gocheck"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+)
+
+type LoggerSuite struct {
+	log *recordLogger
+}
+
+var _ = Suite(&LoggerSuite{})
+
+type logEntry struct {
+	level slog.Level
+	msg   string
+	args  []interface{}
+}
+
+// recordLogger records the events it is given.
+type recordLogger struct {
+	sync.Mutex
+	entries []logEntry
+}
+
+func (l *recordLogger) Log(ctx context.Context, level slog.Level, msg string,
+	args ...interface{}) {
+	l.Lock()
+	defer l.Unlock()
+	l.entries = append(l.entries, logEntry{level, msg, args})
+}
+
+// find returns the recorded events with the given message, as other suites may run
+// background goroutines that log too.
+func (l *recordLogger) find(msg string) []logEntry {
+	l.Lock()
+	defer l.Unlock()
+	var found []logEntry
+	for _, e := range l.entries {
+		if e.msg == msg {
+			found = append(found, e)
+		}
+	}
+	return found
+}
+
+func (s *LoggerSuite) SetUpTest(c *C) {
+	s.log = &recordLogger{}
+	SetLogger(s.log)
+}
+
+func (s *LoggerSuite) TearDownTest(c *C) {
+	SetLogger(nil)
+}
+
+func (s *LoggerSuite) TestProtocolError(c *C) {
+	cl, _ := fakeClient("!oops\r\n")
+	c.Check(cl.Cmd("ping").Err, Equals, ParseError)
+	l := s.log.find("redis: protocol error")
+	c.Assert(l, HasLen, 1)
+	c.Check(l[0].level, Equals, slog.LevelWarn)
+	c.Check(l[0].args, DeepEquals, []interface{}{"addr", "", "line", "!oops\r\n"})
+}
+
+func (s *LoggerSuite) TestReconnect(c *C) {
+	cl, _ := fakeClient("")
+	addr := fakeServer(c, "+OK\r\n")
+	c.Assert(cl.UpdateConfiguration("tcp", addr, ""), IsNil)
+	c.Check(cl.UpdateConfiguration("tcp", "127.0.0.1:1", ""), NotNil)
+	c.Check(s.log.find("redis: reconnected"), HasLen, 1)
+	l := s.log.find("redis: reconnection failed")
+	c.Assert(l, HasLen, 1)
+	c.Check(l[0].args[:2], DeepEquals, []interface{}{"addr", "127.0.0.1:1"})
+}
+
+func (s *LoggerSuite) TestQuarantine(c *C) {
+	q := quarantine{}
+	p := QuarantinePolicy{Failures: 2, Backoff: time.Second}
+	q.failed(p, "node")
+	c.Check(s.log.find("redis: node quarantined"), HasLen, 0)
+	q.failed(p, "node")
+	l := s.log.find("redis: node quarantined")
+	c.Assert(l, HasLen, 1)
+	c.Check(l[0].args, DeepEquals, []interface{}{"addr", "node", "failures", 2,
+		"backoff", time.Second})
+}
+
+func (s *LoggerSuite) TestFailover(c *C) {
+	fc := &FailoverClient{primary: "primary", standby: "standby", active: "primary",
+		policy: FailoverPolicy{Failures: 1}}
+	c.Check(fc.observe(false), Equals, "standby")
+	l := s.log.find("redis: failing over")
+	c.Assert(l, HasLen, 1)
+	c.Check(l[0].args, DeepEquals, []interface{}{"from", "primary", "to", "standby"})
+}
+
+func (s *LoggerSuite) TestSlog(c *C) {
+	var buf bytes.Buffer
+	SetLogger(slog.New(slog.NewTextHandler(&buf, nil)))
+	cl, _ := fakeClient("$x\r\n")
+	cl.Cmd("get", "k")
+	c.Check(strings.Contains(buf.String(), `msg="redis: protocol error"`), Equals, true)
+}
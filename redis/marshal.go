@@ -0,0 +1,398 @@
+package redis
+
+import (
+	"encoding"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+//* Struct marshaling
+
+// structField describes an exported struct field and its `redis:"name,opts"` tag.
+// Fields tagged `redis:"-"` are skipped. Options are "key", marking a script key,
+// "json", encoding the field as JSON, and "omitempty", leaving out zero values from
+// name value pairs. Slice, map and struct fields are always encoded as JSON, as they do
+// not fit in one argument.
+type structField struct {
+	name      string
+	index     int
+	key       bool
+	json      bool
+	omitempty bool
+}
+
+// structFields returns the fields of the given struct type.
+func structFields(t reflect.Type) []structField {
+	var fields []structField
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.PkgPath != "" {
+			continue
+		}
+		tag := f.Tag.Get("redis")
+		if tag == "-" {
+			continue
+		}
+		opts := strings.Split(tag, ",")
+		sf := structField{name: opts[0], index: i}
+		if sf.name == "" {
+			sf.name = f.Name
+		}
+		for _, o := range opts[1:] {
+			switch o {
+			case "key":
+				sf.key = true
+			case "json":
+				sf.json = true
+			case "omitempty":
+				sf.omitempty = true
+			}
+		}
+		fields = append(fields, sf)
+	}
+	return fields
+}
+
+// fieldArg returns the argument of the given field value.
+func fieldArg(f structField, v reflect.Value) (interface{}, error) {
+	if f.json || expands(v) {
+		return json.Marshal(v.Interface())
+	}
+	return v.Interface(), nil
+}
+
+// expands returns true if the given value is encoded as several arguments, see
+// encodeArg, i.e. if it is a slice, array, map or struct that is not a time.Time,
+// a byte slice or a marshaler.
+func expands(v reflect.Value) bool {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return false
+		}
+		if isMarshaler(v.Interface()) {
+			return false
+		}
+		v = v.Elem()
+	}
+	if _, ok := v.Interface().(time.Time); ok || isMarshaler(v.Interface()) {
+		return false
+	}
+	switch v.Kind() {
+	case reflect.Slice, reflect.Array:
+		return v.Type().Elem().Kind() != reflect.Uint8
+	case reflect.Map, reflect.Struct:
+		return true
+	}
+	return false
+}
+
+func isMarshaler(v interface{}) bool {
+	switch v.(type) {
+	case encoding.TextMarshaler, encoding.BinaryMarshaler, fmt.Stringer:
+		return true
+	}
+	return false
+}
+
+// structValue returns the struct that v is or points to.
+func structValue(v interface{}) (reflect.Value, error) {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Ptr && !rv.IsNil() {
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return rv, fmt.Errorf("%T is not a struct", v)
+	}
+	return rv, nil
+}
+
+// ScriptArgs returns the keys and arguments of a script call from the fields of
+// the given struct. Fields tagged `redis:",key"` must be strings or string slices and
+// become KEYS; the other fields become ARGV in field order.
+// Declaring keys this way ensures that ClusterClient routes the call by them,
+// as key names passed in ARGV are invisible to the cluster.
+func ScriptArgs(v interface{}) (keys []string, args []interface{}, err error) {
+	rv, err := structValue(v)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, f := range structFields(rv.Type()) {
+		fv := rv.Field(f.index)
+		if !f.key {
+			a, err := fieldArg(f, fv)
+			if err != nil {
+				return nil, nil, err
+			}
+			args = append(args, a)
+			continue
+		}
+		switch k := fv.Interface().(type) {
+		case string:
+			keys = append(keys, k)
+		case []string:
+			keys = append(keys, k...)
+		default:
+			return nil, nil, fmt.Errorf("key field %s is not a string", f.name)
+		}
+	}
+	for _, k := range keys {
+		if k == "" {
+			return nil, nil, errors.New("empty script key")
+		}
+	}
+	return keys, args, nil
+}
+
+// RunStruct calls the script with the keys and arguments of the given struct,
+// see ScriptArgs.
+func (s *Script) RunStruct(c Cmder, v interface{}) *Reply {
+	keys, args, err := ScriptArgs(v)
+	if err != nil {
+		return &Reply{Type: ErrorReply, Err: err}
+	}
+	return s.Run(c, keys, args...)
+}
+
+// PairsArg returns the given map or struct as a flat "name value name value..."
+// argument list, e.g. for passing as ARGV or to HSET. Struct field names are
+// taken from their redis tag, see ScriptArgs, fields tagged "omitempty" are left out
+// if they have their zero value, and slice, map and struct fields are encoded as JSON.
+func PairsArg(v interface{}) ([]interface{}, error) {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Map {
+		var pairs []interface{}
+		for _, k := range rv.MapKeys() {
+			pairs = append(pairs, k.Interface(), rv.MapIndex(k).Interface())
+		}
+		return pairs, nil
+	}
+	rv, err := structValue(v)
+	if err != nil {
+		return nil, err
+	}
+	var pairs []interface{}
+	for _, f := range structFields(rv.Type()) {
+		if f.omitempty && rv.Field(f.index).IsZero() {
+			continue
+		}
+		a, err := fieldArg(f, rv.Field(f.index))
+		if err != nil {
+			return nil, err
+		}
+		pairs = append(pairs, f.name, a)
+	}
+	return pairs, nil
+}
+
+// JSONArg returns the given value encoded as JSON, e.g. for passing as ARGV
+// to a script that uses cjson.decode.
+func JSONArg(v interface{}) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+//* Decoding
+
+// Decode stores the reply in the value pointed to by v.
+// Supported targets are strings, byte slices, integers, floats and booleans,
+// slices of these from multi bulk replies, and maps and structs from multi bulk
+// replies of "name value name value..." pairs. Struct fields are matched by their
+// redis tag, see ScriptArgs, and fields tagged "json" are decoded from JSON, as are
+// slices, maps and structs from bulk replies. time.Time targets are decoded in the
+// TimeArgFormat. Nil replies leave the target unchanged.
+func (r *Reply) Decode(v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return errors.New("decode target is not a non-nil pointer")
+	}
+	return r.decode(rv.Elem())
+}
+
+// JSON decodes the JSON string reply into the value pointed to by v.
+func (r *Reply) JSON(v interface{}) error {
+	b, err := r.Bytes()
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, v)
+}
+
+func (r *Reply) decode(v reflect.Value) error {
+	switch r.Type {
+	case ErrorReply:
+		return r.Err
+	case NilReply:
+		return nil
+	}
+
+	if v.Type() == timeType {
+		return r.decodeTime(v)
+	}
+	if r.Type == BulkReply && expands(v) {
+		p := reflect.New(v.Type())
+		if err := r.JSON(p.Interface()); err != nil {
+			return err
+		}
+		v.Set(p.Elem())
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		return r.decode(v.Elem())
+	case reflect.Slice:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			b, err := r.Bytes()
+			if err != nil {
+				return err
+			}
+			v.SetBytes(append([]byte(nil), b...))
+			return nil
+		}
+		if r.Type != MultiReply {
+			return errors.New("reply type is not MultiReply")
+		}
+		s := reflect.MakeSlice(v.Type(), len(r.Elems), len(r.Elems))
+		for i, e := range r.Elems {
+			if err := e.decode(s.Index(i)); err != nil {
+				return err
+			}
+		}
+		v.Set(s)
+		return nil
+	case reflect.Map:
+		m, err := r.pairs()
+		if err != nil {
+			return err
+		}
+		if v.Type().Key().Kind() != reflect.String {
+			return fmt.Errorf("cannot decode into %s", v.Type())
+		}
+		if v.IsNil() {
+			v.Set(reflect.MakeMap(v.Type()))
+		}
+		for k, e := range m {
+			ev := reflect.New(v.Type().Elem()).Elem()
+			if err := e.decode(ev); err != nil {
+				return err
+			}
+			v.SetMapIndex(reflect.ValueOf(k).Convert(v.Type().Key()), ev)
+		}
+		return nil
+	case reflect.Struct:
+		m, err := r.pairs()
+		if err != nil {
+			return err
+		}
+		for _, f := range structFields(v.Type()) {
+			e, ok := m[f.name]
+			if !ok {
+				continue
+			}
+			if f.json {
+				err = e.JSON(v.Field(f.index).Addr().Interface())
+			} else {
+				err = e.decode(v.Field(f.index))
+			}
+			if err != nil {
+				return fmt.Errorf("field %s: %s", f.name, err)
+			}
+		}
+		return nil
+	}
+
+	// scalars
+	if r.Type == IntegerReply {
+		switch v.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			v.SetInt(r.int)
+			return nil
+		case reflect.Bool:
+			v.SetBool(r.int != 0)
+			return nil
+		}
+	}
+	var s string
+	switch r.Type {
+	case IntegerReply:
+		s = strconv.FormatInt(r.int, 10)
+	case StatusReply, BulkReply:
+		s = string(r.buf)
+	default:
+		return fmt.Errorf("cannot decode multi bulk reply into %s", v.Type())
+	}
+	return setString(v, s)
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// decodeTime stores the reply in the time.Time v, see TimeArgFormat.
+func (r *Reply) decodeTime(v reflect.Value) error {
+	if r.Type == IntegerReply {
+		r = &Reply{Type: BulkReply, buf: []byte(strconv.FormatInt(r.int, 10))}
+	}
+	s, err := r.Str()
+	if err != nil {
+		return err
+	}
+	var t time.Time
+	switch TimeArgFormat {
+	case TimeRFC3339:
+		t, err = time.Parse(time.RFC3339Nano, s)
+	case TimeUnixMilli:
+		var ms int64
+		ms, err = strconv.ParseInt(s, 10, 64)
+		t = time.Unix(0, ms*int64(time.Millisecond))
+	default:
+		var sec int64
+		sec, err = strconv.ParseInt(s, 10, 64)
+		t = time.Unix(sec, 0)
+	}
+	if err != nil {
+		return err
+	}
+	v.Set(reflect.ValueOf(t))
+	return nil
+}
+
+// setString stores the given string in v, converting it to the kind of v.
+func setString(v reflect.Value, s string) error {
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(s)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(s, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(s, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(s, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetFloat(f)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	default:
+		return fmt.Errorf("cannot decode string into %s", v.Type())
+	}
+	return nil
+}
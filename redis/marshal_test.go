@@ -0,0 +1,71 @@
+package redis
+
+import (
+	. "launchpad.net/gocheck"
+)
+
+type MarshalSuite struct{}
+
+var _ = Suite(&MarshalSuite{})
+
+type transfer struct {
+	From   string            `redis:"from,key"`
+	To     string            `redis:"to,key"`
+	Amount int               `redis:"amount"`
+	Meta   map[string]string `redis:"meta,json"`
+	Note   string            `redis:"-"`
+	hidden int
+}
+
+func (s *MarshalSuite) TestScriptArgs(c *C) {
+	keys, args, err := ScriptArgs(&transfer{From: "a", To: "b", Amount: 5,
+		Meta: map[string]string{"x": "y"}})
+	c.Assert(err, IsNil)
+	c.Check(keys, DeepEquals, []string{"a", "b"})
+	c.Check(args, DeepEquals, []interface{}{5, []byte(`{"x":"y"}`)})
+
+	_, _, err = ScriptArgs(transfer{To: "b"})
+	c.Check(err, ErrorMatches, "empty script key")
+	_, _, err = ScriptArgs(struct {
+		K int `redis:",key"`
+	}{})
+	c.Check(err, ErrorMatches, "key field K is not a string")
+	_, _, err = ScriptArgs(1)
+	c.Check(err, ErrorMatches, "int is not a struct")
+}
+
+func (s *MarshalSuite) TestPairsArg(c *C) {
+	pairs, err := PairsArg(struct {
+		A string
+		B int `redis:"b"`
+	}{"x", 2})
+	c.Assert(err, IsNil)
+	c.Check(pairs, DeepEquals, []interface{}{"A", "x", "b", 2})
+	pairs, err = PairsArg(map[string]int{"a": 1})
+	c.Assert(err, IsNil)
+	c.Check(pairs, DeepEquals, []interface{}{"a", 1})
+}
+
+func (s *MarshalSuite) TestDecode(c *C) {
+	var t transfer
+	r := multi(bulk("from"), bulk("a"), bulk("amount"), integer(7),
+		bulk("meta"), bulk(`{"x":"y"}`), bulk("other"), bulk("z"))
+	c.Assert(r.Decode(&t), IsNil)
+	c.Check(t, DeepEquals, transfer{From: "a", Amount: 7, Meta: map[string]string{"x": "y"}})
+
+	var m map[string]int
+	c.Assert(multi(bulk("a"), bulk("1"), bulk("b"), integer(2)).Decode(&m), IsNil)
+	c.Check(m, DeepEquals, map[string]int{"a": 1, "b": 2})
+
+	var l []float64
+	c.Assert(multi(bulk("1.5"), integer(2)).Decode(&l), IsNil)
+	c.Check(l, DeepEquals, []float64{1.5, 2})
+
+	var p *string
+	c.Assert(bulk("x").Decode(&p), IsNil)
+	c.Check(*p, Equals, "x")
+
+	var n int
+	c.Check(bulk("x").Decode(&n), NotNil)
+	c.Check(bulk("x").Decode(n), ErrorMatches, "decode target is not a non-nil pointer")
+}
@@ -0,0 +1,182 @@
+package redis
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+//* Memory
+
+// MemoryUsage returns the number of bytes used by the given key and its value,
+// or false if the key does not exist. For nested types, samples is the number of
+// elements sampled to estimate the size; zero uses the server default of 5 and
+// a negative value samples all elements.
+func (c *Client) MemoryUsage(key string, samples int) (int64, bool, error) {
+	return optInt64(c.Cmd("memory", "usage", key, samplesArgs(samples)))
+}
+
+func samplesArgs(samples int) []interface{} {
+	if samples > 0 {
+		return []interface{}{"samples", samples}
+	} else if samples < 0 {
+		return []interface{}{"samples", 0}
+	}
+	return nil
+}
+
+// MemoryStats is the parsed output of MEMORY STATS. Sizes are in bytes.
+type MemoryStats struct {
+	PeakAllocated      int64
+	TotalAllocated     int64
+	StartupAllocated   int64
+	ReplicationBacklog int64
+	ClientsReplicas    int64
+	ClientsNormal      int64
+	AOFBuffer          int64
+	OverheadTotal      int64
+	KeysCount          int64
+	KeysBytesPerKey    int64
+	DatasetBytes       int64
+	DatasetPercentage  float64
+	PeakPercentage     float64
+	Fragmentation      float64
+	DBs                map[int]MemoryDBStats // Overhead by database number
+	Fields             map[string]string     // All scalar fields, including the ones above
+}
+
+// MemoryDBStats is the memory overhead of the hash tables of a database.
+type MemoryDBStats struct {
+	Main    int64 // overhead.hashtable.main
+	Expires int64 // overhead.hashtable.expires
+}
+
+// MemoryStats returns the memory usage details of the server.
+func (c *Client) MemoryStats() (*MemoryStats, error) {
+	m, err := c.Cmd("memory", "stats").pairs()
+	if err != nil {
+		return nil, err
+	}
+	st := &MemoryStats{DBs: map[int]MemoryDBStats{}, Fields: map[string]string{}}
+	for k, r := range m {
+		if strings.HasPrefix(k, "db.") {
+			db, err := strconv.Atoi(k[3:])
+			if err != nil {
+				continue
+			}
+			dm, err := r.pairs()
+			if err != nil {
+				return nil, err
+			}
+			main, ok1 := dm["overhead.hashtable.main"]
+			expires, ok2 := dm["overhead.hashtable.expires"]
+			if !ok1 || !ok2 {
+				return nil, ParseError
+			}
+			var s MemoryDBStats
+			if s.Main, err = main.Int64(); err != nil {
+				return nil, err
+			}
+			if s.Expires, err = expires.Int64(); err != nil {
+				return nil, err
+			}
+			st.DBs[db] = s
+		} else if r.Type == IntegerReply || r.Type == BulkReply || r.Type == StatusReply {
+			st.Fields[k] = r.String()
+		}
+	}
+
+	ints := map[string]*int64{
+		"peak.allocated":      &st.PeakAllocated,
+		"total.allocated":     &st.TotalAllocated,
+		"startup.allocated":   &st.StartupAllocated,
+		"replication.backlog": &st.ReplicationBacklog,
+		"clients.slaves":      &st.ClientsReplicas,
+		"clients.normal":      &st.ClientsNormal,
+		"aof.buffer":          &st.AOFBuffer,
+		"overhead.total":      &st.OverheadTotal,
+		"keys.count":          &st.KeysCount,
+		"keys.bytes-per-key":  &st.KeysBytesPerKey,
+		"dataset.bytes":       &st.DatasetBytes,
+	}
+	for k, p := range ints {
+		if r, ok := m[k]; ok {
+			if *p, err = r.Int64(); err != nil {
+				return nil, err
+			}
+		}
+	}
+	floats := map[string]*float64{
+		"dataset.percentage": &st.DatasetPercentage,
+		"peak.percentage":    &st.PeakPercentage,
+		"fragmentation":      &st.Fragmentation,
+	}
+	for k, p := range floats {
+		if r, ok := m[k]; ok {
+			if *p, err = parseFloat(r); err != nil {
+				return nil, ParseError
+			}
+		}
+	}
+	return st, nil
+}
+
+// MemoryDoctor returns a human readable analysis of the memory usage of the server.
+func (c *Client) MemoryDoctor() (string, error) {
+	return c.Cmd("memory", "doctor").Str()
+}
+
+// KeySize is a key and the number of bytes it uses, see MemoryUsage.
+type KeySize struct {
+	Key   string
+	Bytes int64
+}
+
+// BiggestKeysOptions limits the work done by BiggestKeys.
+type BiggestKeysOptions struct {
+	Match   string // SCAN pattern, all keys if empty
+	Count   int    // SCAN COUNT hint, see Scan
+	MaxKeys int    // Number of keys to measure, all keys if zero
+	Samples int    // See MemoryUsage
+	Top     int    // Number of keys returned, 10 if zero
+}
+
+// BiggestKeys scans the keyspace with SCAN and MEMORY USAGE and returns the biggest
+// keys found, biggest first. As it sends one command per key, MaxKeys should be set
+// on large databases. Keys deleted during the scan are skipped.
+func (c *Client) BiggestKeys(opt BiggestKeysOptions) ([]KeySize, error) {
+	top := opt.Top
+	if top == 0 {
+		top = 10
+	}
+	var sizes []KeySize
+	it := c.Scan(opt.Match, opt.Count)
+	for n := 0; (opt.MaxKeys == 0 || n < opt.MaxKeys) && it.Next(); n++ {
+		b, ok, err := c.MemoryUsage(it.Val(), opt.Samples)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			sizes = addTopKey(sizes, KeySize{it.Val(), b}, top)
+		}
+	}
+	if err := it.Err(); err != nil {
+		return nil, err
+	}
+	return sizes, nil
+}
+
+// addTopKey adds the given key to the given keys sorted by decreasing size, if it is one
+// of the top biggest.
+func addTopKey(sizes []KeySize, ks KeySize, top int) []KeySize {
+	if len(sizes) == top && ks.Bytes <= sizes[top-1].Bytes {
+		return sizes
+	}
+	i := sort.Search(len(sizes), func(i int) bool { return sizes[i].Bytes < ks.Bytes })
+	if len(sizes) < top {
+		sizes = append(sizes, KeySize{})
+	}
+	copy(sizes[i+1:], sizes[i:])
+	sizes[i] = ks
+	return sizes
+}
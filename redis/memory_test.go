@@ -0,0 +1,67 @@
+package redis
+
+import (
+	. "launchpad.net/gocheck"
+)
+
+type MemorySuite struct{}
+
+var _ = Suite(&MemorySuite{})
+
+func (s *MemorySuite) TestMemoryUsage(c *C) {
+	cl, f := fakeClient(":72\r\n$-1\r\n:100\r\n")
+	n, ok, err := cl.MemoryUsage("k", 0)
+	c.Assert(err, IsNil)
+	c.Check(ok, Equals, true)
+	c.Check(n, Equals, int64(72))
+	_, ok, err = cl.MemoryUsage("missing", 10)
+	c.Assert(err, IsNil)
+	c.Check(ok, Equals, false)
+	_, _, err = cl.MemoryUsage("k", -1)
+	c.Assert(err, IsNil)
+	c.Check(f.out.String(), Equals, "*3\r\n$6\r\nmemory\r\n$5\r\nusage\r\n$1\r\nk\r\n"+
+		"*5\r\n$6\r\nmemory\r\n$5\r\nusage\r\n$7\r\nmissing\r\n$7\r\nsamples\r\n$2\r\n10\r\n"+
+		"*5\r\n$6\r\nmemory\r\n$5\r\nusage\r\n$1\r\nk\r\n$7\r\nsamples\r\n$1\r\n0\r\n")
+}
+
+func (s *MemorySuite) TestMemoryStats(c *C) {
+	cl, f := fakeClient("*14\r\n$14\r\npeak.allocated\r\n:2000\r\n" +
+		"$15\r\ntotal.allocated\r\n:1500\r\n$14\r\nclients.slaves\r\n:20\r\n" +
+		"$4\r\ndb.0\r\n*4\r\n$23\r\noverhead.hashtable.main\r\n:72\r\n" +
+		"$26\r\noverhead.hashtable.expires\r\n:32\r\n" +
+		"$10\r\nkeys.count\r\n:3\r\n$18\r\ndataset.percentage\r\n$18\r\n33.333332061767578\r\n" +
+		"$13\r\nfragmentation\r\n$4\r\n1.25\r\n" +
+		"*2\r\n$4\r\ndb.1\r\n*2\r\n$1\r\nx\r\n:1\r\n")
+	st, err := cl.MemoryStats()
+	c.Assert(err, IsNil)
+	c.Check(f.out.String(), Equals, "*2\r\n$6\r\nmemory\r\n$5\r\nstats\r\n")
+	c.Check(st.PeakAllocated, Equals, int64(2000))
+	c.Check(st.TotalAllocated, Equals, int64(1500))
+	c.Check(st.ClientsReplicas, Equals, int64(20))
+	c.Check(st.KeysCount, Equals, int64(3))
+	c.Check(st.DatasetPercentage > 33.3 && st.DatasetPercentage < 33.4, Equals, true)
+	c.Check(st.Fragmentation, Equals, 1.25)
+	c.Check(st.DBs, DeepEquals, map[int]MemoryDBStats{0: {Main: 72, Expires: 32}})
+	c.Check(st.Fields["keys.count"], Equals, "3")
+	c.Check(st.Fields["fragmentation"], Equals, "1.25")
+
+	_, err = cl.MemoryStats()
+	c.Check(err, Equals, ParseError)
+}
+
+func (s *MemorySuite) TestBiggestKeys(c *C) {
+	cl, f := fakeClient("*2\r\n$1\r\n7\r\n*3\r\n$1\r\na\r\n$1\r\nb\r\n$1\r\nc\r\n" +
+		":10\r\n:50\r\n$-1\r\n" +
+		"*2\r\n$1\r\n0\r\n*2\r\n$1\r\nd\r\n$1\r\ne\r\n:30\r\n:40\r\n")
+	l, err := cl.BiggestKeys(BiggestKeysOptions{Match: "*", Top: 2, Samples: 3})
+	c.Assert(err, IsNil)
+	c.Check(l, DeepEquals, []KeySize{{"b", 50}, {"e", 40}})
+	c.Check(f.out.String()[:50], Equals,
+		"*4\r\n$4\r\nscan\r\n$1\r\n0\r\n$5\r\nmatch\r\n$1\r\n*\r\n*5\r\n$6\r\nmem")
+
+	cl, f = fakeClient("*2\r\n$1\r\n7\r\n*3\r\n$1\r\na\r\n$1\r\nb\r\n$1\r\nc\r\n" +
+		":10\r\n:50\r\n")
+	l, err = cl.BiggestKeys(BiggestKeysOptions{MaxKeys: 2})
+	c.Assert(err, IsNil)
+	c.Check(l, DeepEquals, []KeySize{{"b", 50}, {"a", 10}})
+}
@@ -0,0 +1,177 @@
+package redis
+
+import (
+	"net"
+	"time"
+)
+
+//* Key migration
+
+// MigrateOptions describes how MigrateKeys moves keys between servers.
+type MigrateOptions struct {
+	// DumpRestore moves keys with DUMP and RESTORE through the client instead of MIGRATE,
+	// for servers that cannot reach each other.
+	DumpRestore bool
+	Replace     bool          // Overwrite existing keys on the target server
+	Batch       int           // Keys moved per round trip, 0 means 100
+	Timeout     time.Duration // MIGRATE timeout, 0 means 5 seconds
+	DB          int           // MIGRATE target database
+	// Username and Password authenticate MIGRATE to the target server, with AUTH2 if
+	// Username is set (Redis 6.0 or later) and with AUTH otherwise.
+	Username string
+	Password string
+	// Progress is called after each batch with the number of keys moved so far
+	// and the total number of keys.
+	Progress func(moved, total int)
+}
+
+// MigrateKeys moves the given keys from the server of src to the server at addr,
+// whose client is dst. Keys missing on the source server are skipped.
+// Keys are moved in batches, so on error some of the keys may have been moved already.
+// To move a hash tag group, pass all of its keys.
+func MigrateKeys(src, dst *Client, addr string, keys []string, opt MigrateOptions) error {
+	batch := opt.Batch
+	if batch <= 0 {
+		batch = 100
+	}
+	for i := 0; i < len(keys); i += batch {
+		end := i + batch
+		if end > len(keys) {
+			end = len(keys)
+		}
+		var err error
+		if opt.DumpRestore {
+			err = dumpRestore(src, dst, keys[i:end], opt.Replace)
+		} else {
+			err = migrate(src, addr, keys[i:end], opt)
+		}
+		if err != nil {
+			return err
+		}
+		if opt.Progress != nil {
+			opt.Progress(end, len(keys))
+		}
+	}
+	return nil
+}
+
+// Migrate moves the given keys from the server at addr to the servers owning them in the
+// ring, e.g. after AddServer; keys owned by the server at addr are left alone.
+// The Progress function of the options is called with counts over all target servers.
+func (rc *RingClient) Migrate(addr string, keys []string, opt MigrateOptions) error {
+	src, err := rc.conn(addr)
+	if err != nil {
+		return err
+	}
+	var targets []string
+	moves := map[string][]string{}
+	total := 0
+	for _, k := range keys {
+		to, err := rc.Locate(k)
+		if err != nil {
+			return err
+		}
+		if to == addr {
+			continue
+		}
+		if _, ok := moves[to]; !ok {
+			targets = append(targets, to)
+		}
+		moves[to] = append(moves[to], k)
+		total++
+	}
+
+	done := 0
+	progress := opt.Progress
+	for _, to := range targets {
+		dst, err := rc.conn(to)
+		if err != nil {
+			return err
+		}
+		if progress != nil {
+			offset := done
+			opt.Progress = func(moved, _ int) { progress(offset+moved, total) }
+		}
+		if err = MigrateKeys(src, dst, to, moves[to], opt); err != nil {
+			if isConnError(err) {
+				rc.observe(addr, src, &Reply{Type: ErrorReply, Err: err}, 0)
+			}
+			return err
+		}
+		done += len(moves[to])
+	}
+	return nil
+}
+
+// migrate moves the given keys with MIGRATE.
+func migrate(src *Client, addr string, keys []string, opt MigrateOptions) error {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return err
+	}
+	timeout := opt.Timeout
+	if timeout <= 0 {
+		timeout = 5 * time.Second
+	}
+	args := []interface{}{host, port, "", opt.DB, int64(timeout / time.Millisecond)}
+	if opt.Replace {
+		args = append(args, "REPLACE")
+	}
+	if opt.Username != "" {
+		args = append(args, "AUTH2", opt.Username, opt.Password)
+	} else if opt.Password != "" {
+		args = append(args, "AUTH", opt.Password)
+	}
+	args = append(args, "KEYS", keys)
+	// NOKEY status reply means none of the keys exist
+	return src.Cmd("migrate", args...).Err
+}
+
+// dumpRestore moves the given keys with DUMP, PTTL, RESTORE and DEL. The pipelines
+// leave the pipeline queues of the clients alone.
+func dumpRestore(src, dst *Client, keys []string, replace bool) error {
+	var reqs []*request
+	for _, k := range keys {
+		reqs = append(reqs, &request{cmd: "dump", args: []interface{}{k}},
+			&request{cmd: "pttl", args: []interface{}{k}})
+	}
+	replies := src.flush(reqs)
+	var restores []*request
+	var moved []string
+	for i, k := range keys {
+		dump, ttl := replies[2*i], replies[2*i+1]
+		if dump.Type == NilReply {
+			continue
+		}
+		b, err := dump.Bytes()
+		if err != nil {
+			return err
+		}
+		ms, err := ttl.Int64()
+		if err != nil {
+			return err
+		}
+		if ms < 0 {
+			ms = 0
+		}
+		args := []interface{}{k, ms, b}
+		if replace {
+			args = append(args, "REPLACE")
+		}
+		restores = append(restores, &request{cmd: "restore", args: args})
+		moved = append(moved, k)
+	}
+	if len(moved) == 0 {
+		return nil
+	}
+	var err error
+	for _, r := range dst.flush(restores) {
+		if r.Err != nil && err == nil {
+			err = r.Err
+		}
+	}
+	if err != nil {
+		return err
+	}
+	return src.Cmd("del", moved).Err
+}
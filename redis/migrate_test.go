@@ -0,0 +1,88 @@
+package redis
+
+import (
+	. "launchpad.net/gocheck"
+	"strings"
+)
+
+type MigrateSuite struct{}
+
+var _ = Suite(&MigrateSuite{})
+
+func (s *MigrateSuite) TestMigrate(c *C) {
+	src, f := fakeClient("+OK\r\n+NOKEY\r\n")
+	var progress [][2]int
+	opt := MigrateOptions{Batch: 2, Timeout: 1000000, Replace: true}
+	opt.Progress = func(moved, total int) { progress = append(progress, [2]int{moved, total}) }
+	err := MigrateKeys(src, nil, "10.0.0.1:6379", []string{"a", "b", "c"}, opt)
+	c.Assert(err, IsNil)
+	c.Check(progress, DeepEquals, [][2]int{{2, 3}, {3, 3}})
+	c.Check(f.out.String(), Equals, "*10\r\n$7\r\nmigrate\r\n$8\r\n10.0.0.1\r\n$4\r\n6379\r\n"+
+		"$0\r\n\r\n$1\r\n0\r\n$1\r\n1\r\n$7\r\nREPLACE\r\n$4\r\nKEYS\r\n$1\r\na\r\n$1\r\nb\r\n"+
+		"*9\r\n$7\r\nmigrate\r\n$8\r\n10.0.0.1\r\n$4\r\n6379\r\n"+
+		"$0\r\n\r\n$1\r\n0\r\n$1\r\n1\r\n$7\r\nREPLACE\r\n$4\r\nKEYS\r\n$1\r\nc\r\n")
+
+	src, _ = fakeClient("-IOERR error or timeout\r\n")
+	err = MigrateKeys(src, nil, "10.0.0.1:6379", []string{"a"}, MigrateOptions{})
+	c.Check(err, ErrorMatches, "IOERR.*")
+}
+
+func (s *MigrateSuite) TestMigrateAuth(c *C) {
+	src, f := fakeClient("+OK\r\n+OK\r\n")
+	err := MigrateKeys(src, nil, "10.0.0.1:6379", []string{"a"},
+		MigrateOptions{DB: 2, Password: "pw"})
+	c.Assert(err, IsNil)
+	err = MigrateKeys(src, nil, "10.0.0.1:6379", []string{"a"},
+		MigrateOptions{Username: "u", Password: "pw"})
+	c.Assert(err, IsNil)
+	c.Check(f.out.String(), Equals, "*10\r\n$7\r\nmigrate\r\n$8\r\n10.0.0.1\r\n$4\r\n6379\r\n"+
+		"$0\r\n\r\n$1\r\n2\r\n$4\r\n5000\r\n$4\r\nAUTH\r\n$2\r\npw\r\n$4\r\nKEYS\r\n$1\r\na\r\n"+
+		"*11\r\n$7\r\nmigrate\r\n$8\r\n10.0.0.1\r\n$4\r\n6379\r\n"+
+		"$0\r\n\r\n$1\r\n0\r\n$4\r\n5000\r\n$5\r\nAUTH2\r\n$1\r\nu\r\n$2\r\npw\r\n"+
+		"$4\r\nKEYS\r\n$1\r\na\r\n")
+}
+
+func (s *MigrateSuite) TestDumpRestore(c *C) {
+	src, fs := fakeClient("$2\r\nxy\r\n:-1\r\n$-1\r\n:-2\r\n:1\r\n")
+	dst, fd := fakeClient("+OK\r\n")
+	err := MigrateKeys(src, dst, "", []string{"a", "b"}, MigrateOptions{DumpRestore: true})
+	c.Assert(err, IsNil)
+	c.Check(fd.out.String(), Equals, "*4\r\n$7\r\nrestore\r\n$1\r\na\r\n$1\r\n0\r\n$2\r\nxy\r\n")
+	c.Check(strings.HasSuffix(fs.out.String(), "*2\r\n$3\r\ndel\r\n$1\r\na\r\n"), Equals, true)
+
+	// requests appended by the caller are left queued
+	src, _ = fakeClient("$2\r\nxy\r\n:-1\r\n:1\r\n$3\r\nbar\r\n")
+	dst, _ = fakeClient("+OK\r\n$3\r\nbaz\r\n")
+	src.Append("get", "foo")
+	dst.Append("get", "foo")
+	err = MigrateKeys(src, dst, "", []string{"a"}, MigrateOptions{DumpRestore: true})
+	c.Assert(err, IsNil)
+	v, _ := src.GetReply().Str()
+	c.Check(v, Equals, "bar")
+	v, _ = dst.GetReply().Str()
+	c.Check(v, Equals, "baz")
+}
+
+func (s *MigrateSuite) TestRingMigrate(c *C) {
+	rc, _ := DialRing("tcp", []string{"a:1"})
+	keys := []string{"k0", "k1", "k2", "k3", "k4", "k5"}
+	rc.AddServer("b:1")
+	moving := 0
+	for _, k := range keys {
+		if addr, _ := rc.Locate(k); addr == "b:1" {
+			moving++
+		}
+	}
+	c.Assert(moving > 0, Equals, true)
+	src, f := fakeClient("+OK\r\n")
+	rc.clients["a:1"] = src
+	rc.clients["b:1"], _ = fakeClient("")
+
+	var moved, total int
+	opt := MigrateOptions{Progress: func(m, t int) { moved, total = m, t }}
+	c.Assert(rc.Migrate("a:1", keys, opt), IsNil)
+	c.Check(moved, Equals, moving)
+	c.Check(total, Equals, moving)
+	c.Check(strings.Count(f.out.String(), "migrate"), Equals, 1)
+	c.Check(strings.Count(f.out.String(), "\r\nk"), Equals, moving)
+}
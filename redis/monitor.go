@@ -0,0 +1,129 @@
+package redis
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+)
+
+//* Monitor
+
+// MonitorEvent is a command processed by the server, as reported by MONITOR.
+type MonitorEvent struct {
+	Time time.Time
+	DB   int
+	Addr string   // Address of the calling client, "lua" for commands run by scripts
+	Args []string // Command and arguments
+}
+
+// Monitor puts the connection of the client in MONITOR mode and calls the handler
+// for every command processed by the server, until the context is done or the
+// connection fails. The client cannot run other commands once in MONITOR mode, so
+// Monitor closes it before returning; use a client dedicated to it.
+//
+// MONITOR slows the server down noticeably, it is meant for debugging.
+func (c *Client) Monitor(ctx context.Context, handler func(MonitorEvent)) error {
+	defer c.Close()
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if err := c.Cmd("monitor").Err; err != nil {
+		return err
+	}
+
+	saved := c.timeout
+	c.timeout = 0
+	defer func() { c.timeout = saved }()
+	conn := c.conn
+	done := make(chan struct{})
+	exited := make(chan struct{})
+	go func() {
+		defer close(exited)
+		select {
+		case <-ctx.Done():
+			conn.Close()
+		case <-done:
+		}
+	}()
+	defer func() {
+		close(done)
+		<-exited
+	}()
+
+	for {
+		s, err := c.readReply().Str()
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			return err
+		}
+		ev, err := parseMonitorEvent(s)
+		if err != nil {
+			return err
+		}
+		handler(ev)
+	}
+}
+
+// parseMonitorEvent parses a line of MONITOR output, of the form
+// `1339518083.107412 [0 127.0.0.1:60866] "set" "foo" "bar"`.
+func parseMonitorEvent(s string) (MonitorEvent, error) {
+	var ev MonitorEvent
+	i := strings.Index(s, " [")
+	j := strings.Index(s, "] ")
+	if i < 0 || j < i {
+		return ev, ParseError
+	}
+
+	ts := s[:i]
+	var usec int64
+	if k := strings.IndexByte(ts, '.'); k >= 0 {
+		var err error
+		if usec, err = strconv.ParseInt(ts[k+1:], 10, 64); err != nil {
+			return ev, ParseError
+		}
+		ts = ts[:k]
+	}
+	sec, err := strconv.ParseInt(ts, 10, 64)
+	if err != nil {
+		return ev, ParseError
+	}
+	ev.Time = time.Unix(sec, usec*int64(time.Microsecond))
+
+	src := s[i+2 : j]
+	k := strings.IndexByte(src, ' ')
+	if k < 0 {
+		return ev, ParseError
+	}
+	if ev.DB, err = strconv.Atoi(src[:k]); err != nil {
+		return ev, ParseError
+	}
+	ev.Addr = src[k+1:]
+
+	rest := s[j+2:]
+	for rest != "" {
+		if rest[0] != '"' {
+			return ev, ParseError
+		}
+		// find the closing quote, skipping escaped characters
+		k := 1
+		for k < len(rest) && rest[k] != '"' {
+			if rest[k] == '\\' {
+				k++
+			}
+			k++
+		}
+		if k >= len(rest) {
+			return ev, ParseError
+		}
+		arg, err := strconv.Unquote(rest[:k+1])
+		if err != nil {
+			return ev, ParseError
+		}
+		ev.Args = append(ev.Args, arg)
+		rest = strings.TrimPrefix(rest[k+1:], " ")
+	}
+	return ev, nil
+}
@@ -0,0 +1,63 @@
+package redis
+
+import (
+	"context"
+	. "launchpad.net/gocheck"
+	"time"
+)
+
+type MonitorSuite struct{}
+
+var _ = Suite(&MonitorSuite{})
+
+func (s *MonitorSuite) TestParseMonitorEvent(c *C) {
+	ev, err := parseMonitorEvent(`1339518083.107412 [0 127.0.0.1:60866] ` +
+		`"set" "foo" "a b\"\x00\r\n"`)
+	c.Assert(err, IsNil)
+	c.Check(ev, DeepEquals, MonitorEvent{
+		Time: time.Unix(1339518083, 107412000),
+		Addr: "127.0.0.1:60866",
+		Args: []string{"set", "foo", "a b\"\x00\r\n"},
+	})
+	ev, err = parseMonitorEvent(`1339518083.000001 [3 lua] "get" "k\\"`)
+	c.Assert(err, IsNil)
+	c.Check(ev.DB, Equals, 3)
+	c.Check(ev.Addr, Equals, "lua")
+	c.Check(ev.Args, DeepEquals, []string{"get", `k\`})
+
+	for _, line := range []string{"OK", `x.1 [0 lua] "get"`, `1.1 [0 lua] "get`,
+		`1.1 [a lua] "get"`, `1.1 [0 lua] get`, `1.1 [0] "get"`} {
+		_, err = parseMonitorEvent(line)
+		c.Check(err, Equals, ParseError)
+	}
+}
+
+func (s *MonitorSuite) TestMonitor(c *C) {
+	cl, f := fakeClient("+OK\r\n+1.5 [0 127.0.0.1:1] \"ping\"\r\n" +
+		"+2.0 [1 127.0.0.1:2] \"get\" \"k\"\r\n")
+	var evs []MonitorEvent
+	err := cl.Monitor(context.Background(), func(ev MonitorEvent) {
+		evs = append(evs, ev)
+	})
+	c.Check(err, NotNil)
+	c.Check(f.out.String(), Equals, "*1\r\n$7\r\nmonitor\r\n")
+	c.Assert(evs, HasLen, 2)
+	c.Check(evs[0].Args, DeepEquals, []string{"ping"})
+	c.Check(evs[1].DB, Equals, 1)
+
+	cl, _ = fakeClient("-ERR not allowed\r\n")
+	err = cl.Monitor(context.Background(), func(MonitorEvent) {})
+	c.Check(err, ErrorMatches, "ERR not allowed")
+}
+
+func (s *MonitorSuite) TestMonitorCancel(c *C) {
+	addr := fakeServer(c, "+OK\r\n+1.0 [0 127.0.0.1:1] \"ping\"\r\n")
+	cl, err := Dial("tcp", addr)
+	c.Assert(err, IsNil)
+	ctx, cancel := context.WithCancel(context.Background())
+	err = cl.Monitor(ctx, func(ev MonitorEvent) {
+		c.Check(ev.Args, DeepEquals, []string{"ping"})
+		cancel()
+	})
+	c.Check(err, Equals, context.Canceled)
+}
@@ -0,0 +1,108 @@
+package redis
+
+import (
+	"strings"
+	"sync"
+	"time"
+)
+
+//* Multi-key command splitting
+
+// splitKeys splits MGET, MSET, DEL, UNLINK, EXISTS and TOUCH into one request per shard
+// of their keys, as returned by the given function.
+// The returned function merges the replies of the requests, given in request order,
+// into the reply of the original command. ok is false for other commands.
+func splitKeys(cmd string, args []interface{},
+	shard func(key []byte) string) (reqs []*request, merge func([]*Reply) *Reply, ok bool) {
+	lc := strings.ToLower(cmd)
+	flat := flattenArgs(args)
+	step := 1
+	switch lc {
+	case "mget", "del", "unlink", "exists", "touch":
+	case "mset":
+		if len(flat)%2 != 0 {
+			return nil, nil, false
+		}
+		step = 2
+	default:
+		return nil, nil, false
+	}
+
+	// position of each key: its request and index in the request
+	type position struct{ req, i int }
+	var pos []position
+	index := map[string]int{}
+	for i := 0; i < len(flat); i += step {
+		sh := shard(argBytes(flat[i]))
+		ri, ok := index[sh]
+		if !ok {
+			ri = len(reqs)
+			index[sh] = ri
+			reqs = append(reqs, &request{cmd: cmd})
+		}
+		pos = append(pos, position{ri, len(reqs[ri].args) / step})
+		reqs[ri].args = append(reqs[ri].args, flat[i:i+step]...)
+	}
+
+	merge = func(replies []*Reply) *Reply {
+		for _, r := range replies {
+			if r.Type == ErrorReply {
+				return r
+			}
+		}
+		switch lc {
+		case "mget":
+			elems := make([]*Reply, len(pos))
+			for k, p := range pos {
+				r := replies[p.req]
+				if r.Type != MultiReply || p.i >= len(r.Elems) {
+					return &Reply{Type: ErrorReply, Err: ParseError}
+				}
+				elems[k] = r.Elems[p.i]
+			}
+			return &Reply{Type: MultiReply, Elems: elems}
+		case "mset":
+			return replies[0]
+		}
+		var n int64
+		for _, r := range replies {
+			if r.Type != IntegerReply {
+				return &Reply{Type: ErrorReply, Err: ParseError}
+			}
+			n += r.int
+		}
+		return &Reply{Type: IntegerReply, int: n}
+	}
+	return reqs, merge, true
+}
+
+//* Parallel pipelines
+
+// runBatches sends the requests of each batch, given by index per node, through a
+// pipeline on the client of the node, with the nodes in parallel.
+// It stores the replies at the index of their request and returns the time each reply
+// took, i.e. the duration of its batch divided evenly among the batch's requests.
+func runBatches(reqs []*request, replies []*Reply, batches map[string][]int,
+	clients map[string]*Client) []time.Duration {
+	elapsed := make([]time.Duration, len(reqs))
+	var wg sync.WaitGroup
+	for addr, idx := range batches {
+		wg.Add(1)
+		go func(c *Client, idx []int) {
+			defer wg.Done()
+			start := time.Now()
+			for _, i := range idx {
+				c.Append(reqs[i].cmd, reqs[i].args...)
+			}
+			for _, i := range idx {
+				replies[i] = c.GetReply()
+			}
+			d := time.Since(start) / time.Duration(len(idx))
+			for _, i := range idx {
+				elapsed[i] = d
+			}
+		}(clients[addr], idx)
+	}
+	wg.Wait()
+	return elapsed
+}
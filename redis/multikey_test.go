@@ -0,0 +1,81 @@
+package redis
+
+import (
+	. "launchpad.net/gocheck"
+)
+
+type MultiKeySuite struct{}
+
+var _ = Suite(&MultiKeySuite{})
+
+// firstLetter shards keys by their first letter.
+func firstLetter(key []byte) string {
+	return string(key[:1])
+}
+
+func (s *MultiKeySuite) TestSplitMget(c *C) {
+	reqs, merge, ok := splitKeys("MGET", []interface{}{"a1", "b1", "a2"}, firstLetter)
+	c.Assert(ok, Equals, true)
+	c.Assert(reqs, HasLen, 2)
+	c.Check(reqs[0].args, DeepEquals, []interface{}{"a1", "a2"})
+	c.Check(reqs[1].args, DeepEquals, []interface{}{"b1"})
+
+	r := merge([]*Reply{multi(bulk("x1"), bulk("x2")), multi(&Reply{Type: NilReply})})
+	l, err := r.List()
+	c.Assert(err, IsNil)
+	c.Check(l, DeepEquals, []string{"x1", "", "x2"})
+}
+
+func (s *MultiKeySuite) TestSplitMset(c *C) {
+	reqs, merge, ok := splitKeys("mset", []interface{}{"a1", 1, "b1", 2, "a2", 3}, firstLetter)
+	c.Assert(ok, Equals, true)
+	c.Check(reqs[0].args, DeepEquals, []interface{}{"a1", 1, "a2", 3})
+	c.Check(reqs[1].args, DeepEquals, []interface{}{"b1", 2})
+	ok1 := &Reply{Type: StatusReply, buf: []byte("OK")}
+	c.Check(merge([]*Reply{ok1, ok1}), Equals, ok1)
+
+	fail := &Reply{Type: ErrorReply, Err: ParseError}
+	c.Check(merge([]*Reply{ok1, fail}), Equals, fail)
+
+	_, _, ok = splitKeys("mset", []interface{}{"a1"}, firstLetter)
+	c.Check(ok, Equals, false)
+	_, _, ok = splitKeys("msetnx", []interface{}{"a1", 1}, firstLetter)
+	c.Check(ok, Equals, false)
+}
+
+func (s *MultiKeySuite) TestSplitDel(c *C) {
+	_, merge, ok := splitKeys("del", []interface{}{[]string{"a1", "b1", "c1"}}, firstLetter)
+	c.Assert(ok, Equals, true)
+	n, err := merge([]*Reply{integer(1), integer(0), integer(1)}).Int()
+	c.Assert(err, IsNil)
+	c.Check(n, Equals, 2)
+}
+
+func (s *MultiKeySuite) TestClusterMget(c *C) {
+	// foo and qux live on the second node, bar on the first
+	cc, _, fb := twoNodeCluster("*1\r\n$1\r\nb\r\n", "*1\r\n$1\r\nf\r\n*1\r\n$1\r\nq\r\n")
+	l, err := cc.Cmd("mget", "foo", "bar", "qux").List()
+	c.Assert(err, IsNil)
+	c.Check(l, DeepEquals, []string{"f", "b", "q"})
+	c.Check(fb.out.String(), Equals,
+		"*2\r\n$4\r\nmget\r\n$3\r\nfoo\r\n*2\r\n$4\r\nmget\r\n$3\r\nqux\r\n")
+}
+
+func (s *MultiKeySuite) TestRingDel(c *C) {
+	rc, _ := DialRing("tcp", []string{"a:1", "b:1"})
+	rc.SetHash(func(b []byte) uint32 { return uint32(b[0]) << 24 })
+	rc.SetVirtualNodes(1)
+	x, _ := rc.Locate("x")
+	for _, addr := range rc.Servers() {
+		rc.clients[addr], _ = fakeClient(":1\r\n")
+	}
+	y := "x"
+	for k := byte('a'); y == "x"; k++ {
+		if addr, _ := rc.Locate(string(k)); addr != x {
+			y = string(k)
+		}
+	}
+	n, err := rc.Cmd("del", "x", y).Int()
+	c.Assert(err, IsNil)
+	c.Check(n, Equals, 2)
+}
@@ -0,0 +1,264 @@
+package redis
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+//* Stream producer
+
+// TrimPolicy describes how a Producer keeps a stream from growing unbounded.
+// With both MaxLen and Retention set, the stream is trimmed by both.
+type TrimPolicy struct {
+	MaxLen    int64         // Keep about this many entries, 0 disables
+	Retention time.Duration // Evict entries added longer ago than this, 0 disables
+	Exact     bool          // Trim exactly instead of approximately ("~")
+	// Interval makes the producer trim with XTRIM at most once per Interval, when adding
+	// entries or calling Trim, instead of trimming on every XADD.
+	Interval time.Duration
+}
+
+// Producer adds entries to streams, trimming them according to their policies.
+// Like Client, Producer is not safe for concurrent use.
+type Producer struct {
+	c        *Client
+	policies map[string]TrimPolicy
+	trimmed  map[string]time.Time
+}
+
+// NewProducer returns a Producer adding entries with the given client.
+func NewProducer(c *Client) *Producer {
+	return &Producer{c: c, policies: map[string]TrimPolicy{}, trimmed: map[string]time.Time{}}
+}
+
+// SetTrimPolicy sets the trim policy of the given stream.
+// Streams without a policy are not trimmed.
+func (p *Producer) SetTrimPolicy(stream string, policy TrimPolicy) {
+	p.policies[stream] = policy
+}
+
+// Add adds an entry with the given fields to the given stream and returns its ID,
+// trimming the stream according to its policy.
+func (p *Producer) Add(stream string, fields map[string]interface{}) (string, error) {
+	policy, ok := p.policies[stream]
+	trims := policy.trims(time.Now())
+	if !ok || policy.Interval > 0 || len(trims) == 0 {
+		id, err := p.c.XAdd(stream, fields, XAddOptions{})
+		if err == nil && ok {
+			err = p.trim(stream, policy)
+		}
+		return id, err
+	}
+
+	// trim on write: one strategy with XADD, the other one with XTRIM
+	opt := XAddOptions{MaxLen: trims[0].MaxLen, MinID: trims[0].MinID, Approx: trims[0].Approx}
+	args, err := xaddArgs(stream, fields, opt)
+	if err != nil {
+		return "", err
+	}
+	reqs := []*request{{cmd: "xadd", args: args}}
+	for _, t := range trims[1:] {
+		reqs = append(reqs, &request{cmd: "xtrim", args: append([]interface{}{stream},
+			t.args()...)})
+	}
+	replies := p.c.flush(reqs)
+	id, err := xaddID(replies[0])
+	for _, r := range replies[1:] {
+		if r.Type == ErrorReply && err == nil {
+			err = r.Err
+		}
+	}
+	return id, err
+}
+
+// Trim trims the streams whose trim policies have an Interval that has passed.
+func (p *Producer) Trim() error {
+	for stream, policy := range p.policies {
+		if policy.Interval > 0 {
+			if err := p.trim(stream, policy); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// trim trims the given stream with XTRIM if its interval has passed.
+func (p *Producer) trim(stream string, policy TrimPolicy) error {
+	now := time.Now()
+	if now.Sub(p.trimmed[stream]) < policy.Interval {
+		return nil
+	}
+	for _, t := range policy.trims(now) {
+		if _, err := p.c.XTrim(stream, t); err != nil {
+			return err
+		}
+	}
+	p.trimmed[stream] = now
+	return nil
+}
+
+// trims returns the trim options of the policy at the given time.
+func (policy TrimPolicy) trims(now time.Time) []XTrimOptions {
+	var trims []XTrimOptions
+	if policy.MaxLen > 0 {
+		trims = append(trims, XTrimOptions{MaxLen: policy.MaxLen, Approx: !policy.Exact})
+	}
+	if policy.Retention > 0 {
+		minID := StreamIDAt(now.Add(-policy.Retention)).String()
+		trims = append(trims, XTrimOptions{MinID: minID, Approx: !policy.Exact})
+	}
+	return trims
+}
+
+//* Async producer
+
+var ProducerClosedError error = errors.New("producer is closed")
+
+// AsyncProducerOptions describes the options of NewAsyncProducer.
+type AsyncProducerOptions struct {
+	BatchSize     int           // Flush after this many entries, 0 means 100
+	FlushInterval time.Duration // Flush entries waiting this long, 0 means 10 milliseconds
+	// QueueSize is the number of entries buffered before Add blocks, 0 means 10 batches.
+	QueueSize int
+}
+
+// AddFuture is the pending result of AsyncProducer.Add.
+type AddFuture struct {
+	done chan struct{}
+	id   string
+	err  error
+}
+
+// Done returns a channel closed once the entry is added or has failed.
+func (f *AddFuture) Done() <-chan struct{} {
+	return f.done
+}
+
+// Wait waits for the entry to be added and returns its ID.
+func (f *AddFuture) Wait() (string, error) {
+	<-f.done
+	return f.id, f.err
+}
+
+func (f *AddFuture) resolve(id string, err error) {
+	f.id, f.err = id, err
+	close(f.done)
+}
+
+type asyncEntry struct {
+	stream string
+	fields map[string]interface{}
+	future *AddFuture
+}
+
+// AsyncProducer adds entries to streams in the background, sending them to the
+// server in pipelined batches. Unlike Producer, AsyncProducer is safe for concurrent
+// use. It owns its client, which must not be used elsewhere until Close returns.
+type AsyncProducer struct {
+	c       *Client
+	opt     AsyncProducerOptions
+	entries chan *asyncEntry
+	done    chan struct{}
+	mu      sync.RWMutex
+	closed  bool
+}
+
+// NewAsyncProducer returns an AsyncProducer adding entries with the given client.
+func NewAsyncProducer(c *Client, opt AsyncProducerOptions) *AsyncProducer {
+	if opt.BatchSize <= 0 {
+		opt.BatchSize = 100
+	}
+	if opt.FlushInterval <= 0 {
+		opt.FlushInterval = 10 * time.Millisecond
+	}
+	if opt.QueueSize <= 0 {
+		opt.QueueSize = 10 * opt.BatchSize
+	}
+	p := &AsyncProducer{
+		c:       c,
+		opt:     opt,
+		entries: make(chan *asyncEntry, opt.QueueSize),
+		done:    make(chan struct{}),
+	}
+	go p.run()
+	return p
+}
+
+// Add queues an entry with the given fields for the given stream and returns its
+// future. Add blocks while the queue is full, slowing callers down to the rate the
+// server accepts entries at.
+func (p *AsyncProducer) Add(stream string, fields map[string]interface{}) *AddFuture {
+	f := &AddFuture{done: make(chan struct{})}
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	if p.closed {
+		f.resolve("", ProducerClosedError)
+		return f
+	}
+	if len(fields) == 0 {
+		f.resolve("", errors.New("stream entry has no fields"))
+		return f
+	}
+	p.entries <- &asyncEntry{stream, fields, f}
+	return f
+}
+
+// Close flushes the queued entries and stops the producer. It does not close the client.
+func (p *AsyncProducer) Close() {
+	p.mu.Lock()
+	if !p.closed {
+		p.closed = true
+		close(p.entries)
+	}
+	p.mu.Unlock()
+	<-p.done
+}
+
+// run collects queued entries into batches and flushes them.
+func (p *AsyncProducer) run() {
+	defer close(p.done)
+	var batch []*asyncEntry
+	var timeout <-chan time.Time
+	for {
+		select {
+		case e, ok := <-p.entries:
+			if !ok {
+				p.flush(batch)
+				return
+			}
+			batch = append(batch, e)
+			if len(batch) == 1 {
+				timeout = time.After(p.opt.FlushInterval)
+			}
+			if len(batch) < p.opt.BatchSize {
+				continue
+			}
+		case <-timeout:
+		}
+		p.flush(batch)
+		batch, timeout = nil, nil
+	}
+}
+
+// flush adds the given entries in one pipeline and resolves their futures.
+func (p *AsyncProducer) flush(batch []*asyncEntry) {
+	var sent []*asyncEntry
+	var reqs []*request
+	for _, e := range batch {
+		args, err := xaddArgs(e.stream, e.fields, XAddOptions{})
+		if err != nil {
+			e.future.resolve("", err)
+			continue
+		}
+		reqs = append(reqs, &request{cmd: "xadd", args: args})
+		sent = append(sent, e)
+	}
+	if len(reqs) == 0 {
+		return
+	}
+	for i, r := range p.c.flush(reqs) {
+		sent[i].future.resolve(xaddID(r))
+	}
+}
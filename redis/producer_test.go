@@ -0,0 +1,94 @@
+package redis
+
+import (
+	. "launchpad.net/gocheck"
+	"strings"
+	"time"
+)
+
+type ProducerSuite struct{}
+
+var _ = Suite(&ProducerSuite{})
+
+func (s *ProducerSuite) TestTrimOnWrite(c *C) {
+	cl, f := fakeClient("$3\r\n1-0\r\n:0\r\n")
+	p := NewProducer(cl)
+	p.SetTrimPolicy("s", TrimPolicy{MaxLen: 100, Retention: time.Hour})
+	id, err := p.Add("s", map[string]interface{}{"a": 1})
+	c.Assert(err, IsNil)
+	c.Check(id, Equals, "1-0")
+	out := f.out.String()
+	c.Check(strings.HasPrefix(out, "*8\r\n$4\r\nxadd\r\n$1\r\ns\r\n$6\r\nmaxlen\r\n$1\r\n~\r\n"+
+		"$3\r\n100\r\n"), Equals, true)
+	xtrim := "$5\r\nxtrim\r\n$1\r\ns\r\n$5\r\nminid\r\n$1\r\n~\r\n"
+	c.Check(strings.Contains(out, xtrim), Equals, true)
+
+	// requests appended by the caller are left queued
+	cl, _ = fakeClient("$3\r\n2-0\r\n:0\r\n$3\r\nbar\r\n")
+	p = NewProducer(cl)
+	p.SetTrimPolicy("s", TrimPolicy{MaxLen: 100, Retention: time.Hour})
+	cl.Append("get", "foo")
+	id, err = p.Add("s", map[string]interface{}{"a": 1})
+	c.Assert(err, IsNil)
+	c.Check(id, Equals, "2-0")
+	v, _ := cl.GetReply().Str()
+	c.Check(v, Equals, "bar")
+}
+
+func (s *ProducerSuite) TestTrimInterval(c *C) {
+	cl, f := fakeClient("$3\r\n1-0\r\n:5\r\n$3\r\n2-0\r\n")
+	p := NewProducer(cl)
+	p.SetTrimPolicy("s", TrimPolicy{MaxLen: 10, Exact: true, Interval: time.Hour})
+	_, err := p.Add("s", map[string]interface{}{"a": 1})
+	c.Assert(err, IsNil)
+	_, err = p.Add("s", map[string]interface{}{"a": 2})
+	c.Assert(err, IsNil)
+	c.Assert(p.Trim(), IsNil)
+	c.Check(strings.Count(f.out.String(), "xtrim\r\n$1\r\ns\r\n$6\r\nmaxlen\r\n$2\r\n10\r\n"),
+		Equals, 1)
+}
+
+func (s *ProducerSuite) TestTrims(c *C) {
+	now := time.Unix(1000, 0)
+	c.Check(TrimPolicy{Retention: time.Second, Exact: true}.trims(now), DeepEquals,
+		[]XTrimOptions{{MinID: "999000-0"}})
+	c.Check(TrimPolicy{}.trims(now), HasLen, 0)
+}
+
+func (s *ProducerSuite) TestAsyncProducer(c *C) {
+	cl, f := fakeClient("$3\r\n1-0\r\n$3\r\n2-0\r\n$3\r\n3-0\r\n")
+	p := NewAsyncProducer(cl, AsyncProducerOptions{BatchSize: 2, FlushInterval: time.Hour})
+	f1 := p.Add("s", map[string]interface{}{"a": 1})
+	f2 := p.Add("s", map[string]interface{}{"a": 2})
+	id, err := f2.Wait()
+	c.Assert(err, IsNil)
+	c.Check(id, Equals, "2-0")
+	id, _ = f1.Wait()
+	c.Check(id, Equals, "1-0")
+
+	// flushed on close, before the interval
+	f3 := p.Add("s", map[string]interface{}{"a": 3})
+	_, err = p.Add("s", nil).Wait()
+	c.Check(err, NotNil)
+	select {
+	case <-f3.Done():
+		c.Fatal("flushed before batch is full")
+	default:
+	}
+	p.Close()
+	id, _ = f3.Wait()
+	c.Check(id, Equals, "3-0")
+	c.Check(strings.Count(f.out.String(), "xadd"), Equals, 3)
+
+	_, err = p.Add("s", map[string]interface{}{"a": 4}).Wait()
+	c.Check(err, Equals, ProducerClosedError)
+}
+
+func (s *ProducerSuite) TestAsyncProducerInterval(c *C) {
+	cl, _ := fakeClient("$3\r\n1-0\r\n")
+	p := NewAsyncProducer(cl, AsyncProducerOptions{FlushInterval: time.Millisecond})
+	defer p.Close()
+	id, err := p.Add("s", map[string]interface{}{"a": 1}).Wait()
+	c.Assert(err, IsNil)
+	c.Check(id, Equals, "1-0")
+}
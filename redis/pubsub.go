@@ -0,0 +1,27 @@
+package redis
+
+import (
+	"errors"
+)
+
+//* Pub/Sub introspection
+
+// PubsubChannels returns the currently active channels,
+// optionally filtered by the given glob-style pattern.
+func (c *Client) PubsubChannels(pattern ...string) ([]string, error) {
+	if len(pattern) > 1 {
+		return nil, errors.New("at most one pattern can be given")
+	}
+	return c.Cmd("pubsub", "channels", pattern).List()
+}
+
+// PubsubNumsub returns the number of subscribers (not counting pattern subscribers)
+// for the given channels.
+func (c *Client) PubsubNumsub(channels ...string) (map[string]int64, error) {
+	return c.Cmd("pubsub", "numsub", channels).intMap()
+}
+
+// PubsubNumpat returns the number of active pattern subscriptions.
+func (c *Client) PubsubNumpat() (int64, error) {
+	return c.Cmd("pubsub", "numpat").Int64()
+}
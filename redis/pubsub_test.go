@@ -0,0 +1,34 @@
+package redis
+
+import (
+	. "launchpad.net/gocheck"
+)
+
+type PubsubSuite struct{}
+
+var _ = Suite(&PubsubSuite{})
+
+func (s *PubsubSuite) TestPubsubChannels(c *C) {
+	cl, f := fakeClient("*2\r\n$3\r\nfoo\r\n$3\r\nbar\r\n")
+	l, err := cl.PubsubChannels("f*")
+	c.Assert(err, IsNil)
+	c.Check(l, DeepEquals, []string{"foo", "bar"})
+	c.Check(f.out.String(), Equals, "*3\r\n$6\r\npubsub\r\n$8\r\nchannels\r\n$2\r\nf*\r\n")
+
+	_, err = cl.PubsubChannels("a", "b")
+	c.Check(err, NotNil)
+}
+
+func (s *PubsubSuite) TestPubsubNumsub(c *C) {
+	cl, _ := fakeClient("*4\r\n$3\r\nfoo\r\n:2\r\n$3\r\nbar\r\n:0\r\n")
+	m, err := cl.PubsubNumsub("foo", "bar")
+	c.Assert(err, IsNil)
+	c.Check(m, DeepEquals, map[string]int64{"foo": 2, "bar": 0})
+}
+
+func (s *PubsubSuite) TestPubsubNumpat(c *C) {
+	cl, _ := fakeClient(":3\r\n")
+	n, err := cl.PubsubNumpat()
+	c.Assert(err, IsNil)
+	c.Check(n, Equals, int64(3))
+}
@@ -0,0 +1,77 @@
+package redis
+
+import (
+	"errors"
+	"log/slog"
+	"time"
+)
+
+//* Quarantine
+
+var QuarantinedError error = errors.New("node is quarantined after repeated failures")
+
+// QuarantinePolicy describes when ClusterClient and RingClient stop using a failing node.
+// After Failures consecutive dial or connection failures, the node is quarantined for Backoff.
+// The first command after the quarantine probes the node; if it fails, the node is
+// quarantined again for twice the previous period, up to MaxBackoff.
+// While a node is quarantined, commands to it fail with QuarantinedError, except read-only
+// commands of ClusterClient, which are sent to a healthy replica if there is one.
+type QuarantinePolicy struct {
+	Failures   int // Consecutive failures before quarantine, 0 disables quarantine
+	Backoff    time.Duration
+	MaxBackoff time.Duration
+}
+
+// DefaultQuarantinePolicy is the quarantine policy of newly dialed clients.
+var DefaultQuarantinePolicy = QuarantinePolicy{
+	Failures:   3,
+	Backoff:    time.Second,
+	MaxBackoff: time.Minute,
+}
+
+type nodeHealth struct {
+	failures int
+	backoff  time.Duration
+	until    time.Time
+}
+
+// quarantine tracks the health of nodes.
+type quarantine map[string]*nodeHealth
+
+// blocked returns true if the given node is quarantined.
+func (q quarantine) blocked(addr string) bool {
+	h, ok := q[addr]
+	return ok && time.Now().Before(h.until)
+}
+
+// failed records a failure of the given node.
+func (q quarantine) failed(p QuarantinePolicy, addr string) {
+	if p.Failures <= 0 {
+		return
+	}
+	h, ok := q[addr]
+	if !ok {
+		h = &nodeHealth{}
+		q[addr] = h
+	}
+	h.failures++
+	if h.failures < p.Failures {
+		return
+	}
+	if h.backoff == 0 {
+		h.backoff = p.Backoff
+	} else {
+		h.backoff *= 2
+	}
+	if p.MaxBackoff > 0 && h.backoff > p.MaxBackoff {
+		h.backoff = p.MaxBackoff
+	}
+	h.until = time.Now().Add(h.backoff)
+	logEvent(slog.LevelWarn, "redis: node quarantined", "addr", addr, "failures", h.failures,
+		"backoff", h.backoff)
+}
+
+// succeeded records a successful command on the given node.
+func (q quarantine) succeeded(addr string) {
+	delete(q, addr)
+}
@@ -0,0 +1,62 @@
+package redis
+
+import (
+	. "launchpad.net/gocheck"
+	"time"
+)
+
+type QuarantineSuite struct{}
+
+var _ = Suite(&QuarantineSuite{})
+
+func (s *QuarantineSuite) TestQuarantine(c *C) {
+	ms := time.Millisecond
+	p := QuarantinePolicy{Failures: 2, Backoff: 20 * ms, MaxBackoff: 30 * ms}
+	q := quarantine{}
+	q.failed(p, "a")
+	c.Check(q.blocked("a"), Equals, false)
+	q.failed(p, "a")
+	c.Check(q.blocked("a"), Equals, true)
+	c.Check(q["a"].backoff, Equals, 20*time.Millisecond)
+
+	// the probe after the quarantine fails
+	time.Sleep(25 * time.Millisecond)
+	c.Check(q.blocked("a"), Equals, false)
+	q.failed(p, "a")
+	c.Check(q.blocked("a"), Equals, true)
+	c.Check(q["a"].backoff, Equals, 30*time.Millisecond)
+
+	q.succeeded("a")
+	c.Check(q.blocked("a"), Equals, false)
+
+	q.failed(QuarantinePolicy{}, "b")
+	c.Check(q, HasLen, 0)
+}
+
+func (s *QuarantineSuite) TestClusterQuarantine(c *C) {
+	cc, _ := fakeCluster("")
+	cc.Quarantine = QuarantinePolicy{Failures: 1, Backoff: time.Minute}
+	replica, _ := fakeClient("+OK\r\n$1\r\nr\r\n")
+	cc.clients["127.0.0.1:7001"] = replica
+	cc.replicas["127.0.0.1:7001"] = true
+	r := &slotRange{0, numSlots - 1, []string{"127.0.0.1:7000", "127.0.0.1:7001"}}
+	for i := range cc.slots {
+		cc.slots[i] = r
+	}
+
+	c.Check(isConnError(cc.Cmd("set", "foo", "bar").Err), Equals, true)
+	c.Check(cc.health.blocked("127.0.0.1:7000"), Equals, true)
+	cc.stale = false
+
+	c.Check(cc.Cmd("set", "foo", "bar").Err, Equals, QuarantinedError)
+	cc.stale = false
+	v, _ := cc.Cmd("get", "foo").Str()
+	c.Check(v, Equals, "r")
+}
+
+func (s *QuarantineSuite) TestRingQuarantine(c *C) {
+	rc, _ := DialRing("tcp", []string{"127.0.0.1:1"})
+	rc.Quarantine = QuarantinePolicy{Failures: 1, Backoff: time.Minute}
+	c.Check(rc.Cmd("get", "foo").Err, Not(Equals), QuarantinedError)
+	c.Check(rc.Cmd("get", "foo").Err, Equals, QuarantinedError)
+}
@@ -0,0 +1,127 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+//* Job queue
+
+// Codec encodes and decodes the payloads of queued jobs.
+type Codec interface {
+	Marshal(v interface{}) ([]byte, error)
+	Unmarshal(data []byte, v interface{}) error
+}
+
+// JSONCodec is a Codec using encoding/json.
+type JSONCodec struct{}
+
+func (JSONCodec) Marshal(v interface{}) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+func (JSONCodec) Unmarshal(data []byte, v interface{}) error {
+	return json.Unmarshal(data, v)
+}
+
+// Job describes a job taken from a Queue.
+type Job struct {
+	ID    string // ID of the stream entry
+	data  []byte
+	codec Codec
+}
+
+// Decode decodes the payload of the job into the value pointed to by v.
+func (j *Job) Decode(v interface{}) error {
+	return j.codec.Unmarshal(j.data, v)
+}
+
+// JobHandler processes a job. Jobs are completed if the handler returns nil.
+type JobHandler func(j *Job) error
+
+// Queue describes a work queue stored in a stream, whose jobs are processed by
+// competing workers sharing a consumer group.
+//
+// Jobs not completed within VisibilityTimeout, because their handler failed or their
+// worker died, are taken over by another worker, up to MaxAttempts attempts in all.
+// After that they are moved to the dead-letter stream, the queue name + ":dead".
+type Queue struct {
+	Codec             Codec         // Payload codec, nil means JSONCodec
+	VisibilityTimeout time.Duration // 0 means 30 seconds
+	MaxAttempts       int64         // 0 means 3
+	c                 *Client
+	name              string
+}
+
+var NoPayloadError error = errors.New("job has no payload")
+
+// NewQueue returns the queue with the given name, using the given client for enqueuing.
+func NewQueue(c *Client, name string) *Queue {
+	return &Queue{c: c, name: name}
+}
+
+// Enqueue adds a job with the given payload, encoded with the codec of the queue,
+// and returns its ID.
+func (q *Queue) Enqueue(payload interface{}) (string, error) {
+	data, err := q.codec().Marshal(payload)
+	if err != nil {
+		return "", err
+	}
+	return q.c.XAdd(q.name, map[string]interface{}{"payload": data}, XAddOptions{})
+}
+
+// Worker returns a worker taking jobs from the queue with the given client, which
+// must not be shared with other workers, as the given consumer of the "workers" group.
+func (q *Queue) Worker(c *Client, consumer string) (*Worker, error) {
+	g, err := NewConsumerGroup(c, q.name, "workers", consumer)
+	if err != nil {
+		return nil, err
+	}
+	g.ClaimIdle = q.VisibilityTimeout
+	if g.ClaimIdle <= 0 {
+		g.ClaimIdle = 30 * time.Second
+	}
+	g.MaxDeliveries = q.MaxAttempts
+	if g.MaxDeliveries <= 0 {
+		g.MaxDeliveries = 3
+	}
+	return &Worker{g: g, codec: q.codec()}, nil
+}
+
+func (q *Queue) codec() Codec {
+	if q.Codec == nil {
+		return JSONCodec{}
+	}
+	return q.Codec
+}
+
+// Worker processes the jobs of a Queue.
+// Like Client, Worker is not safe for concurrent use.
+type Worker struct {
+	g     *ConsumerGroup
+	codec Codec
+}
+
+// Poll takes available jobs, passes them to the given handler and completes the jobs
+// that were processed. It returns the number of jobs taken.
+func (w *Worker) Poll(h JobHandler) (int, error) {
+	return w.g.Poll(w.handler(h))
+}
+
+// Run processes jobs until the given context is done or taking jobs fails.
+func (w *Worker) Run(ctx context.Context, h JobHandler) error {
+	return w.g.Run(ctx, w.handler(h))
+}
+
+// handler returns the stream handler passing entries as jobs to h.
+func (w *Worker) handler(h JobHandler) StreamHandler {
+	return func(_ string, e StreamEntry) error {
+		data, ok := e.Fields["payload"]
+		if !ok {
+			return NoPayloadError
+		}
+		return h(&Job{ID: e.ID, data: []byte(data), codec: w.codec})
+	}
+}
@@ -0,0 +1,49 @@
+package redis
+
+import (
+	. "launchpad.net/gocheck"
+	"strings"
+	"time"
+)
+
+type QueueSuite struct{}
+
+var _ = Suite(&QueueSuite{})
+
+type email struct {
+	To string
+}
+
+func (s *QueueSuite) TestEnqueue(c *C) {
+	cl, f := fakeClient("$3\r\n1-0\r\n")
+	q := NewQueue(cl, "mail")
+	id, err := q.Enqueue(email{"a@b"})
+	c.Assert(err, IsNil)
+	c.Check(id, Equals, "1-0")
+	c.Check(f.out.String(), Equals, "*5\r\n$4\r\nxadd\r\n$4\r\nmail\r\n$1\r\n*\r\n"+
+		"$7\r\npayload\r\n$12\r\n{\"To\":\"a@b\"}\r\n")
+}
+
+func (s *QueueSuite) TestWorker(c *C) {
+	cl, f := fakeClient("+OK\r\n" +
+		"*3\r\n$3\r\n0-0\r\n*0\r\n*0\r\n" +
+		"*1\r\n*2\r\n$4\r\nmail\r\n*1\r\n" +
+		"*2\r\n$3\r\n1-0\r\n*2\r\n$7\r\npayload\r\n$12\r\n{\"To\":\"a@b\"}\r\n" +
+		":1\r\n")
+	q := NewQueue(cl, "mail")
+	q.VisibilityTimeout = time.Minute
+	w, err := q.Worker(cl, "w1")
+	c.Assert(err, IsNil)
+
+	var got email
+	n, err := w.Poll(func(j *Job) error {
+		c.Check(j.ID, Equals, "1-0")
+		return j.Decode(&got)
+	})
+	c.Assert(err, IsNil)
+	c.Check(n, Equals, 1)
+	c.Check(got, Equals, email{"a@b"})
+	c.Check(strings.Contains(f.out.String(), "$5\r\n60000\r\n"), Equals, true)
+	c.Check(strings.HasSuffix(f.out.String(), "$4\r\nxack\r\n$4\r\nmail\r\n$7\r\nworkers\r\n"+
+		"$3\r\n1-0\r\n"), Equals, true)
+}
@@ -0,0 +1,131 @@
+package redis
+
+import (
+	"errors"
+	"net"
+	"strconv"
+)
+
+//* Replication
+
+// Role is the replication role of a server, as returned by ROLE.
+type Role struct {
+	Role string // "master", "slave" or "sentinel"
+
+	// Masters
+	ReplOffset int64         // Also set on replicas, as processed from the master
+	Replicas   []ReplicaInfo // State and Lag are not reported by ROLE
+
+	// Replicas
+	MasterAddr string
+	LinkState  string // "connect", "connecting", "sync" or "connected"
+
+	// Sentinels
+	MasterNames []string // Monitored masters
+}
+
+// IsMaster returns true if the server is a master.
+func (r *Role) IsMaster() bool {
+	return r.Role == "master"
+}
+
+// Role returns the replication role of the server.
+func (c *Client) Role() (*Role, error) {
+	r := c.Cmd("role")
+	if r.Type == ErrorReply {
+		return nil, r.Err
+	}
+	if r.Type != MultiReply || len(r.Elems) == 0 {
+		return nil, ParseError
+	}
+	name, err := r.Elems[0].Str()
+	if err != nil {
+		return nil, err
+	}
+	role := &Role{Role: name}
+	switch name {
+	case "master":
+		if len(r.Elems) != 3 {
+			return nil, ParseError
+		}
+		if role.ReplOffset, err = r.Elems[1].Int64(); err != nil {
+			return nil, err
+		}
+		for _, e := range r.Elems[2].Elems {
+			l, err := e.List()
+			if err != nil {
+				return nil, err
+			}
+			if len(l) != 3 {
+				return nil, ParseError
+			}
+			offset, err := strconv.ParseInt(l[2], 10, 64)
+			if err != nil {
+				return nil, ParseError
+			}
+			role.Replicas = append(role.Replicas, ReplicaInfo{
+				Addr:      net.JoinHostPort(l[0], l[1]),
+				Offset:    offset,
+				OffsetLag: role.ReplOffset - offset,
+			})
+		}
+	case "slave":
+		if len(r.Elems) != 5 {
+			return nil, ParseError
+		}
+		host, err := r.Elems[1].Str()
+		if err != nil {
+			return nil, err
+		}
+		port, err := r.Elems[2].Int64()
+		if err != nil {
+			return nil, err
+		}
+		role.MasterAddr = net.JoinHostPort(host, strconv.FormatInt(port, 10))
+		if role.LinkState, err = r.Elems[3].Str(); err != nil {
+			return nil, err
+		}
+		if role.ReplOffset, err = r.Elems[4].Int64(); err != nil {
+			return nil, err
+		}
+	case "sentinel":
+		if len(r.Elems) != 2 {
+			return nil, ParseError
+		}
+		if role.MasterNames, err = r.Elems[1].List(); err != nil {
+			return nil, err
+		}
+	}
+	return role, nil
+}
+
+// ReplicaOf makes the server a replica of the master at the given address, or a
+// master if the address is empty (REPLICAOF NO ONE). It requires Redis 5.0 or later,
+// see SlaveOf for older servers.
+func (c *Client) ReplicaOf(addr string) error {
+	args, err := replicaOfArgs(addr)
+	if err != nil {
+		return err
+	}
+	return c.cmdSince(Version{5, 0, 0}, "replicaof", args...).Err
+}
+
+// SlaveOf is like ReplicaOf, using the SLAVEOF command of servers older than 5.0.
+func (c *Client) SlaveOf(addr string) error {
+	args, err := replicaOfArgs(addr)
+	if err != nil {
+		return err
+	}
+	return c.Cmd("slaveof", args...).Err
+}
+
+func replicaOfArgs(addr string) ([]interface{}, error) {
+	if addr == "" {
+		return []interface{}{"no", "one"}, nil
+	}
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return nil, errors.New("invalid master address: " + err.Error())
+	}
+	return []interface{}{host, port}, nil
+}
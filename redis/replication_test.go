@@ -0,0 +1,51 @@
+package redis
+
+import (
+	. "launchpad.net/gocheck"
+)
+
+type ReplicationSuite struct{}
+
+var _ = Suite(&ReplicationSuite{})
+
+func (s *ReplicationSuite) TestRole(c *C) {
+	cl, f := fakeClient("*3\r\n$6\r\nmaster\r\n:3129659\r\n*2\r\n" +
+		"*3\r\n$9\r\n127.0.0.1\r\n$4\r\n9001\r\n$7\r\n3129242\r\n" +
+		"*3\r\n$3\r\n::1\r\n$4\r\n9002\r\n$7\r\n3129543\r\n" +
+		"*5\r\n$5\r\nslave\r\n$9\r\n127.0.0.1\r\n:9000\r\n$9\r\nconnected\r\n:3167038\r\n" +
+		"*2\r\n$8\r\nsentinel\r\n*1\r\n$8\r\nmymaster\r\n" +
+		"*2\r\n$6\r\nmaster\r\n:1\r\n")
+	role, err := cl.Role()
+	c.Assert(err, IsNil)
+	c.Check(f.out.String(), Equals, "*1\r\n$4\r\nrole\r\n")
+	c.Check(role.IsMaster(), Equals, true)
+	c.Check(role, DeepEquals, &Role{Role: "master", ReplOffset: 3129659, Replicas: []ReplicaInfo{
+		{Addr: "127.0.0.1:9001", Offset: 3129242, OffsetLag: 417},
+		{Addr: "[::1]:9002", Offset: 3129543, OffsetLag: 116},
+	}})
+
+	role, err = cl.Role()
+	c.Assert(err, IsNil)
+	c.Check(role.IsMaster(), Equals, false)
+	c.Check(role, DeepEquals, &Role{Role: "slave", ReplOffset: 3167038,
+		MasterAddr: "127.0.0.1:9000", LinkState: "connected"})
+
+	role, err = cl.Role()
+	c.Assert(err, IsNil)
+	c.Check(role, DeepEquals, &Role{Role: "sentinel", MasterNames: []string{"mymaster"}})
+
+	_, err = cl.Role()
+	c.Check(err, Equals, ParseError)
+}
+
+func (s *ReplicationSuite) TestReplicaOf(c *C) {
+	cl, f := fakeClient("+OK\r\n+OK\r\n+OK\r\n")
+	c.Check(cl.ReplicaOf("10.0.0.1:6379"), IsNil)
+	c.Check(cl.ReplicaOf(""), IsNil)
+	c.Check(cl.SlaveOf("[::1]:6380"), IsNil)
+	c.Check(f.out.String(), Equals,
+		"*3\r\n$9\r\nreplicaof\r\n$8\r\n10.0.0.1\r\n$4\r\n6379\r\n"+
+			"*3\r\n$9\r\nreplicaof\r\n$2\r\nno\r\n$3\r\none\r\n"+
+			"*3\r\n$7\r\nslaveof\r\n$3\r\n::1\r\n$4\r\n6380\r\n")
+	c.Check(cl.ReplicaOf("nohost"), ErrorMatches, "invalid master address: .*")
+}
@@ -219,6 +219,59 @@ func (r *Reply) Hash() (map[string]string, error) {
 	return rmap, nil
 }
 
+// intMap returns a multi bulk reply of "key integer key integer..." pairs
+// as a map[string]int64 or an error.
+func (r *Reply) intMap() (map[string]int64, error) {
+	if r.Type == ErrorReply {
+		return nil, r.Err
+	}
+	if r.Type != MultiReply {
+		return nil, errors.New("reply type is not MultiReply")
+	}
+	if len(r.Elems)%2 != 0 {
+		return nil, errors.New("reply has odd number of elements")
+	}
+
+	rmap := make(map[string]int64, len(r.Elems)/2)
+	for i := 0; i < len(r.Elems); i += 2 {
+		key, err := r.Elems[i].Str()
+		if err != nil {
+			return nil, errors.New("key element has no string reply")
+		}
+		n, err := r.Elems[i+1].Int64()
+		if err != nil {
+			return nil, err
+		}
+		rmap[key] = n
+	}
+
+	return rmap, nil
+}
+
+// pairs returns a multi bulk reply of "key value key value..." pairs
+// as a map of keys to value replies or an error.
+func (r *Reply) pairs() (map[string]*Reply, error) {
+	if r.Type == ErrorReply {
+		return nil, r.Err
+	}
+	if r.Type != MultiReply {
+		return nil, errors.New("reply type is not MultiReply")
+	}
+	if len(r.Elems)%2 != 0 {
+		return nil, errors.New("reply has odd number of elements")
+	}
+
+	m := make(map[string]*Reply, len(r.Elems)/2)
+	for i := 0; i < len(r.Elems); i += 2 {
+		key, err := r.Elems[i].Str()
+		if err != nil {
+			return nil, errors.New("key element has no string reply")
+		}
+		m[key] = r.Elems[i+1]
+	}
+	return m, nil
+}
+
 // String returns a string representation of the reply and its sub-replies.
 // This method is for debugging.
 // Use method Reply.Str() for reading string reply.
@@ -0,0 +1,39 @@
+package redis
+
+import (
+	"strings"
+	"time"
+)
+
+//* Retry
+
+// RetryPolicy describes how commands failing with transient topology errors
+// (READONLY and CLUSTERDOWN) are retried by ClusterClient and SentinelClient.
+type RetryPolicy struct {
+	MaxRetries int           // Retries after the first attempt, 0 disables retrying
+	Backoff    time.Duration // Delay before the first retry, doubled for each further retry
+}
+
+// DefaultRetryPolicy is the retry policy of newly dialed clients.
+var DefaultRetryPolicy = RetryPolicy{MaxRetries: 3, Backoff: 100 * time.Millisecond}
+
+// wait sleeps before the retry following the given attempt, counted from 0,
+// and returns false if no more retries are allowed.
+func (p RetryPolicy) wait(attempt int) bool {
+	if attempt >= p.MaxRetries {
+		return false
+	}
+	time.Sleep(p.Backoff << uint(attempt))
+	return true
+}
+
+// isTopologyError returns true if the given reply is an error caused by
+// a topology change: a write sent to a replica (READONLY) or a cluster
+// that is temporarily unable to serve (CLUSTERDOWN).
+func isTopologyError(r *Reply) bool {
+	if r.Type != ErrorReply || r.Err == nil {
+		return false
+	}
+	s := r.Err.Error()
+	return strings.HasPrefix(s, "READONLY ") || strings.HasPrefix(s, "CLUSTERDOWN ")
+}
@@ -0,0 +1,68 @@
+package redis
+
+import (
+	"errors"
+	. "launchpad.net/gocheck"
+	"time"
+)
+
+type RetrySuite struct{}
+
+var _ = Suite(&RetrySuite{})
+
+func (s *RetrySuite) TestIsTopologyError(c *C) {
+	c.Check(isTopologyError(&Reply{Type: ErrorReply,
+		Err: errors.New("READONLY You can't write against a read only replica.")}), Equals, true)
+	c.Check(isTopologyError(&Reply{Type: ErrorReply,
+		Err: errors.New("CLUSTERDOWN The cluster is down")}), Equals, true)
+	c.Check(isTopologyError(&Reply{Type: ErrorReply, Err: errors.New("ERR foo")}), Equals, false)
+	c.Check(isTopologyError(&Reply{Type: StatusReply}), Equals, false)
+}
+
+func (s *RetrySuite) TestWait(c *C) {
+	p := RetryPolicy{MaxRetries: 2, Backoff: time.Millisecond}
+	c.Check(p.wait(0), Equals, true)
+	c.Check(p.wait(1), Equals, true)
+	c.Check(p.wait(2), Equals, false)
+	c.Check(RetryPolicy{}.wait(0), Equals, false)
+}
+
+func (s *RetrySuite) TestClusterRetry(c *C) {
+	slots := "*1\r\n*3\r\n:0\r\n:16383\r\n*2\r\n$9\r\n127.0.0.1\r\n:7000\r\n"
+	cc, _ := fakeCluster("-CLUSTERDOWN The cluster is down\r\n" + slots + "$3\r\nbar\r\n")
+	cc.Retry = RetryPolicy{MaxRetries: 1, Backoff: time.Millisecond}
+	v, err := cc.Cmd("get", "foo").Str()
+	c.Assert(err, IsNil)
+	c.Check(v, Equals, "bar")
+
+	cc, _ = fakeCluster("-READONLY You can't write against a read only replica.\r\n" + slots +
+		"-READONLY You can't write against a read only replica.\r\n")
+	cc.Retry = RetryPolicy{MaxRetries: 1, Backoff: time.Millisecond}
+	r := cc.Cmd("set", "foo", "bar")
+	c.Check(isTopologyError(r), Equals, true)
+}
+
+func (s *RetrySuite) TestSentinelRetry(c *C) {
+	role := "*3\r\n$6\r\nmaster\r\n:0\r\n*0\r\n"
+	demoted := fakeServer(c, role+"-READONLY You can't write against a read only replica.\r\n")
+	master := fakeServer(c, role+"+OK\r\n")
+	sentinel := fakeServer(c, masterReply(demoted), "", masterReply(master))
+
+	sc, err := DialSentinel("tcp", []string{sentinel}, "mymaster")
+	c.Assert(err, IsNil)
+	defer sc.Close()
+	sc.Retry = RetryPolicy{MaxRetries: 1, Backoff: time.Millisecond}
+	// let the watcher take its sentinel connection first
+	for i := 0; i < 100; i++ {
+		sc.mu.Lock()
+		w := sc.watcher
+		sc.mu.Unlock()
+		if w != nil {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	r := sc.Cmd("set", "foo", "bar")
+	c.Check(r.Type, Equals, StatusReply)
+	c.Check(sc.Addr(), Equals, master)
+}
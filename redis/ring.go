@@ -0,0 +1,296 @@
+package redis
+
+import (
+	"errors"
+	"hash/crc32"
+	"log/slog"
+	"sort"
+	"strconv"
+	"time"
+)
+
+//* Ring
+
+const defaultVirtualNodes = 160
+
+var NoKeyError error = errors.New("command has no key to route by")
+var NoServerError error = errors.New("ring has no servers")
+
+// RingHash hashes a key or a virtual node name onto the ring.
+type RingHash func(b []byte) uint32
+
+type ringPoint struct {
+	hash uint32
+	addr string
+}
+
+// RingClient describes a client that shards keys across standalone Redis servers
+// with consistent hashing.
+// Each server is placed on the ring as a number of virtual nodes, and a key belongs to
+// the server owning the first virtual node at or after the key's hash.
+// Hash tags are honored, so keys sharing a {tag} are stored on the same server.
+// Like Client, RingClient is not safe for concurrent use.
+type RingClient struct {
+	// OnRebalance, if set, is called after servers are added to or removed from the ring,
+	// so that keys whose owner changed can be migrated.
+	OnRebalance func(added, removed []string)
+	Quarantine  QuarantinePolicy
+	network     string
+	timeout     time.Duration
+	vnodes      int
+	hash        RingHash
+	addrs       []string
+	points      []ringPoint
+	clients     map[string]*Client
+	health      quarantine
+	stats       nodeStats
+}
+
+// DialRingTimeout creates a ring of the given servers with the given timeout.
+// Connections to the servers are established as they are needed.
+func DialRingTimeout(network string, addrs []string, timeout time.Duration) (*RingClient, error) {
+	if len(addrs) == 0 {
+		return nil, NoServerError
+	}
+	rc := &RingClient{
+		Quarantine: DefaultQuarantinePolicy,
+		network:    network,
+		timeout:    timeout,
+		vnodes:     defaultVirtualNodes,
+		hash:       crc32.ChecksumIEEE,
+		addrs:      append([]string(nil), addrs...),
+		clients:    map[string]*Client{},
+		health:     quarantine{},
+		stats:      nodeStats{},
+	}
+	rc.build()
+	return rc, nil
+}
+
+// DialRing creates a ring of the given servers.
+func DialRing(network string, addrs []string) (*RingClient, error) {
+	return DialRingTimeout(network, addrs, time.Duration(0))
+}
+
+//* Public methods
+
+// Close closes the connections to all servers.
+func (rc *RingClient) Close() error {
+	var err error
+	for addr, c := range rc.clients {
+		if e := c.Close(); e != nil {
+			err = e
+		}
+		delete(rc.clients, addr)
+	}
+	return err
+}
+
+// SetVirtualNodes sets the number of virtual nodes per server and rebuilds the ring.
+func (rc *RingClient) SetVirtualNodes(n int) {
+	if n < 1 {
+		n = 1
+	}
+	rc.vnodes = n
+	rc.build()
+}
+
+// SetHash sets the hash function of the ring and rebuilds the ring.
+func (rc *RingClient) SetHash(h RingHash) {
+	rc.hash = h
+	rc.build()
+}
+
+// Servers returns the addresses of the servers in the ring.
+func (rc *RingClient) Servers() []string {
+	return append([]string(nil), rc.addrs...)
+}
+
+// AddServer adds the given server to the ring.
+func (rc *RingClient) AddServer(addr string) {
+	for _, a := range rc.addrs {
+		if a == addr {
+			return
+		}
+	}
+	rc.addrs = append(rc.addrs, addr)
+	rc.build()
+	if rc.OnRebalance != nil {
+		rc.OnRebalance([]string{addr}, nil)
+	}
+}
+
+// RemoveServer removes the given server from the ring and closes the connection to it.
+func (rc *RingClient) RemoveServer(addr string) {
+	for i, a := range rc.addrs {
+		if a == addr {
+			rc.addrs = append(rc.addrs[:i], rc.addrs[i+1:]...)
+			if c, ok := rc.clients[addr]; ok {
+				c.Close()
+				delete(rc.clients, addr)
+			}
+			delete(rc.stats, addr)
+			rc.build()
+			if rc.OnRebalance != nil {
+				rc.OnRebalance(nil, []string{addr})
+			}
+			return
+		}
+	}
+}
+
+// Locate returns the address of the server that owns the given key.
+func (rc *RingClient) Locate(key string) (string, error) {
+	return rc.locate([]byte(key))
+}
+
+// Cmd calls the given Redis command on the server owning the command's key.
+// Commands without a key return an error reply with NoKeyError.
+// Commands to a quarantined server return an error reply with QuarantinedError,
+// see QuarantinePolicy.
+// MGET, MSET, DEL, UNLINK, EXISTS and TOUCH with keys on several servers are split
+// by server and executed in parallel, with the replies merged in key order; note that
+// a split MSET is not atomic. Other multi-key commands whose keys span several servers
+// return an error reply with a *CrossSlotError.
+func (rc *RingClient) Cmd(cmd string, args ...interface{}) *Reply {
+	if err := checkKeys(cmd, args, rc.server); err != nil {
+		if reqs, merge, ok := splitKeys(cmd, args, rc.server); ok {
+			return merge(rc.parallel(reqs))
+		}
+		return &Reply{Type: ErrorReply, Err: err}
+	}
+	key, ok := commandKey(cmd, args)
+	if !ok {
+		return &Reply{Type: ErrorReply, Err: NoKeyError}
+	}
+	addr, err := rc.locate(key)
+	if err != nil {
+		return &Reply{Type: ErrorReply, Err: err}
+	}
+	c, err := rc.conn(addr)
+	if err != nil {
+		return &Reply{Type: ErrorReply, Err: err}
+	}
+	start := time.Now()
+	r := c.Cmd(cmd, args...)
+	rc.observe(addr, c, r, time.Since(start))
+	return r
+}
+
+//* Private methods
+
+// build places the virtual nodes of all servers on the ring.
+func (rc *RingClient) build() {
+	points := make([]ringPoint, 0, len(rc.addrs)*rc.vnodes)
+	for _, addr := range rc.addrs {
+		for i := 0; i < rc.vnodes; i++ {
+			points = append(points, ringPoint{rc.hash([]byte(addr + "-" + strconv.Itoa(i))), addr})
+		}
+	}
+	sort.Slice(points, func(i, j int) bool {
+		if points[i].hash == points[j].hash {
+			return points[i].addr < points[j].addr
+		}
+		return points[i].hash < points[j].hash
+	})
+	rc.points = points
+}
+
+func (rc *RingClient) locate(key []byte) (string, error) {
+	if len(rc.points) == 0 {
+		return "", NoServerError
+	}
+	h := rc.hash(hashTag(key))
+	i := sort.Search(len(rc.points), func(i int) bool { return rc.points[i].hash >= h })
+	if i == len(rc.points) {
+		i = 0
+	}
+	return rc.points[i].addr, nil
+}
+
+// server returns the server owning the given key, or an empty string if there is none.
+func (rc *RingClient) server(key []byte) string {
+	addr, _ := rc.locate(key)
+	return addr
+}
+
+// conn returns the Client for the given server unless it is quarantined.
+func (rc *RingClient) conn(addr string) (*Client, error) {
+	if rc.health.blocked(addr) {
+		return nil, QuarantinedError
+	}
+	c, err := rc.client(addr)
+	if err != nil {
+		rc.health.failed(rc.Quarantine, addr)
+		return nil, err
+	}
+	return c, nil
+}
+
+// observe records the health and statistics of the given server from the given reply,
+// which took d to arrive.
+func (rc *RingClient) observe(addr string, c *Client, r *Reply, d time.Duration) {
+	rc.stats.record(addr, r, d)
+	if r.Type == ErrorReply && isConnError(r.Err) {
+		logEvent(slog.LevelWarn, "redis: dropped connection to server", "addr", addr, "error",
+			r.Err)
+		rc.health.failed(rc.Quarantine, addr)
+		c.Close()
+		if rc.clients[addr] == c {
+			delete(rc.clients, addr)
+		}
+	} else {
+		rc.health.succeeded(addr)
+	}
+}
+
+// parallel executes the given single-server requests with one pipeline per server
+// in parallel and returns the replies in the order of the requests.
+func (rc *RingClient) parallel(reqs []*request) []*Reply {
+	replies := make([]*Reply, len(reqs))
+	batches := map[string][]int{}
+	for i, req := range reqs {
+		key, _ := commandKey(req.cmd, req.args)
+		addr, err := rc.locate(key)
+		if err != nil {
+			replies[i] = &Reply{Type: ErrorReply, Err: err}
+			continue
+		}
+		batches[addr] = append(batches[addr], i)
+	}
+
+	clients := map[string]*Client{}
+	for addr, idx := range batches {
+		c, err := rc.conn(addr)
+		if err != nil {
+			for _, i := range idx {
+				replies[i] = &Reply{Type: ErrorReply, Err: err}
+			}
+			delete(batches, addr)
+			continue
+		}
+		clients[addr] = c
+	}
+
+	elapsed := runBatches(reqs, replies, batches, clients)
+	for addr, idx := range batches {
+		for _, i := range idx {
+			rc.observe(addr, clients[addr], replies[i], elapsed[i])
+		}
+	}
+	return replies
+}
+
+// client returns the Client for the given server, connecting to it if needed.
+func (rc *RingClient) client(addr string) (*Client, error) {
+	if c, ok := rc.clients[addr]; ok {
+		return c, nil
+	}
+	c, err := DialTimeout(rc.network, addr, rc.timeout)
+	rc.stats.dialed(addr, err)
+	if err != nil {
+		return nil, err
+	}
+	rc.clients[addr] = c
+	return c, nil
+}
@@ -0,0 +1,81 @@
+package redis
+
+import (
+	. "launchpad.net/gocheck"
+	"strconv"
+)
+
+type RingSuite struct{}
+
+var _ = Suite(&RingSuite{})
+
+func (s *RingSuite) TestLocate(c *C) {
+	addrs := []string{"a:1", "b:1", "c:1"}
+	rc, err := DialRing("tcp", addrs)
+	c.Assert(err, IsNil)
+
+	owners := map[string]string{}
+	counts := map[string]int{}
+	for i := 0; i < 3000; i++ {
+		k := "key" + strconv.Itoa(i)
+		owners[k], _ = rc.Locate(k)
+		counts[owners[k]]++
+	}
+	for _, addr := range addrs {
+		c.Check(counts[addr] > 500, Equals, true)
+	}
+
+	a, _ := rc.Locate("{user1}.name")
+	b, _ := rc.Locate("{user1}.email")
+	c.Check(a, Equals, b)
+
+	// only the keys of the removed server move
+	var added, removed []string
+	rc.OnRebalance = func(a, r []string) { added, removed = a, r }
+	rc.RemoveServer("b:1")
+	c.Check(removed, DeepEquals, []string{"b:1"})
+	c.Check(rc.Servers(), DeepEquals, []string{"a:1", "c:1"})
+	for k, owner := range owners {
+		if owner != "b:1" {
+			now, _ := rc.Locate(k)
+			c.Check(now, Equals, owner)
+		}
+	}
+	rc.AddServer("b:1")
+	c.Check(added, DeepEquals, []string{"b:1"})
+	for k, owner := range owners {
+		now, _ := rc.Locate(k)
+		c.Check(now, Equals, owner)
+	}
+}
+
+func (s *RingSuite) TestCmd(c *C) {
+	rc, _ := DialRing("tcp", []string{"a:1", "b:1"})
+	rc.SetVirtualNodes(10)
+	rc.SetHash(func(b []byte) uint32 { return uint32(len(b)) })
+	owner, _ := rc.Locate("foo")
+	cl, f := fakeClient("$3\r\nbar\r\n")
+	rc.clients[owner] = cl
+
+	v, err := rc.Cmd("get", "foo").Str()
+	c.Assert(err, IsNil)
+	c.Check(v, Equals, "bar")
+	c.Check(f.out.String(), Equals, "*2\r\n$3\r\nget\r\n$3\r\nfoo\r\n")
+
+	c.Check(rc.Cmd("ping").Err, Equals, NoKeyError)
+}
+
+func (s *RingSuite) TestCrossSlot(c *C) {
+	rc, _ := DialRing("tcp", []string{"a:1", "b:1"})
+	first, _ := rc.Locate("k0")
+	other := ""
+	for i := 1; other == ""; i++ {
+		k := "k" + strconv.Itoa(i)
+		if addr, _ := rc.Locate(k); addr != first {
+			other = k
+		}
+	}
+	e, ok := rc.Cmd("sunion", "k0", other).Err.(*CrossSlotError)
+	c.Assert(ok, Equals, true)
+	c.Check(e.Keys, DeepEquals, []string{other})
+}
@@ -0,0 +1,197 @@
+package redis
+
+import (
+	"errors"
+	"time"
+)
+
+//* Scan
+
+// ScanIterator iterates over the elements returned by a SCAN family command,
+// fetching further pages as needed.
+//
+//	it := c.Scan("user:*", 100)
+//	for it.Next() {
+//		fmt.Println(it.Val())
+//	}
+//	if err := it.Err(); err != nil {
+//		...
+//	}
+type ScanIterator struct {
+	next func() (page []string, more bool, err error)
+	buf  []string
+	val  string
+	err  error
+	done bool
+}
+
+// Next advances the iterator to the next element and returns false
+// when the iteration is over or an error occurred.
+func (it *ScanIterator) Next() bool {
+	for len(it.buf) == 0 {
+		if it.done || it.err != nil {
+			return false
+		}
+		var more bool
+		it.buf, more, it.err = it.next()
+		it.done = !more
+	}
+	it.val, it.buf = it.buf[0], it.buf[1:]
+	return true
+}
+
+// Val returns the current element.
+func (it *ScanIterator) Val() string {
+	return it.val
+}
+
+// Err returns the error that ended the iteration, if any.
+func (it *ScanIterator) Err() error {
+	return it.err
+}
+
+// scanArgs returns the MATCH and COUNT arguments of a SCAN family command.
+func scanArgs(match string, count int) []interface{} {
+	var args []interface{}
+	if match != "" {
+		args = append(args, "match", match)
+	}
+	if count > 0 {
+		args = append(args, "count", count)
+	}
+	return args
+}
+
+// parseScan parses a SCAN family reply into the next cursor and the returned elements.
+func parseScan(r *Reply) (string, []string, error) {
+	if r.Type == ErrorReply {
+		return "", nil, r.Err
+	}
+	if r.Type != MultiReply || len(r.Elems) != 2 {
+		return "", nil, errors.New("reply is not a scan reply")
+	}
+	cursor, err := r.Elems[0].Str()
+	if err != nil {
+		return "", nil, err
+	}
+	l, err := r.Elems[1].List()
+	if err != nil {
+		return "", nil, err
+	}
+	return cursor, l, nil
+}
+
+// Scan returns an iterator over the keys that match the given pattern, with the given
+// COUNT hint for each page. An empty pattern matches all keys and a zero count uses
+// the server default. As with SCAN, keys added or removed during the iteration may
+// or may not be returned, and keys may be returned more than once.
+func (c *Client) Scan(match string, count int) *ScanIterator {
+	return c.scan("scan", nil, match, count)
+}
+
+// HScan returns an iterator over the fields of the hash at the given key that match
+// the given pattern, see Scan. The iterator returns each field followed by its value.
+func (c *Client) HScan(key, match string, count int) *ScanIterator {
+	return c.scan("hscan", []interface{}{key}, match, count)
+}
+
+// SScan returns an iterator over the members of the set at the given key that match
+// the given pattern, see Scan.
+func (c *Client) SScan(key, match string, count int) *ScanIterator {
+	return c.scan("sscan", []interface{}{key}, match, count)
+}
+
+// ZScan returns an iterator over the members of the sorted set at the given key that
+// match the given pattern, see Scan. The iterator returns each member followed by
+// its score.
+func (c *Client) ZScan(key, match string, count int) *ScanIterator {
+	return c.scan("zscan", []interface{}{key}, match, count)
+}
+
+// scan returns an iterator paging with the given SCAN family command.
+func (c *Client) scan(cmd string, key []interface{}, match string, count int) *ScanIterator {
+	cursor := "0"
+	next := func() ([]string, bool, error) {
+		next, l, err := parseScan(c.Cmd(cmd, key, cursor, scanArgs(match, count)))
+		if err != nil {
+			return nil, false, err
+		}
+		cursor = next
+		return l, cursor != "0", nil
+	}
+	return &ScanIterator{next: next}
+}
+
+// Scan returns an iterator over the keys of all master nodes of the cluster
+// that match the given pattern, with the given COUNT hint for each page.
+// An empty pattern matches all keys and a zero count uses the server default.
+//
+// The nodes are scanned one after another. When a node fails, the slot map is
+// reloaded; masters that joined are scanned as well and departed nodes are skipped.
+// As with SCAN, keys that move between nodes during the iteration may be
+// returned twice or not at all.
+func (cc *ClusterClient) Scan(match string, count int) *ScanIterator {
+	var queue []string
+	seen := map[string]bool{}
+	update := func() {
+		masters := cc.masters()
+		current := map[string]bool{}
+		for _, addr := range masters {
+			current[addr] = true
+			if !seen[addr] {
+				seen[addr] = true
+				queue = append(queue, addr)
+			}
+		}
+		left := queue[:0]
+		for _, addr := range queue {
+			if current[addr] {
+				left = append(left, addr)
+			}
+		}
+		queue = left
+	}
+	update()
+
+	cursor := "0"
+	next := func() ([]string, bool, error) {
+		for len(queue) > 0 {
+			addr := queue[0]
+			c, err := cc.conn(addr)
+			var r *Reply
+			if err == nil {
+				start := time.Now()
+				r = c.Cmd("scan", cursor, scanArgs(match, count))
+				cc.observe(addr, c, r, time.Since(start))
+				if r.Type == ErrorReply {
+					err = r.Err
+				}
+			}
+			if err != nil {
+				if !isConnError(err) {
+					return nil, false, err
+				}
+				cc.Refresh()
+				update()
+				if len(queue) > 0 && queue[0] == addr {
+					return nil, false, err
+				}
+				cursor = "0"
+				continue
+			}
+
+			next, keys, err := parseScan(r)
+			if err != nil {
+				return nil, false, err
+			}
+			cursor = next
+			if cursor == "0" {
+				queue = queue[1:]
+				update()
+			}
+			return keys, len(queue) > 0, nil
+		}
+		return nil, false, nil
+	}
+	return &ScanIterator{next: next}
+}
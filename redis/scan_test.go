@@ -0,0 +1,98 @@
+package redis
+
+import (
+	. "launchpad.net/gocheck"
+)
+
+type ScanSuite struct{}
+
+var _ = Suite(&ScanSuite{})
+
+// twoNodeCluster returns a fake cluster whose second half of slots is served
+// by a second node with the given replies.
+func twoNodeCluster(a, b string) (*ClusterClient, *fakeConn, *fakeConn) {
+	cc, fa := fakeCluster(a)
+	node, fb := fakeClient(b)
+	cc.clients["127.0.0.1:7001"] = node
+	r := &slotRange{numSlots / 2, numSlots - 1, []string{"127.0.0.1:7001"}}
+	for i := numSlots / 2; i < numSlots; i++ {
+		cc.slots[i] = r
+	}
+	return cc, fa, fb
+}
+
+func (s *ScanSuite) TestParseScan(c *C) {
+	r := &Reply{Type: MultiReply, Elems: []*Reply{
+		{Type: BulkReply, buf: []byte("17")},
+		{Type: MultiReply, Elems: []*Reply{{Type: BulkReply, buf: []byte("a")}}},
+	}}
+	cursor, l, err := parseScan(r)
+	c.Assert(err, IsNil)
+	c.Check(cursor, Equals, "17")
+	c.Check(l, DeepEquals, []string{"a"})
+
+	_, _, err = parseScan(&Reply{Type: IntegerReply})
+	c.Check(err, NotNil)
+}
+
+func (s *ScanSuite) TestClusterScan(c *C) {
+	cc, fa, _ := twoNodeCluster(
+		"*2\r\n$1\r\n5\r\n*2\r\n$1\r\na\r\n$1\r\nb\r\n*2\r\n$1\r\n0\r\n*0\r\n",
+		"*2\r\n$1\r\n0\r\n*1\r\n$1\r\nc\r\n")
+	it := cc.Scan("*", 10)
+	var keys []string
+	for it.Next() {
+		keys = append(keys, it.Val())
+	}
+	c.Check(it.Err(), IsNil)
+	c.Check(keys, DeepEquals, []string{"a", "b", "c"})
+	c.Check(fa.out.String(), Equals,
+		"*6\r\n$4\r\nscan\r\n$1\r\n0\r\n$5\r\nmatch\r\n$1\r\n*\r\n$5\r\ncount\r\n$2\r\n10\r\n"+
+			"*6\r\n$4\r\nscan\r\n$1\r\n5\r\n$5\r\nmatch\r\n$1\r\n*\r\n$5\r\ncount\r\n$2\r\n10\r\n")
+}
+
+func (s *ScanSuite) TestClusterScanNodeFailure(c *C) {
+	// the second node fails and the reloaded slot map no longer has it
+	cc, _, _ := twoNodeCluster(
+		"*2\r\n$1\r\n0\r\n*1\r\n$1\r\na\r\n"+
+			"*1\r\n*3\r\n:0\r\n:16383\r\n*2\r\n$9\r\n127.0.0.1\r\n:7000\r\n",
+		"")
+	it := cc.Scan("", 0)
+	var keys []string
+	for it.Next() {
+		keys = append(keys, it.Val())
+	}
+	c.Check(it.Err(), IsNil)
+	c.Check(keys, DeepEquals, []string{"a"})
+}
+
+func (s *ScanSuite) TestClientScan(c *C) {
+	cl, f := fakeClient("*2\r\n$2\r\n17\r\n*2\r\n$1\r\na\r\n$1\r\nb\r\n" +
+		"*2\r\n$1\r\n0\r\n*1\r\n$1\r\nc\r\n")
+	var keys []string
+	it := cl.Scan("*", 2)
+	for it.Next() {
+		keys = append(keys, it.Val())
+	}
+	c.Assert(it.Err(), IsNil)
+	c.Check(keys, DeepEquals, []string{"a", "b", "c"})
+	c.Check(f.out.String(), Equals, "*6\r\n$4\r\nscan\r\n$1\r\n0\r\n$5\r\nmatch\r\n$1\r\n*\r\n"+
+		"$5\r\ncount\r\n$1\r\n2\r\n"+
+		"*6\r\n$4\r\nscan\r\n$2\r\n17\r\n$5\r\nmatch\r\n$1\r\n*\r\n$5\r\ncount\r\n$1\r\n2\r\n")
+}
+
+func (s *ScanSuite) TestHScan(c *C) {
+	cl, f := fakeClient("*2\r\n$1\r\n0\r\n*2\r\n$1\r\nf\r\n$1\r\nv\r\n-ERR wrong type\r\n")
+	var l []string
+	it := cl.HScan("h", "", 0)
+	for it.Next() {
+		l = append(l, it.Val())
+	}
+	c.Assert(it.Err(), IsNil)
+	c.Check(l, DeepEquals, []string{"f", "v"})
+	c.Check(f.out.String(), Equals, "*3\r\n$5\r\nhscan\r\n$1\r\nh\r\n$1\r\n0\r\n")
+
+	it = cl.ZScan("h", "", 0)
+	c.Check(it.Next(), Equals, false)
+	c.Check(it.Err(), ErrorMatches, "ERR wrong type")
+}
@@ -0,0 +1,217 @@
+package redis
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"time"
+)
+
+//* Scripting
+
+var UnknownScriptError error = errors.New("script is not registered")
+
+// ScriptBusyError is the error of commands that fail because the server is busy running
+// a script (BUSY).
+type ScriptBusyError struct {
+	Msg     string        // Error message of the server
+	Elapsed time.Duration // Time from sending the command to receiving the error
+	Killed  bool          // Whether the script was stopped with SCRIPT KILL
+}
+
+func (e *ScriptBusyError) Error() string {
+	return e.Msg + " (after " + e.Elapsed.String() + ")"
+}
+
+// ScriptKill stops the script that the server is running, unless it has written data.
+func (c *Client) ScriptKill() error {
+	return c.Cmd("script", "kill").Err
+}
+
+// Cmder is implemented by the clients of this package.
+type Cmder interface {
+	Cmd(cmd string, args ...interface{}) *Reply
+}
+
+// Pipeline is implemented by the clients of this package that support pipelining,
+// Client and ClusterClient.
+type Pipeline interface {
+	Append(cmd string, args ...interface{})
+	GetReply() *Reply
+	appendRequest(req *request)
+}
+
+// Script describes a Lua script.
+// Scripts are called with EVALSHA, so the source is only sent to servers
+// that do not have the script in their script cache yet.
+type Script struct {
+	// OnRun, if set, is called after each Run of the script with the time the call took
+	// and its reply, e.g. to track the latency of heavy scripts.
+	OnRun func(elapsed time.Duration, r *Reply)
+	src   string
+	sha   string
+}
+
+// NewScript returns a Script with the given Lua source.
+func NewScript(src string) *Script {
+	h := sha1.Sum([]byte(src))
+	return &Script{src: src, sha: hex.EncodeToString(h[:])}
+}
+
+// Src returns the Lua source of the script.
+func (s *Script) Src() string {
+	return s.src
+}
+
+// SHA returns the SHA1 digest of the script, as used by EVALSHA.
+func (s *Script) SHA() string {
+	return s.sha
+}
+
+// Run calls the script on the given client with the given keys and arguments
+// using EVALSHA. If the server does not know the script, Run calls it with EVAL,
+// which also adds it to the script cache of the server.
+func (s *Script) Run(c Cmder, keys []string, args ...interface{}) *Reply {
+	return s.run(c, "evalsha", "eval", keys, args)
+}
+
+// RunRO is like Run for read-only scripts, using EVALSHA_RO and EVAL_RO
+// (Redis 7.0 or later). ClusterClient sends these to replicas according to its ReadPolicy.
+func (s *Script) RunRO(c Cmder, keys []string, args ...interface{}) *Reply {
+	return s.run(c, "evalsha_ro", "eval_ro", keys, args)
+}
+
+// Append adds a call of the script with the given keys and arguments to the pipeline
+// queue of the given client. The script is called with EVALSHA; if the server does not
+// know the script, GetReply calls it with EVAL when returning its reply, i.e. after
+// the rest of the pipeline has been executed.
+// Inside a MULTI transaction there is no fallback, as EXEC reports the failure;
+// call Load before the transaction instead.
+func (s *Script) Append(p Pipeline, keys []string, args ...interface{}) {
+	s.append(p, "evalsha", "eval", keys, args)
+}
+
+// AppendRO is like Append for read-only scripts, using EVALSHA_RO and EVAL_RO.
+func (s *Script) AppendRO(p Pipeline, keys []string, args ...interface{}) {
+	s.append(p, "evalsha_ro", "eval_ro", keys, args)
+}
+
+func (s *Script) run(c Cmder, cmd, fallback string, keys []string, args []interface{}) *Reply {
+	start := time.Now()
+	a := scriptArgs(s.sha, keys, args)
+	r := c.Cmd(cmd, a...)
+	if isNoScript(r) {
+		a[0] = s.src
+		r = c.Cmd(fallback, a...)
+	}
+	if s.OnRun != nil {
+		s.OnRun(time.Since(start), r)
+	}
+	return r
+}
+
+func (s *Script) append(p Pipeline, cmd, fallback string, keys []string, args []interface{}) {
+	a := scriptArgs(s.sha, keys, args)
+	fa := append([]interface{}{s.src}, a[1:]...)
+	p.appendRequest(&request{cmd: cmd, args: a, fallback: &request{cmd: fallback, args: fa}})
+}
+
+// scriptArgs returns the arguments of an EVAL family command.
+func scriptArgs(script string, keys []string, args []interface{}) []interface{} {
+	a := make([]interface{}, 0, 2+len(keys)+len(args))
+	a = append(a, script, len(keys))
+	for _, k := range keys {
+		a = append(a, k)
+	}
+	return append(a, args...)
+}
+
+// Load loads the script on the server of the given client with SCRIPT LOAD.
+func (s *Script) Load(c Cmder) error {
+	return c.Cmd("script", "load", s.src).Err
+}
+
+// busyError replaces the error of the given reply with a *ScriptBusyError if it is
+// a BUSY error and returns the new error.
+// The command of the reply was sent at the given time.
+func busyError(r *Reply, sent time.Time) *ScriptBusyError {
+	if r.Type != ErrorReply || r.Err == nil || !strings.HasPrefix(r.Err.Error(), "BUSY ") {
+		return nil
+	}
+	e := &ScriptBusyError{Msg: r.Err.Error(), Elapsed: time.Since(sent)}
+	r.Err = e
+	return e
+}
+
+// killBusyScript sends SCRIPT KILL if the client kills busy scripts, records the outcome
+// in the given error and returns true if the script was stopped.
+func (c *Client) killBusyScript(e *ScriptBusyError) bool {
+	if c.KillBusyScripts {
+		e.Killed = c.ScriptKill() == nil
+	}
+	return e.Killed
+}
+
+// isNoScript returns true if the given reply is a NOSCRIPT error.
+func isNoScript(r *Reply) bool {
+	return r.Type == ErrorReply && r.Err != nil && strings.HasPrefix(r.Err.Error(), "NOSCRIPT ")
+}
+
+//* Script registry
+
+// RegisterScript loads the given script on the server with SCRIPT LOAD and registers it
+// under the given name, replacing any script registered under that name.
+// Registered scripts are loaded again whenever the client connects to a server,
+// see UpdateConfiguration.
+func (c *Client) RegisterScript(name string, s *Script) error {
+	if r := c.Cmd("script", "load", s.src); r.Type == ErrorReply {
+		return r.Err
+	}
+	if c.scripts == nil {
+		c.scripts = map[string]*Script{}
+	}
+	c.scripts[name] = s
+	return nil
+}
+
+// RunScript calls the script registered under the given name with EVALSHA.
+// If the server does not know the script, e.g. after a restart, all registered scripts
+// are loaded again before the call is repeated.
+// Calls to unknown scripts return an error reply with UnknownScriptError.
+func (c *Client) RunScript(name string, keys []string, args ...interface{}) *Reply {
+	s, ok := c.scripts[name]
+	if !ok {
+		return &Reply{Type: ErrorReply, Err: UnknownScriptError}
+	}
+	start := time.Now()
+	a := scriptArgs(s.sha, keys, args)
+	r := c.Cmd("evalsha", a...)
+	if isNoScript(r) {
+		if err := c.loadScripts(); err != nil {
+			r = &Reply{Type: ErrorReply, Err: err}
+		} else {
+			r = c.Cmd("evalsha", a...)
+		}
+	}
+	c.scriptDone(name, start, r)
+	return r
+}
+
+// loadScripts loads the registered scripts on the server, with one pipeline that
+// leaves the pipeline queue of the client alone.
+func (c *Client) loadScripts() error {
+	if len(c.scripts) == 0 {
+		return nil
+	}
+	var reqs []*request
+	for _, s := range c.scripts {
+		reqs = append(reqs, &request{cmd: "script", args: []interface{}{"load", s.src}})
+	}
+	for _, r := range c.flush(reqs) {
+		if r.Type == ErrorReply {
+			return r.Err
+		}
+	}
+	return nil
+}
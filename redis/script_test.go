@@ -0,0 +1,138 @@
+package redis
+
+import (
+	. "launchpad.net/gocheck"
+	"strings"
+)
+
+type ScriptSuite struct{}
+
+var _ = Suite(&ScriptSuite{})
+
+func (s *ScriptSuite) TestSHA(c *C) {
+	sc := NewScript("return 1")
+	c.Check(sc.SHA(), Equals, "e0e1f9fabfc9d4800c877a703b823ac0578ff8db")
+	c.Check(sc.Src(), Equals, "return 1")
+}
+
+func (s *ScriptSuite) TestRun(c *C) {
+	sc := NewScript("return 1")
+	cl, f := fakeClient(":1\r\n")
+	n, err := sc.Run(cl, []string{"k"}, "a").Int()
+	c.Assert(err, IsNil)
+	c.Check(n, Equals, 1)
+	c.Check(f.out.String(), Equals, "*5\r\n$7\r\nevalsha\r\n$40\r\n"+sc.SHA()+
+		"\r\n$1\r\n1\r\n$1\r\nk\r\n$1\r\na\r\n")
+
+	cl, f = fakeClient("-NOSCRIPT No matching script. Please use EVAL.\r\n:2\r\n")
+	n, err = sc.Run(cl, nil).Int()
+	c.Assert(err, IsNil)
+	c.Check(n, Equals, 2)
+	c.Check(f.out.String(), Equals, "*3\r\n$7\r\nevalsha\r\n$40\r\n"+sc.SHA()+
+		"\r\n$1\r\n0\r\n*3\r\n$4\r\neval\r\n$8\r\nreturn 1\r\n$1\r\n0\r\n")
+
+	cl, _ = fakeClient("-ERR Error running script\r\n")
+	c.Check(sc.Run(cl, nil).Err, ErrorMatches, "ERR Error running script")
+}
+
+func (s *ScriptSuite) TestRegistry(c *C) {
+	sc := NewScript("return 1")
+	sha := "$40\r\n" + sc.SHA() + "\r\n"
+	cl, f := fakeClient(sha + "-NOSCRIPT No matching script.\r\n" + sha + ":1\r\n")
+	c.Assert(cl.RegisterScript("one", sc), IsNil)
+	n, err := cl.RunScript("one", nil).Int()
+	c.Assert(err, IsNil)
+	c.Check(n, Equals, 1)
+	c.Check(strings.Count(f.out.String(), "$4\r\nload\r\n"), Equals, 2)
+	c.Check(strings.Count(f.out.String(), "evalsha"), Equals, 2)
+	c.Check(cl.RunScript("two", nil).Err, Equals, UnknownScriptError)
+
+	// scripts are loaded on the new connection
+	addr := fakeServer(c, sha+":1\r\n")
+	c.Assert(cl.UpdateConfiguration("tcp", addr, ""), IsNil)
+	n, err = cl.RunScript("one", nil).Int()
+	c.Assert(err, IsNil)
+	c.Check(n, Equals, 1)
+}
+
+func (s *ScriptSuite) TestReloadKeepsPipeline(c *C) {
+	sc := NewScript("return 1")
+	sha := "$40\r\n" + sc.SHA() + "\r\n"
+	cl, _ := fakeClient(sha + "-NOSCRIPT No matching script.\r\n" + sha + ":1\r\n" +
+		"$3\r\nbar\r\n")
+	c.Assert(cl.RegisterScript("one", sc), IsNil)
+	cl.Append("get", "foo")
+	n, err := cl.RunScript("one", nil).Int()
+	c.Assert(err, IsNil)
+	c.Check(n, Equals, 1)
+	v, err := cl.GetReply().Str()
+	c.Assert(err, IsNil)
+	c.Check(v, Equals, "bar")
+	c.Check(cl.GetReply().Err, Equals, PipelineQueueEmptyError)
+}
+
+func (s *ScriptSuite) TestAppend(c *C) {
+	sc := NewScript("return 1")
+	cl, f := fakeClient(":1\r\n-NOSCRIPT No matching script.\r\n+OK\r\n:2\r\n")
+	cl.Append("incr", "a")
+	sc.Append(cl, []string{"k"})
+	cl.Append("set", "b", "c")
+	n, _ := cl.GetReply().Int()
+	c.Check(n, Equals, 1)
+	n, err := cl.GetReply().Int()
+	c.Assert(err, IsNil)
+	c.Check(n, Equals, 2)
+	v, _ := cl.GetReply().Str()
+	c.Check(v, Equals, "OK")
+	c.Check(strings.HasSuffix(f.out.String(),
+		"*4\r\n$4\r\neval\r\n$8\r\nreturn 1\r\n$1\r\n1\r\n$1\r\nk\r\n"), Equals, true)
+
+	// cluster pipelines fall back in the same way
+	cc, _, fb := twoNodeCluster("", "-NOSCRIPT No matching script.\r\n:3\r\n")
+	sc.Append(cc, []string{"foo"})
+	n, err = cc.GetReply().Int()
+	c.Assert(err, IsNil)
+	c.Check(n, Equals, 3)
+	c.Check(strings.Count(fb.out.String(), "$4\r\neval\r\n"), Equals, 1)
+}
+
+func (s *ScriptSuite) TestBusy(c *C) {
+	busy := "-BUSY Redis is busy running a script.\r\n"
+	cl, f := fakeClient(busy)
+	e, ok := cl.Cmd("get", "a").Err.(*ScriptBusyError)
+	c.Assert(ok, Equals, true)
+	c.Check(e.Killed, Equals, false)
+	c.Check(e.Error(), Matches, `BUSY Redis is busy running a script\. \(after .*\)`)
+	c.Check(strings.Contains(f.out.String(), "kill"), Equals, false)
+
+	cl, f = fakeClient(busy + busy + "+OK\r\n")
+	cl.KillBusyScripts = true
+	cl.Append("get", "a")
+	cl.Append("get", "b")
+	for i := 0; i < 2; i++ {
+		e, ok = cl.GetReply().Err.(*ScriptBusyError)
+		c.Assert(ok, Equals, true)
+		c.Check(e.Killed, Equals, true)
+	}
+	c.Check(strings.Count(f.out.String(), "$4\r\nkill\r\n"), Equals, 1)
+}
+
+func (s *ScriptSuite) TestRunRO(c *C) {
+	sc := NewScript("return 1")
+	cc, f := fakeCluster("")
+	replica, fr := fakeClient("+OK\r\n-NOSCRIPT No matching script.\r\n:1\r\n")
+	cc.clients["127.0.0.1:7001"] = replica
+	cc.replicas["127.0.0.1:7001"] = true
+	r := &slotRange{0, numSlots - 1, []string{"127.0.0.1:7000", "127.0.0.1:7001"}}
+	for i := range cc.slots {
+		cc.slots[i] = r
+	}
+	cc.ReadPolicy = ReadPreferReplica
+
+	n, err := sc.RunRO(cc, []string{"foo"}).Int()
+	c.Assert(err, IsNil)
+	c.Check(n, Equals, 1)
+	c.Check(f.out.Len(), Equals, 0)
+	c.Check(strings.Contains(fr.out.String(), "$10\r\nevalsha_ro\r\n"), Equals, true)
+	c.Check(strings.Contains(fr.out.String(), "$7\r\neval_ro\r\n"), Equals, true)
+}
@@ -0,0 +1,52 @@
+package redis
+
+import (
+	"io/fs"
+	"path"
+	"strings"
+)
+
+//* Script files
+
+// LoadScripts reads the .lua files in the given directory of fsys, e.g. an embed.FS,
+// and returns them as scripts keyed by file name without the extension.
+// Subdirectories are not read.
+func LoadScripts(fsys fs.FS, dir string) (map[string]*Script, error) {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil, err
+	}
+	scripts := map[string]*Script{}
+	for _, e := range entries {
+		if e.IsDir() || path.Ext(e.Name()) != ".lua" {
+			continue
+		}
+		src, err := fs.ReadFile(fsys, path.Join(dir, e.Name()))
+		if err != nil {
+			return nil, err
+		}
+		scripts[strings.TrimSuffix(e.Name(), ".lua")] = NewScript(string(src))
+	}
+	return scripts, nil
+}
+
+// RegisterScriptsFS registers the .lua files in the given directory of fsys under their
+// file names without the extension, see LoadScripts and RegisterScript.
+//
+//	//go:embed scripts/*.lua
+//	var scripts embed.FS
+//
+//	err := c.RegisterScriptsFS(scripts, "scripts")
+//	r := c.RunScript("ratelimit", []string{"user:1"}, 10)
+func (c *Client) RegisterScriptsFS(fsys fs.FS, dir string) error {
+	scripts, err := LoadScripts(fsys, dir)
+	if err != nil {
+		return err
+	}
+	for name, s := range scripts {
+		if err = c.RegisterScript(name, s); err != nil {
+			return err
+		}
+	}
+	return nil
+}
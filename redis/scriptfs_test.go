@@ -0,0 +1,31 @@
+package redis
+
+import (
+	. "launchpad.net/gocheck"
+	"testing/fstest"
+)
+
+type ScriptFSSuite struct{}
+
+var _ = Suite(&ScriptFSSuite{})
+
+func (s *ScriptFSSuite) TestLoadScripts(c *C) {
+	fsys := fstest.MapFS{
+		"scripts/one.lua":     {Data: []byte("return 1")},
+		"scripts/README":      {Data: []byte("docs")},
+		"scripts/sub/two.lua": {Data: []byte("return 2")},
+	}
+	scripts, err := LoadScripts(fsys, "scripts")
+	c.Assert(err, IsNil)
+	c.Assert(scripts, HasLen, 1)
+	c.Check(scripts["one"].Src(), Equals, "return 1")
+
+	_, err = LoadScripts(fsys, "missing")
+	c.Check(err, NotNil)
+
+	cl, _ := fakeClient("$40\r\n" + scripts["one"].SHA() + "\r\n:1\r\n")
+	c.Assert(cl.RegisterScriptsFS(fsys, "scripts"), IsNil)
+	n, err := cl.RunScript("one", nil).Int()
+	c.Assert(err, IsNil)
+	c.Check(n, Equals, 1)
+}
@@ -0,0 +1,140 @@
+package redis
+
+import (
+	"time"
+)
+
+//* Script observability
+
+// RunningScript describes the script or function that a server is running.
+type RunningScript struct {
+	Name     string
+	Command  []string
+	Duration time.Duration
+}
+
+// FunctionEngineStats describes the libraries of an engine in FUNCTION STATS.
+type FunctionEngineStats struct {
+	Libraries int
+	Functions int
+}
+
+// FunctionStats describes the output of FUNCTION STATS.
+type FunctionStats struct {
+	Running *RunningScript // nil if no function is running
+	Engines map[string]FunctionEngineStats
+}
+
+// ScriptExists returns whether the scripts with the given SHA1 digests are in the
+// script cache of the server.
+func (c *Client) ScriptExists(shas ...string) ([]bool, error) {
+	return parseScriptExists(c.Cmd("script", "exists", shas))
+}
+
+// ScriptExists returns whether the scripts with the given SHA1 digests are in the
+// script cache of each node of the cluster, by node address.
+func (cc *ClusterClient) ScriptExists(shas ...string) (map[string][]bool, error) {
+	cc.maybeRefresh()
+	m := map[string][]bool{}
+	for _, addr := range cc.nodes() {
+		c, err := cc.conn(addr)
+		if err != nil {
+			return nil, err
+		}
+		start := time.Now()
+		r := c.Cmd("script", "exists", shas)
+		cc.observe(addr, c, r, time.Since(start))
+		if m[addr], err = parseScriptExists(r); err != nil {
+			return nil, err
+		}
+	}
+	return m, nil
+}
+
+// ScriptExists returns whether the scripts with the given SHA1 digests are in the
+// script cache of each server of the ring, by server address.
+func (rc *RingClient) ScriptExists(shas ...string) (map[string][]bool, error) {
+	m := map[string][]bool{}
+	for _, addr := range rc.addrs {
+		c, err := rc.conn(addr)
+		if err != nil {
+			return nil, err
+		}
+		start := time.Now()
+		r := c.Cmd("script", "exists", shas)
+		rc.observe(addr, c, r, time.Since(start))
+		if m[addr], err = parseScriptExists(r); err != nil {
+			return nil, err
+		}
+	}
+	return m, nil
+}
+
+// FunctionStats returns information about the function that the server is running
+// and the loaded libraries (Redis 7.0 or later).
+func (c *Client) FunctionStats() (*FunctionStats, error) {
+	return parseFunctionStats(c.cmdSince(Version{7, 0, 0}, "function", "stats"))
+}
+
+// scriptDone calls the OnScript hook, if set, for the script call started at the given time.
+func (c *Client) scriptDone(name string, start time.Time, r *Reply) {
+	if c.OnScript != nil {
+		c.OnScript(name, time.Since(start), r)
+	}
+}
+
+//* Parsing
+
+// parseScriptExists parses a SCRIPT EXISTS reply.
+func parseScriptExists(r *Reply) ([]bool, error) {
+	var exists []bool
+	if err := r.Decode(&exists); err != nil {
+		return nil, err
+	}
+	return exists, nil
+}
+
+// parseFunctionStats parses a FUNCTION STATS reply.
+func parseFunctionStats(r *Reply) (*FunctionStats, error) {
+	m, err := r.pairs()
+	if err != nil {
+		return nil, err
+	}
+	stats := &FunctionStats{Engines: map[string]FunctionEngineStats{}}
+	if rs, ok := m["running_script"]; ok && rs.Type == MultiReply {
+		rm, err := rs.pairs()
+		if err != nil {
+			return nil, err
+		}
+		stats.Running = &RunningScript{Name: pairStr(rm, "name")}
+		if cmd, ok := rm["command"]; ok {
+			if stats.Running.Command, err = cmd.List(); err != nil {
+				return nil, err
+			}
+		}
+		if d, ok := rm["duration_ms"]; ok {
+			ms, err := d.Int64()
+			if err != nil {
+				return nil, err
+			}
+			stats.Running.Duration = time.Duration(ms) * time.Millisecond
+		}
+	}
+	if engines, ok := m["engines"]; ok {
+		em, err := engines.pairs()
+		if err != nil {
+			return nil, err
+		}
+		for name, e := range em {
+			counts, err := e.intMap()
+			if err != nil {
+				return nil, err
+			}
+			stats.Engines[name] = FunctionEngineStats{
+				Libraries: int(counts["libraries_count"]),
+				Functions: int(counts["functions_count"]),
+			}
+		}
+	}
+	return stats, nil
+}
@@ -0,0 +1,65 @@
+package redis
+
+import (
+	. "launchpad.net/gocheck"
+	"time"
+)
+
+type ScriptInfoSuite struct{}
+
+var _ = Suite(&ScriptInfoSuite{})
+
+func (s *ScriptInfoSuite) TestParseFunctionStats(c *C) {
+	r := multi(
+		bulk("running_script"), multi(
+			bulk("name"), bulk("myfunc"),
+			bulk("command"), multi(bulk("fcall"), bulk("myfunc"), bulk("0")),
+			bulk("duration_ms"), integer(1500),
+		),
+		bulk("engines"), multi(
+			bulk("LUA"), multi(bulk("libraries_count"), integer(1),
+				bulk("functions_count"), integer(2)),
+		),
+	)
+	stats, err := parseFunctionStats(r)
+	c.Assert(err, IsNil)
+	c.Check(stats, DeepEquals, &FunctionStats{
+		Running: &RunningScript{
+			Name:     "myfunc",
+			Command:  []string{"fcall", "myfunc", "0"},
+			Duration: 1500 * time.Millisecond,
+		},
+		Engines: map[string]FunctionEngineStats{"LUA": {1, 2}},
+	})
+
+	stats, err = parseFunctionStats(multi(bulk("running_script"), &Reply{Type: NilReply},
+		bulk("engines"), multi()))
+	c.Assert(err, IsNil)
+	c.Check(stats.Running, IsNil)
+}
+
+func (s *ScriptInfoSuite) TestScriptExists(c *C) {
+	cc, _, _ := twoNodeCluster("*2\r\n:1\r\n:0\r\n", "*2\r\n:0\r\n:0\r\n")
+	m, err := cc.ScriptExists("a", "b")
+	c.Assert(err, IsNil)
+	c.Check(m, DeepEquals, map[string][]bool{
+		"127.0.0.1:7000": {true, false},
+		"127.0.0.1:7001": {false, false},
+	})
+}
+
+func (s *ScriptInfoSuite) TestHooks(c *C) {
+	var name string
+	var reply *Reply
+	cl, _ := fakeClient(":1\r\n:2\r\n")
+	cl.OnScript = func(n string, _ time.Duration, r *Reply) { name, reply = n, r }
+	cl.FCall("myfunc", nil)
+	c.Check(name, Equals, "myfunc")
+	c.Check(reply.Type, Equals, IntegerReply)
+
+	sc := NewScript("return 2")
+	runs := 0
+	sc.OnRun = func(_ time.Duration, r *Reply) { runs++ }
+	sc.Run(cl, nil)
+	c.Check(runs, Equals, 1)
+}
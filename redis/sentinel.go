@@ -0,0 +1,291 @@
+package redis
+
+import (
+	"errors"
+	"log/slog"
+	"net"
+	"strings"
+	"sync"
+	"time"
+)
+
+//* Sentinel
+
+var MasterNotFoundError error = errors.New("no sentinel knows a master for the given name")
+var NotMasterError error = errors.New("server reported by sentinel is not a master")
+var FailoverError error = errors.New("master failed over during command, it may be retried")
+
+// SentinelClient describes a client for a master monitored by Redis Sentinel.
+// The address of the master is queried from the sentinels and the master's role
+// is verified before it is used.
+//
+// SentinelClient follows the +switch-master events of the sentinels.
+// When the master fails over, the command in progress fails with FailoverError
+// and the next command is sent to the new master. Commands failing with READONLY,
+// as sent to a demoted master, are retried on the rediscovered master according to Retry.
+// Like Client, SentinelClient is not safe for concurrent use.
+type SentinelClient struct {
+	Retry     RetryPolicy // Retrying of commands failing with topology errors
+	network   string
+	timeout   time.Duration
+	sentinels []string
+	name      string
+	mu        sync.Mutex // guards the fields below and writes to sentinels
+	client    *Client
+	addr      string
+	switched  string // address announced by +switch-master
+	watcher   *Client
+	closed    bool
+}
+
+// DialSentinelTimeout connects to the master of the given name using the given sentinels
+// with the given timeout.
+func DialSentinelTimeout(network string, sentinels []string, name string,
+	timeout time.Duration) (*SentinelClient, error) {
+	sc := &SentinelClient{
+		Retry:     DefaultRetryPolicy,
+		network:   network,
+		timeout:   timeout,
+		sentinels: append([]string(nil), sentinels...),
+		name:      name,
+	}
+	if err := sc.Discover(); err != nil {
+		return nil, err
+	}
+	go sc.watch()
+	return sc, nil
+}
+
+// DialSentinel connects to the master of the given name using the given sentinels.
+func DialSentinel(network string, sentinels []string, name string) (*SentinelClient, error) {
+	return DialSentinelTimeout(network, sentinels, name, time.Duration(0))
+}
+
+//* Public methods
+
+// Close closes the connections to the master and the sentinels.
+func (sc *SentinelClient) Close() error {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	sc.closed = true
+	if sc.watcher != nil {
+		sc.watcher.Close()
+	}
+	if sc.client == nil {
+		return nil
+	}
+	err := sc.client.Close()
+	sc.client = nil
+	return err
+}
+
+// Addr returns the address of the current master.
+func (sc *SentinelClient) Addr() string {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	return sc.addr
+}
+
+// Cmd calls the given Redis command on the current master.
+// If the connection to the master has failed or the master has failed over,
+// the master is discovered again first.
+func (sc *SentinelClient) Cmd(cmd string, args ...interface{}) *Reply {
+	for i := 0; ; i++ {
+		r := sc.cmd(cmd, args)
+		if !isTopologyError(r) || !sc.Retry.wait(i) {
+			return r
+		}
+		logEvent(slog.LevelInfo, "redis: retrying command", "cmd", cmd, "attempt", i+1,
+			"error", r.Err)
+	}
+}
+
+//* Private methods
+
+// cmd calls the given command on the current master once.
+func (sc *SentinelClient) cmd(cmd string, args []interface{}) *Reply {
+	c, err := sc.master()
+	if err != nil {
+		return &Reply{Type: ErrorReply, Err: err}
+	}
+	r := c.Cmd(cmd, args...)
+	if isTopologyError(r) {
+		// the master has been demoted, discover the new one before the next command
+		sc.mu.Lock()
+		if sc.client == c {
+			sc.client = nil
+		}
+		sc.mu.Unlock()
+		c.Close()
+	} else if r.Type == ErrorReply && isConnError(r.Err) {
+		logEvent(slog.LevelWarn, "redis: dropped connection to master", "name", sc.name,
+			"error", r.Err)
+		sc.mu.Lock()
+		failover := sc.switched != ""
+		if sc.client == c {
+			sc.client = nil
+		}
+		sc.mu.Unlock()
+		c.Close()
+		if failover {
+			r = &Reply{Type: ErrorReply, Err: FailoverError}
+		}
+	}
+	return r
+}
+
+// Discover queries the sentinels for the current master and connects to it.
+// The first sentinel that answers is tried first on subsequent discoveries.
+func (sc *SentinelClient) Discover() error {
+	err := MasterNotFoundError
+	for i, saddr := range sc.sentinels {
+		var addr string
+		if addr, err = sc.masterAddr(saddr); err != nil {
+			continue
+		}
+		var c *Client
+		if c, err = sc.dialMaster(addr); err != nil {
+			continue
+		}
+
+		sc.mu.Lock()
+		old := sc.client
+		sc.sentinels[0], sc.sentinels[i] = sc.sentinels[i], sc.sentinels[0]
+		sc.client, sc.addr, sc.switched = c, addr, ""
+		sc.mu.Unlock()
+		if old != nil {
+			old.Close()
+		}
+		logEvent(slog.LevelInfo, "redis: connected to master", "name", sc.name, "addr", addr)
+		return nil
+	}
+	return err
+}
+
+// master returns the Client of the current master, discovering the master first
+// if the connection has failed or a failover has been announced.
+func (sc *SentinelClient) master() (*Client, error) {
+	sc.mu.Lock()
+	c, switched := sc.client, sc.switched
+	sc.mu.Unlock()
+	if c != nil && switched == "" {
+		return c, nil
+	}
+	if err := sc.Discover(); err != nil {
+		return nil, err
+	}
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	return sc.client, nil
+}
+
+// masterAddr asks the given sentinel for the address of the master.
+func (sc *SentinelClient) masterAddr(saddr string) (string, error) {
+	s, err := DialTimeout(sc.network, saddr, sc.timeout)
+	if err != nil {
+		return "", err
+	}
+	defer s.Close()
+
+	r := s.Cmd("sentinel", "get-master-addr-by-name", sc.name)
+	if r.Type == NilReply {
+		return "", MasterNotFoundError
+	}
+	l, err := r.List()
+	if err != nil {
+		return "", err
+	}
+	if len(l) != 2 {
+		return "", ParseError
+	}
+	return net.JoinHostPort(l[0], l[1]), nil
+}
+
+// dialMaster connects to the given address and verifies that it is a master.
+func (sc *SentinelClient) dialMaster(addr string) (*Client, error) {
+	c, err := DialTimeout(sc.network, addr, sc.timeout)
+	if err != nil {
+		return nil, err
+	}
+	role, err := c.Role()
+	if err != nil {
+		c.Close()
+		return nil, err
+	}
+	if !role.IsMaster() {
+		c.Close()
+		return nil, NotMasterError
+	}
+	return c, nil
+}
+
+// watch follows the +switch-master events of the sentinels until sc is closed.
+func (sc *SentinelClient) watch() {
+	for {
+		sc.mu.Lock()
+		if sc.closed {
+			sc.mu.Unlock()
+			return
+		}
+		sentinels := append([]string(nil), sc.sentinels...)
+		sc.mu.Unlock()
+
+		for _, saddr := range sentinels {
+			sc.follow(saddr)
+		}
+		time.Sleep(time.Second)
+	}
+}
+
+// follow subscribes to +switch-master on the given sentinel and handles the events
+// until the connection fails.
+func (sc *SentinelClient) follow(saddr string) {
+	s, err := Dial(sc.network, saddr)
+	if err != nil {
+		return
+	}
+	defer s.Close()
+	sc.mu.Lock()
+	if sc.closed {
+		sc.mu.Unlock()
+		return
+	}
+	sc.watcher = s
+	sc.mu.Unlock()
+
+	if r := s.Cmd("subscribe", "+switch-master"); r.Type == ErrorReply {
+		return
+	}
+	for {
+		r := s.readReply()
+		if r.Type == ErrorReply {
+			return
+		}
+		// message +switch-master "<name> <old ip> <old port> <new ip> <new port>"
+		l, err := r.List()
+		if err != nil || len(l) != 3 || l[0] != "message" {
+			continue
+		}
+		f := strings.Fields(l[2])
+		if len(f) != 5 || f[0] != sc.name {
+			continue
+		}
+		sc.switchMaster(net.JoinHostPort(f[3], f[4]))
+	}
+}
+
+// switchMaster records a failover to the given address and closes the connection
+// to the old master, failing the command in progress.
+func (sc *SentinelClient) switchMaster(addr string) {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	if addr == sc.addr {
+		return
+	}
+	logEvent(slog.LevelWarn, "redis: master failed over", "name", sc.name, "from", sc.addr,
+		"to", addr)
+	sc.switched = addr
+	if sc.client != nil {
+		sc.client.Close()
+	}
+}
@@ -0,0 +1,77 @@
+package redis
+
+import (
+	. "launchpad.net/gocheck"
+	"net"
+	"strings"
+	"time"
+)
+
+type SentinelSuite struct{}
+
+var _ = Suite(&SentinelSuite{})
+
+// masterReply returns a SENTINEL get-master-addr-by-name reply for the given address.
+func masterReply(addr string) string {
+	host, port, _ := net.SplitHostPort(addr)
+	return "*2\r\n" + string(formatArg(host)) + string(formatArg(port))
+}
+
+func (s *SentinelSuite) TestDiscover(c *C) {
+	replica := fakeServer(c,
+		"*5\r\n$5\r\nslave\r\n$9\r\n127.0.0.1\r\n:1\r\n$9\r\nconnected\r\n:0\r\n")
+	master := fakeServer(c, "*3\r\n$6\r\nmaster\r\n:0\r\n*0\r\n$3\r\nbar\r\n")
+	unknown := fakeServer(c, "*-1\r\n")
+	stale := fakeServer(c, masterReply(replica))
+	good := fakeServer(c, masterReply(master))
+
+	sc, err := DialSentinel("tcp", []string{"127.0.0.1:1", unknown, stale, good}, "mymaster")
+	c.Assert(err, IsNil)
+	defer sc.Close()
+	c.Check(sc.Addr(), Equals, master)
+	c.Check(sc.sentinels[0], Equals, good)
+
+	v, err := sc.Cmd("get", "foo").Str()
+	c.Assert(err, IsNil)
+	c.Check(v, Equals, "bar")
+}
+
+func (s *SentinelSuite) TestMasterNotFound(c *C) {
+	unknown := fakeServer(c, "*-1\r\n")
+	_, err := DialSentinel("tcp", []string{unknown}, "mymaster")
+	c.Check(err, Equals, MasterNotFoundError)
+}
+
+func (s *SentinelSuite) TestSwitchMaster(c *C) {
+	role := "*3\r\n$6\r\nmaster\r\n:0\r\n*0\r\n"
+	oldMaster := fakeServer(c, role)
+	newMaster := fakeServer(c, role+"$3\r\nbar\r\n")
+	oldHost, oldPort, _ := net.SplitHostPort(oldMaster)
+	newHost, newPort, _ := net.SplitHostPort(newMaster)
+	event := strings.Join([]string{"mymaster", oldHost, oldPort, newHost, newPort}, " ")
+	sentinel := fakeServer(c,
+		masterReply(oldMaster),
+		"*3\r\n$9\r\nsubscribe\r\n$14\r\n+switch-master\r\n:1\r\n"+
+			"*3\r\n$7\r\nmessage\r\n$14\r\n+switch-master\r\n"+string(formatArg(event)),
+		masterReply(newMaster))
+
+	sc, err := DialSentinel("tcp", []string{sentinel}, "mymaster")
+	c.Assert(err, IsNil)
+	defer sc.Close()
+	c.Check(sc.Addr(), Equals, oldMaster)
+
+	for i := 0; i < 100; i++ {
+		sc.mu.Lock()
+		switched := sc.switched
+		sc.mu.Unlock()
+		if switched != "" {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	v, err := sc.Cmd("get", "foo").Str()
+	c.Assert(err, IsNil)
+	c.Check(v, Equals, "bar")
+	c.Check(sc.Addr(), Equals, newMaster)
+}
@@ -0,0 +1,116 @@
+package redis
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"time"
+)
+
+//* Server
+
+var DestructiveError error = errors.New("destructive command is not allowed by the client")
+
+// FlushMode is the mode of FlushDB and FlushAll.
+type FlushMode string
+
+const (
+	FlushDefault FlushMode = ""      // Use the lazyfree-lazy-user-flush setting of the server
+	FlushSync    FlushMode = "sync"  // Delete the keys before returning
+	FlushAsync   FlushMode = "async" // Delete the keys in the background
+)
+
+func (m FlushMode) args() []interface{} {
+	if m == FlushDefault {
+		return nil
+	}
+	return []interface{}{string(m)}
+}
+
+// FlushDB deletes all keys of the current database. It fails with DestructiveError
+// unless AllowDestructive is set.
+func (c *Client) FlushDB(mode FlushMode) error {
+	if !c.AllowDestructive {
+		return DestructiveError
+	}
+	return c.Cmd("flushdb", mode.args()...).Err
+}
+
+// FlushAll deletes all keys of all databases. It fails with DestructiveError unless
+// AllowDestructive is set.
+func (c *Client) FlushAll(mode FlushMode) error {
+	if !c.AllowDestructive {
+		return DestructiveError
+	}
+	return c.Cmd("flushall", mode.args()...).Err
+}
+
+// BgSave starts saving the dataset to disk in the background. If schedule is set and
+// an AOF rewrite is in progress, the save is scheduled to run after it, and BgSave
+// returns true.
+func (c *Client) BgSave(schedule bool) (scheduled bool, err error) {
+	var args []interface{}
+	if schedule {
+		args = append(args, "schedule")
+	}
+	return parseScheduled(c.Cmd("bgsave", args))
+}
+
+// BgRewriteAOF starts rewriting the append only file in the background. It returns
+// true if the rewrite is scheduled to run after a save in progress.
+func (c *Client) BgRewriteAOF() (scheduled bool, err error) {
+	return parseScheduled(c.Cmd("bgrewriteaof"))
+}
+
+// parseScheduled parses the status reply of BGSAVE and BGREWRITEAOF, which says
+// whether the operation started or was scheduled.
+func parseScheduled(r *Reply) (bool, error) {
+	s, err := r.Str()
+	if err != nil {
+		return false, err
+	}
+	return strings.Contains(s, "scheduled"), nil
+}
+
+// LastSave returns the time of the last successful save of the dataset to disk.
+func (c *Client) LastSave() (time.Time, error) {
+	n, err := c.Cmd("lastsave").Int64()
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Unix(n, 0), nil
+}
+
+// Save saves the dataset to disk, blocking all clients until it is done. Prefer
+// BgSave on servers in use.
+func (c *Client) Save() error {
+	return c.Cmd("save").Err
+}
+
+// ShutdownMode is the mode of Shutdown.
+type ShutdownMode string
+
+const (
+	ShutdownDefault ShutdownMode = ""       // Save if save points are configured
+	ShutdownSave    ShutdownMode = "save"   // Save even without save points
+	ShutdownNoSave  ShutdownMode = "nosave" // Don't save, losing the changes since the last save
+)
+
+// Shutdown stops the server and closes the client. It fails with DestructiveError
+// unless AllowDestructive is set.
+func (c *Client) Shutdown(mode ShutdownMode) error {
+	if !c.AllowDestructive {
+		return DestructiveError
+	}
+	var args []interface{}
+	if mode != ShutdownDefault {
+		args = append(args, string(mode))
+	}
+	r := c.Cmd("shutdown", args)
+	// the server closes the connection instead of replying on success
+	if r.Type == ErrorReply && r.Err != io.EOF {
+		return r.Err
+	}
+	c.Close()
+	return nil
+}
@@ -0,0 +1,62 @@
+package redis
+
+import (
+	. "launchpad.net/gocheck"
+	"time"
+)
+
+type ServerSuite struct{}
+
+var _ = Suite(&ServerSuite{})
+
+func (s *ServerSuite) TestFlush(c *C) {
+	cl, f := fakeClient("+OK\r\n+OK\r\n")
+	c.Check(cl.FlushDB(FlushAsync), Equals, DestructiveError)
+	c.Check(cl.FlushAll(FlushDefault), Equals, DestructiveError)
+	c.Check(f.out.Len(), Equals, 0)
+
+	cl.AllowDestructive = true
+	c.Assert(cl.FlushDB(FlushAsync), IsNil)
+	c.Assert(cl.FlushAll(FlushDefault), IsNil)
+	c.Check(f.out.String(), Equals,
+		"*2\r\n$7\r\nflushdb\r\n$5\r\nasync\r\n*1\r\n$8\r\nflushall\r\n")
+}
+
+func (s *ServerSuite) TestPersistence(c *C) {
+	cl, f := fakeClient("+Background saving started\r\n" +
+		"+Background saving scheduled\r\n" +
+		"+Background append only file rewriting started\r\n" +
+		":1700000000\r\n+OK\r\n-ERR Background save already in progress\r\n")
+	scheduled, err := cl.BgSave(false)
+	c.Assert(err, IsNil)
+	c.Check(scheduled, Equals, false)
+	scheduled, err = cl.BgSave(true)
+	c.Assert(err, IsNil)
+	c.Check(scheduled, Equals, true)
+	scheduled, err = cl.BgRewriteAOF()
+	c.Assert(err, IsNil)
+	c.Check(scheduled, Equals, false)
+	t, err := cl.LastSave()
+	c.Assert(err, IsNil)
+	c.Check(t.Equal(time.Unix(1700000000, 0)), Equals, true)
+	c.Check(cl.Save(), IsNil)
+	_, err = cl.BgSave(false)
+	c.Check(err, ErrorMatches, "ERR Background save already in progress")
+	c.Check(f.out.String(), Equals, "*1\r\n$6\r\nbgsave\r\n"+
+		"*2\r\n$6\r\nbgsave\r\n$8\r\nschedule\r\n"+
+		"*1\r\n$12\r\nbgrewriteaof\r\n*1\r\n$8\r\nlastsave\r\n*1\r\n$4\r\nsave\r\n"+
+		"*1\r\n$6\r\nbgsave\r\n")
+}
+
+func (s *ServerSuite) TestShutdown(c *C) {
+	cl, f := fakeClient("")
+	c.Check(cl.Shutdown(ShutdownNoSave), Equals, DestructiveError)
+	c.Check(f.out.Len(), Equals, 0)
+	cl.AllowDestructive = true
+	c.Check(cl.Shutdown(ShutdownNoSave), IsNil)
+	c.Check(f.out.String(), Equals, "*2\r\n$8\r\nshutdown\r\n$6\r\nnosave\r\n")
+
+	cl, _ = fakeClient("-ERR Errors trying to SHUTDOWN. Check logs.\r\n")
+	cl.AllowDestructive = true
+	c.Check(cl.Shutdown(ShutdownDefault), ErrorMatches, "ERR Errors trying to SHUTDOWN.*")
+}
@@ -0,0 +1,13 @@
+package redis
+
+//* Sets
+
+// SInterCard returns the number of members in the intersection of the sets at the given
+// keys, stopping at limit if it is not 0 (Redis 7.0 or later).
+func (c *Client) SInterCard(limit int64, keys ...string) (int64, error) {
+	var args []interface{}
+	if limit > 0 {
+		args = append(args, "limit", limit)
+	}
+	return c.cmdSince(Version{7, 0, 0}, "sintercard", len(keys), keys, args).Int64()
+}
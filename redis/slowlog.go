@@ -0,0 +1,77 @@
+package redis
+
+import (
+	"errors"
+	"time"
+)
+
+//* Slow log
+
+// SlowLogEntry is an entry of the slow log.
+type SlowLogEntry struct {
+	ID         int64
+	Time       time.Time     // When the command was executed
+	Duration   time.Duration // Execution time, not counting I/O
+	Args       []string      // Command and arguments, possibly truncated by the server
+	ClientAddr string        // Redis 4.0 or later
+	ClientName string        // Redis 4.0 or later
+}
+
+// SlowLogGet returns the given number of most recent slow log entries, newest first.
+// A zero count uses the server default of 10 and a negative count returns all
+// entries (Redis 7.0 or later).
+func (c *Client) SlowLogGet(count int) ([]SlowLogEntry, error) {
+	var args []interface{}
+	if count != 0 {
+		args = append(args, count)
+	}
+	return parseSlowLog(c.Cmd("slowlog", "get", args))
+}
+
+// SlowLogLen returns the number of entries in the slow log.
+func (c *Client) SlowLogLen() (int64, error) {
+	return c.Cmd("slowlog", "len").Int64()
+}
+
+// SlowLogReset deletes all entries of the slow log.
+func (c *Client) SlowLogReset() error {
+	return c.Cmd("slowlog", "reset").Err
+}
+
+// parseSlowLog parses the reply of SLOWLOG GET.
+func parseSlowLog(r *Reply) ([]SlowLogEntry, error) {
+	if r.Type == ErrorReply {
+		return nil, r.Err
+	}
+	if r.Type != MultiReply {
+		return nil, errors.New("reply type is not MultiReply")
+	}
+	entries := make([]SlowLogEntry, len(r.Elems))
+	for i, er := range r.Elems {
+		if er.Type != MultiReply || len(er.Elems) < 4 {
+			return nil, errors.New("reply is not a slow log entry")
+		}
+		e := &entries[i]
+		var err error
+		if e.ID, err = er.Elems[0].Int64(); err != nil {
+			return nil, err
+		}
+		sec, err := er.Elems[1].Int64()
+		if err != nil {
+			return nil, err
+		}
+		us, err := er.Elems[2].Int64()
+		if err != nil {
+			return nil, err
+		}
+		e.Time, e.Duration = time.Unix(sec, 0), time.Duration(us)*time.Microsecond
+		if e.Args, err = er.Elems[3].List(); err != nil {
+			return nil, err
+		}
+		if len(er.Elems) >= 6 {
+			e.ClientAddr, _ = er.Elems[4].Str()
+			e.ClientName, _ = er.Elems[5].Str()
+		}
+	}
+	return entries, nil
+}
@@ -0,0 +1,45 @@
+package redis
+
+import (
+	. "launchpad.net/gocheck"
+	"time"
+)
+
+type SlowLogSuite struct{}
+
+var _ = Suite(&SlowLogSuite{})
+
+func (s *SlowLogSuite) TestParseSlowLog(c *C) {
+	l, err := parseSlowLog(multi(
+		multi(integer(14), integer(1700000000), integer(2500),
+			multi(bulk("keys"), bulk("*")), bulk("127.0.0.1:5000"), bulk("worker")),
+		multi(integer(13), integer(1600000000), integer(10), multi(bulk("ping")))))
+	c.Assert(err, IsNil)
+	c.Check(l, DeepEquals, []SlowLogEntry{
+		{ID: 14, Time: time.Unix(1700000000, 0), Duration: 2500 * time.Microsecond,
+			Args: []string{"keys", "*"}, ClientAddr: "127.0.0.1:5000", ClientName: "worker"},
+		{ID: 13, Time: time.Unix(1600000000, 0), Duration: 10 * time.Microsecond,
+			Args: []string{"ping"}},
+	})
+
+	_, err = parseSlowLog(multi(multi(integer(1))))
+	c.Check(err, NotNil)
+	_, err = parseSlowLog(&Reply{Type: ErrorReply, Err: ParseError})
+	c.Check(err, Equals, ParseError)
+}
+
+func (s *SlowLogSuite) TestSlowLog(c *C) {
+	cl, f := fakeClient("*0\r\n*0\r\n:3\r\n+OK\r\n")
+	l, err := cl.SlowLogGet(0)
+	c.Assert(err, IsNil)
+	c.Check(l, HasLen, 0)
+	_, err = cl.SlowLogGet(-1)
+	c.Assert(err, IsNil)
+	n, err := cl.SlowLogLen()
+	c.Assert(err, IsNil)
+	c.Check(n, Equals, int64(3))
+	c.Check(cl.SlowLogReset(), IsNil)
+	c.Check(f.out.String(), Equals, "*2\r\n$7\r\nslowlog\r\n$3\r\nget\r\n"+
+		"*3\r\n$7\r\nslowlog\r\n$3\r\nget\r\n$2\r\n-1\r\n"+
+		"*2\r\n$7\r\nslowlog\r\n$3\r\nlen\r\n*2\r\n$7\r\nslowlog\r\n$5\r\nreset\r\n")
+}
@@ -0,0 +1,90 @@
+package redis
+
+//* Sort
+
+// Sort builds a SORT command sorting the elements of a list, set or sorted set.
+//
+//	s := redis.NewSort("users").By("user:*->age").Get("#", "user:*->name").Limit(0, 10)
+//	l, err := c.Sort(s)
+type Sort struct {
+	key   string
+	by    string
+	limit []interface{}
+	get   []interface{}
+	desc  bool
+	alpha bool
+}
+
+// NewSort returns a Sort of the elements at the given key, in ascending numeric order.
+func NewSort(key string) *Sort {
+	return &Sort{key: key}
+}
+
+// By sorts by the values of the keys obtained by substituting the first "*" in the
+// given pattern with each element. Hash fields are referenced with "key->field".
+// Pass "nosort" to skip sorting, e.g. to only use Get.
+func (s *Sort) By(pattern string) *Sort {
+	s.by = pattern
+	return s
+}
+
+// Limit returns count elements, skipping the first offset ones.
+func (s *Sort) Limit(offset, count int64) *Sort {
+	s.limit = []interface{}{"limit", offset, count}
+	return s
+}
+
+// Get returns the values of the keys obtained by substituting the first "*" in each of
+// the given patterns with each element, instead of the elements. "#" returns the
+// element itself.
+func (s *Sort) Get(patterns ...string) *Sort {
+	for _, p := range patterns {
+		s.get = append(s.get, "get", p)
+	}
+	return s
+}
+
+// Desc sorts in descending order.
+func (s *Sort) Desc() *Sort {
+	s.desc = true
+	return s
+}
+
+// Alpha sorts lexicographically instead of numerically.
+func (s *Sort) Alpha() *Sort {
+	s.alpha = true
+	return s
+}
+
+func (s *Sort) args() []interface{} {
+	args := []interface{}{s.key}
+	if s.by != "" {
+		args = append(args, "by", s.by)
+	}
+	args = append(args, s.limit...)
+	args = append(args, s.get...)
+	if s.desc {
+		args = append(args, "desc")
+	}
+	if s.alpha {
+		args = append(args, "alpha")
+	}
+	return args
+}
+
+// Sort returns the elements sorted by the given Sort, or the values of its Get patterns.
+// Missing values of Get patterns are returned as empty strings.
+func (c *Client) Sort(s *Sort) ([]string, error) {
+	return c.Cmd("sort", s.args()...).List()
+}
+
+// SortRO is like Sort, but uses SORT_RO, which may run on replicas (Redis 7.0 or later).
+func (c *Client) SortRO(s *Sort) ([]string, error) {
+	return c.cmdSince(Version{7, 0, 0}, "sort_ro", s.args()...).List()
+}
+
+// SortStore stores the result of the given Sort as a list at the key dst and returns
+// its length.
+func (c *Client) SortStore(s *Sort, dst string) (int64, error) {
+	return c.Cmd("sort", append(s.args(), "store", dst)...).Int64()
+}
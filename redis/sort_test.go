@@ -0,0 +1,27 @@
+package redis
+
+import (
+	. "launchpad.net/gocheck"
+)
+
+type SortSuite struct{}
+
+var _ = Suite(&SortSuite{})
+
+func (s *SortSuite) TestSort(c *C) {
+	cl, f := fakeClient("*4\r\n$1\r\n2\r\n$3\r\nbob\r\n$1\r\n1\r\n$-1\r\n:2\r\n")
+	srt := NewSort("users").By("user:*->age").Get("#", "user:*->name").Limit(0, 2).Desc()
+	l, err := cl.Sort(srt)
+	c.Assert(err, IsNil)
+	c.Check(l, DeepEquals, []string{"2", "bob", "1", ""})
+	c.Check(f.out.String(), Equals, "*12\r\n$4\r\nsort\r\n$5\r\nusers\r\n"+
+		"$2\r\nby\r\n$11\r\nuser:*->age\r\n$5\r\nlimit\r\n$1\r\n0\r\n$1\r\n2\r\n"+
+		"$3\r\nget\r\n$1\r\n#\r\n$3\r\nget\r\n$12\r\nuser:*->name\r\n$4\r\ndesc\r\n")
+
+	f.out.Reset()
+	n, err := cl.SortStore(NewSort("l").Alpha(), "dst")
+	c.Assert(err, IsNil)
+	c.Check(n, Equals, int64(2))
+	c.Check(f.out.String(), Equals, "*5\r\n$4\r\nsort\r\n$1\r\nl\r\n$5\r\nalpha\r\n"+
+		"$5\r\nstore\r\n$3\r\ndst\r\n")
+}
@@ -0,0 +1,106 @@
+package redis
+
+import (
+	"time"
+)
+
+//* Node statistics
+
+// NodeStats holds the counters of a node of ClusterClient or RingClient.
+type NodeStats struct {
+	Commands    int64         // Replies read from the node, including error replies
+	Errors      int64         // Error replies, including connection failures
+	ConnErrors  int64         // Connection failures
+	Dials       int64         // Connection attempts
+	DialErrors  int64         // Failed connection attempts
+	Latency     time.Duration // Total time spent waiting for replies
+	MaxLatency  time.Duration
+	Connected   bool // Whether the client holds a connection to the node
+	Quarantined bool // See QuarantinePolicy
+}
+
+// ErrorRate returns the fraction of commands that failed.
+func (s NodeStats) ErrorRate() float64 {
+	if s.Commands == 0 {
+		return 0
+	}
+	return float64(s.Errors) / float64(s.Commands)
+}
+
+// AvgLatency returns the average time spent waiting for a reply.
+func (s NodeStats) AvgLatency() time.Duration {
+	if s.Commands == 0 {
+		return 0
+	}
+	return s.Latency / time.Duration(s.Commands)
+}
+
+// nodeStats holds the counters of nodes by address.
+type nodeStats map[string]*NodeStats
+
+func (m nodeStats) get(addr string) *NodeStats {
+	s, ok := m[addr]
+	if !ok {
+		s = &NodeStats{}
+		m[addr] = s
+	}
+	return s
+}
+
+// record counts the given reply of the given node, which took d to arrive.
+func (m nodeStats) record(addr string, r *Reply, d time.Duration) {
+	s := m.get(addr)
+	s.Commands++
+	if r.Type == ErrorReply {
+		s.Errors++
+		if isConnError(r.Err) {
+			s.ConnErrors++
+		}
+	}
+	s.Latency += d
+	if d > s.MaxLatency {
+		s.MaxLatency = d
+	}
+}
+
+// dialed counts a connection attempt to the given node.
+func (m nodeStats) dialed(addr string, err error) {
+	s := m.get(addr)
+	s.Dials++
+	if err != nil {
+		s.DialErrors++
+	}
+}
+
+// snapshot returns a copy of the counters, completed with the connection and
+// quarantine state of the nodes.
+func (m nodeStats) snapshot(clients map[string]*Client, health quarantine) map[string]NodeStats {
+	stats := make(map[string]NodeStats, len(m))
+	for addr, s := range m {
+		c := *s
+		_, c.Connected = clients[addr]
+		c.Quarantined = health.blocked(addr)
+		stats[addr] = c
+	}
+	return stats
+}
+
+// Stats returns the counters of the nodes of the cluster by address.
+func (cc *ClusterClient) Stats() map[string]NodeStats {
+	return cc.stats.snapshot(cc.clients, cc.health)
+}
+
+// ResetStats resets the counters of the nodes of the cluster.
+func (cc *ClusterClient) ResetStats() {
+	cc.stats = nodeStats{}
+}
+
+// Stats returns the counters of the servers of the ring by address.
+func (rc *RingClient) Stats() map[string]NodeStats {
+	return rc.stats.snapshot(rc.clients, rc.health)
+}
+
+// ResetStats resets the counters of the servers of the ring.
+func (rc *RingClient) ResetStats() {
+	rc.stats = nodeStats{}
+}
@@ -0,0 +1,50 @@
+package redis
+
+import (
+	"errors"
+	"io"
+	. "launchpad.net/gocheck"
+	"time"
+)
+
+type StatsSuite struct{}
+
+var _ = Suite(&StatsSuite{})
+
+func (s *StatsSuite) TestRecord(c *C) {
+	m := nodeStats{}
+	m.record("a", &Reply{Type: IntegerReply}, 2*time.Millisecond)
+	m.record("a", &Reply{Type: ErrorReply, Err: errors.New("ERR")}, 4*time.Millisecond)
+	m.record("a", &Reply{Type: ErrorReply, Err: io.EOF}, 0)
+	m.record("a", &Reply{Type: IntegerReply}, 2*time.Millisecond)
+	m.dialed("a", nil)
+	m.dialed("a", io.EOF)
+
+	st := m.snapshot(map[string]*Client{"a": nil}, quarantine{})["a"]
+	c.Check(st.Commands, Equals, int64(4))
+	c.Check(st.Errors, Equals, int64(2))
+	c.Check(st.ConnErrors, Equals, int64(1))
+	c.Check(st.Dials, Equals, int64(2))
+	c.Check(st.DialErrors, Equals, int64(1))
+	c.Check(st.MaxLatency, Equals, 4*time.Millisecond)
+	c.Check(st.AvgLatency(), Equals, 2*time.Millisecond)
+	c.Check(st.ErrorRate(), Equals, 0.5)
+	c.Check(st.Connected, Equals, true)
+	c.Check(NodeStats{}.ErrorRate(), Equals, 0.0)
+}
+
+func (s *StatsSuite) TestCluster(c *C) {
+	cc, _, _ := twoNodeCluster("$1\r\nb\r\n", "$1\r\nf\r\n-ERR wrong type\r\n")
+	cc.Cmd("get", "bar")
+	cc.Cmd("get", "foo")
+	cc.Cmd("get", "foo")
+
+	st := cc.Stats()
+	c.Check(st["127.0.0.1:7000"].Commands, Equals, int64(1))
+	c.Check(st["127.0.0.1:7001"].Commands, Equals, int64(2))
+	c.Check(st["127.0.0.1:7001"].Errors, Equals, int64(1))
+	c.Check(st["127.0.0.1:7001"].Connected, Equals, true)
+
+	cc.ResetStats()
+	c.Check(cc.Stats(), HasLen, 0)
+}
@@ -0,0 +1,305 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+//* Streams
+
+// StreamEntry describes an entry of a stream.
+type StreamEntry struct {
+	ID     string
+	Fields map[string]string // nil for entries that were deleted while pending
+}
+
+// StreamID describes the ID of a stream entry, "<ms>-<seq>".
+type StreamID struct {
+	Ms  uint64 // Unix time in milliseconds
+	Seq uint64 // Sequence number within the millisecond
+}
+
+// StreamIDAt returns the smallest ID of the entries added at the given time,
+// e.g. for ranges starting at a point in time.
+func StreamIDAt(t time.Time) StreamID {
+	return StreamID{Ms: uint64(t.UnixNano() / int64(time.Millisecond))}
+}
+
+// ParseStreamID parses the given stream entry ID. A missing sequence number means 0.
+func ParseStreamID(s string) (StreamID, error) {
+	var id StreamID
+	ms, seq := s, ""
+	if i := strings.IndexByte(s, '-'); i >= 0 {
+		ms, seq = s[:i], s[i+1:]
+	}
+	var err error
+	if id.Ms, err = strconv.ParseUint(ms, 10, 64); err != nil {
+		return id, fmt.Errorf("invalid stream ID %q", s)
+	}
+	if seq != "" {
+		if id.Seq, err = strconv.ParseUint(seq, 10, 64); err != nil {
+			return id, fmt.Errorf("invalid stream ID %q", s)
+		}
+	}
+	return id, nil
+}
+
+// String returns the ID in the "<ms>-<seq>" form.
+func (id StreamID) String() string {
+	return strconv.FormatUint(id.Ms, 10) + "-" + strconv.FormatUint(id.Seq, 10)
+}
+
+// Time returns the time the entry was added at.
+func (id StreamID) Time() time.Time {
+	return time.Unix(0, int64(id.Ms)*int64(time.Millisecond))
+}
+
+// Next returns the smallest ID greater than the ID, e.g. for continuing a range
+// after its last entry.
+func (id StreamID) Next() StreamID {
+	if id.Seq == ^uint64(0) {
+		return StreamID{Ms: id.Ms + 1}
+	}
+	return StreamID{Ms: id.Ms, Seq: id.Seq + 1}
+}
+
+// XAddOptions describes the options of XAdd.
+// MaxLen and MinID trim the stream while adding.
+type XAddOptions struct {
+	ID         string // Entry ID, empty for an automatically generated ID ("*")
+	NoMkStream bool   // Do not create the stream if it does not exist
+	MaxLen     int64  // Evict the oldest entries beyond this length, 0 disables
+	MinID      string // Evict entries with lower IDs, empty disables
+	Approx     bool   // Trim approximately ("~"), which is much more efficient
+	Limit      int64  // Maximum number of entries evicted with Approx, 0 for the default
+}
+
+// trimArgs returns the trimming arguments of XADD.
+func (opt XAddOptions) trimArgs() []interface{} {
+	return XTrimOptions{opt.MaxLen, opt.MinID, opt.Approx, opt.Limit}.args()
+}
+
+// XAdd adds an entry with the given fields to the given stream and returns its ID.
+// With NoMkStream, an empty ID is returned if the stream does not exist.
+func (c *Client) XAdd(stream string, fields map[string]interface{},
+	opt XAddOptions) (string, error) {
+	args, err := xaddArgs(stream, fields, opt)
+	if err != nil {
+		return "", err
+	}
+	return xaddID(c.Cmd("xadd", args...))
+}
+
+// xaddArgs returns the arguments of XADD.
+func xaddArgs(stream string, fields map[string]interface{}, opt XAddOptions) ([]interface{},
+	error) {
+	if len(fields) == 0 {
+		return nil, errors.New("stream entry has no fields")
+	}
+	args := []interface{}{stream}
+	if opt.NoMkStream {
+		args = append(args, "nomkstream")
+	}
+	args = append(args, opt.trimArgs()...)
+	if opt.ID != "" {
+		args = append(args, opt.ID)
+	} else {
+		args = append(args, "*")
+	}
+	for f, v := range fields {
+		args = append(args, f, v)
+	}
+	return args, nil
+}
+
+// xaddID returns the entry ID of an XADD reply.
+func xaddID(r *Reply) (string, error) {
+	if r.Type == NilReply {
+		return "", nil
+	}
+	return r.Str()
+}
+
+// XTrimOptions describes the options of XTrim.
+type XTrimOptions struct {
+	MaxLen int64  // Evict the oldest entries beyond this length, 0 disables
+	MinID  string // Evict entries with lower IDs, used if MaxLen is 0
+	Approx bool   // Trim approximately ("~"), which is much more efficient
+	Limit  int64  // Maximum number of entries evicted with Approx, 0 for the default
+}
+
+// args returns the trimming arguments of XADD and XTRIM.
+func (opt XTrimOptions) args() []interface{} {
+	var args []interface{}
+	if opt.MaxLen > 0 {
+		args = append(args, "maxlen")
+	} else if opt.MinID != "" {
+		args = append(args, "minid")
+	} else {
+		return nil
+	}
+	if opt.Approx {
+		args = append(args, "~")
+	}
+	if opt.MaxLen > 0 {
+		args = append(args, opt.MaxLen)
+	} else {
+		args = append(args, opt.MinID)
+	}
+	if opt.Approx && opt.Limit > 0 {
+		args = append(args, "limit", opt.Limit)
+	}
+	return args
+}
+
+// XTrim trims the given stream and returns the number of evicted entries.
+func (c *Client) XTrim(stream string, opt XTrimOptions) (int64, error) {
+	args := opt.args()
+	if args == nil {
+		return 0, errors.New("no trimming strategy")
+	}
+	return c.Cmd("xtrim", append([]interface{}{stream}, args...)...).Int64()
+}
+
+// XReadOptions describes the options of XRead.
+type XReadOptions struct {
+	Count int64         // Maximum number of entries per stream, 0 for no limit
+	Block time.Duration // Wait up to this long for new entries, 0 does not block
+}
+
+// XRead returns the entries of the given streams with IDs greater than the given ones,
+// by stream. Pass "$" as the ID to wait for entries added after the call.
+// If no entries arrive before the Block timeout, the result is empty. The read timeout of
+// the client is extended by the Block timeout.
+func (c *Client) XRead(streams map[string]string, opt XReadOptions) (map[string][]StreamEntry,
+	error) {
+	args := opt.args()
+	args = append(args, "streams")
+	keys := make([]interface{}, 0, len(streams))
+	ids := make([]interface{}, 0, len(streams))
+	for k, id := range streams {
+		keys = append(keys, k)
+		ids = append(ids, id)
+	}
+	args = append(append(args, keys...), ids...)
+	return parseXRead(c.readCmd(opt, "xread", args))
+}
+
+// readCmd calls the given XREAD or XREADGROUP command, extending the read timeout of the
+// client by the Block timeout of the given options.
+func (c *Client) readCmd(opt XReadOptions, cmd string, args []interface{}) *Reply {
+	if opt.Block > 0 {
+		return c.blockingCmd(context.Background(), opt.Block, cmd, args...)
+	}
+	return c.Cmd(cmd, args...)
+}
+
+func (opt XReadOptions) args() []interface{} {
+	var args []interface{}
+	if opt.Count > 0 {
+		args = append(args, "count", opt.Count)
+	}
+	if opt.Block > 0 {
+		ms := int64(opt.Block / time.Millisecond)
+		if ms == 0 {
+			ms = 1
+		}
+		args = append(args, "block", ms)
+	}
+	return args
+}
+
+// XRange returns the entries of the given stream with IDs between start and end,
+// inclusive, in ascending order. Use "-" and "+" for the smallest and greatest IDs,
+// and a count of 0 for no limit.
+func (c *Client) XRange(stream, start, end string, count int64) ([]StreamEntry, error) {
+	args := []interface{}{stream, start, end}
+	if count > 0 {
+		args = append(args, "count", count)
+	}
+	return parseStreamEntries(c.Cmd("xrange", args...))
+}
+
+// XRevRange is like XRange in descending order, starting with end.
+func (c *Client) XRevRange(stream, end, start string, count int64) ([]StreamEntry, error) {
+	args := []interface{}{stream, end, start}
+	if count > 0 {
+		args = append(args, "count", count)
+	}
+	return parseStreamEntries(c.Cmd("xrevrange", args...))
+}
+
+// XLen returns the number of entries of the given stream.
+func (c *Client) XLen(stream string) (int64, error) {
+	return c.Cmd("xlen", stream).Int64()
+}
+
+// XDel deletes the entries with the given IDs from the given stream and returns the
+// number of deleted entries.
+func (c *Client) XDel(stream string, ids ...string) (int64, error) {
+	return c.Cmd("xdel", stream, ids).Int64()
+}
+
+//* Parsing
+
+// parseStreamEntries parses a list of stream entries.
+func parseStreamEntries(r *Reply) ([]StreamEntry, error) {
+	if r.Type == ErrorReply {
+		return nil, r.Err
+	}
+	if r.Type == NilReply {
+		return nil, nil
+	}
+	if r.Type != MultiReply {
+		return nil, errors.New("reply type is not MultiReply")
+	}
+	entries := make([]StreamEntry, 0, len(r.Elems))
+	for _, e := range r.Elems {
+		if e.Type != MultiReply || len(e.Elems) != 2 {
+			return nil, ParseError
+		}
+		id, err := e.Elems[0].Str()
+		if err != nil {
+			return nil, err
+		}
+		entry := StreamEntry{ID: id}
+		if e.Elems[1].Type != NilReply {
+			if entry.Fields, err = e.Elems[1].Hash(); err != nil {
+				return nil, err
+			}
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// parseXRead parses an XREAD or XREADGROUP reply.
+func parseXRead(r *Reply) (map[string][]StreamEntry, error) {
+	streams := map[string][]StreamEntry{}
+	if r.Type == NilReply {
+		return streams, nil
+	}
+	if r.Type == ErrorReply {
+		return nil, r.Err
+	}
+	if r.Type != MultiReply {
+		return nil, errors.New("reply type is not MultiReply")
+	}
+	for _, s := range r.Elems {
+		if s.Type != MultiReply || len(s.Elems) != 2 {
+			return nil, ParseError
+		}
+		name, err := s.Elems[0].Str()
+		if err != nil {
+			return nil, err
+		}
+		if streams[name], err = parseStreamEntries(s.Elems[1]); err != nil {
+			return nil, err
+		}
+	}
+	return streams, nil
+}
@@ -0,0 +1,112 @@
+package redis
+
+import (
+	. "launchpad.net/gocheck"
+	"net"
+	"time"
+)
+
+type StreamSuite struct{}
+
+var _ = Suite(&StreamSuite{})
+
+// slowServer starts a server that answers its first connection with the given raw
+// reply after the given delay and returns its address.
+func slowServer(c *C, delay time.Duration, reply string) string {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	c.Assert(err, IsNil)
+	go func() {
+		defer l.Close()
+		conn, err := l.Accept()
+		if err != nil {
+			return
+		}
+		time.Sleep(delay)
+		conn.Write([]byte(reply))
+		fakeConns.Lock()
+		fakeConns.l = append(fakeConns.l, conn)
+		fakeConns.Unlock()
+	}()
+	return l.Addr().String()
+}
+
+func entry(id string, fields ...string) *Reply {
+	elems := make([]*Reply, len(fields))
+	for i, f := range fields {
+		elems[i] = bulk(f)
+	}
+	return multi(bulk(id), multi(elems...))
+}
+
+func (s *StreamSuite) TestStreamID(c *C) {
+	id, err := ParseStreamID("1526919030474-55")
+	c.Assert(err, IsNil)
+	c.Check(id, Equals, StreamID{1526919030474, 55})
+	c.Check(id.String(), Equals, "1526919030474-55")
+	c.Check(id.Next().String(), Equals, "1526919030474-56")
+	c.Check(StreamID{1, ^uint64(0)}.Next(), Equals, StreamID{2, 0})
+	c.Check(id.Time().UnixNano(), Equals, int64(1526919030474)*int64(time.Millisecond))
+	c.Check(StreamIDAt(id.Time()), Equals, StreamID{Ms: 1526919030474})
+
+	id, err = ParseStreamID("12")
+	c.Assert(err, IsNil)
+	c.Check(id, Equals, StreamID{Ms: 12})
+	_, err = ParseStreamID("x-1")
+	c.Check(err, ErrorMatches, `invalid stream ID "x-1"`)
+}
+
+func (s *StreamSuite) TestXAdd(c *C) {
+	cl, f := fakeClient("$3\r\n1-0\r\n")
+	id, err := cl.XAdd("s", map[string]interface{}{"a": 1},
+		XAddOptions{MaxLen: 1000, Approx: true, Limit: 10})
+	c.Assert(err, IsNil)
+	c.Check(id, Equals, "1-0")
+	c.Check(f.out.String(), Equals, "*10\r\n$4\r\nxadd\r\n$1\r\ns\r\n$6\r\nmaxlen\r\n$1\r\n~\r\n"+
+		"$4\r\n1000\r\n$5\r\nlimit\r\n$2\r\n10\r\n$1\r\n*\r\n$1\r\na\r\n$1\r\n1\r\n")
+
+	c.Check(XAddOptions{MinID: "5-0"}.trimArgs(), DeepEquals, []interface{}{"minid", "5-0"})
+	_, err = cl.XAdd("s", nil, XAddOptions{})
+	c.Check(err, NotNil)
+}
+
+func (s *StreamSuite) TestXRead(c *C) {
+	cl, f := fakeClient("*1\r\n*2\r\n$1\r\ns\r\n" +
+		"*1\r\n*2\r\n$3\r\n1-0\r\n*2\r\n$1\r\na\r\n$1\r\nb\r\n" +
+		"*-1\r\n")
+	opt := XReadOptions{Count: 1, Block: time.Second}
+	streams, err := cl.XRead(map[string]string{"s": "0"}, opt)
+	c.Assert(err, IsNil)
+	c.Check(streams, DeepEquals, map[string][]StreamEntry{
+		"s": {{ID: "1-0", Fields: map[string]string{"a": "b"}}},
+	})
+	c.Check(f.out.String(), Equals, "*8\r\n$5\r\nxread\r\n$5\r\ncount\r\n$1\r\n1\r\n"+
+		"$5\r\nblock\r\n$4\r\n1000\r\n$7\r\nstreams\r\n$1\r\ns\r\n$1\r\n0\r\n")
+
+	streams, err = cl.XRead(map[string]string{"s": "$"}, XReadOptions{Block: time.Second})
+	c.Assert(err, IsNil)
+	c.Check(streams, HasLen, 0)
+}
+
+func (s *StreamSuite) TestXReadBlockTimeout(c *C) {
+	// the reply arrives after the client timeout, but before the Block timeout
+	cl, err := DialTimeout("tcp", slowServer(c, 100*time.Millisecond, "*-1\r\n"),
+		30*time.Millisecond)
+	c.Assert(err, IsNil)
+	defer cl.Close()
+	streams, err := cl.XRead(map[string]string{"s": "$"}, XReadOptions{Block: time.Second})
+	c.Assert(err, IsNil)
+	c.Check(streams, HasLen, 0)
+	c.Check(cl.timeout, Equals, 30*time.Millisecond)
+}
+
+func (s *StreamSuite) TestParseStreamEntries(c *C) {
+	entries, err := parseStreamEntries(multi(entry("1-0", "a", "b"),
+		multi(bulk("2-0"), &Reply{Type: NilReply})))
+	c.Assert(err, IsNil)
+	c.Check(entries, DeepEquals, []StreamEntry{
+		{ID: "1-0", Fields: map[string]string{"a": "b"}},
+		{ID: "2-0"},
+	})
+	_, err = parseStreamEntries(multi(bulk("1-0")))
+	c.Check(err, Equals, ParseError)
+}
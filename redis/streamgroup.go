@@ -0,0 +1,255 @@
+package redis
+
+import (
+	"context"
+	"strings"
+	"time"
+)
+
+//* Consumer groups
+
+// XGroupCreate creates the given consumer group of the given stream, which delivers
+// the entries after the given ID ("$" for new entries only, "0" for all entries).
+// With mkstream, the stream is created if it does not exist.
+func (c *Client) XGroupCreate(stream, group, start string, mkstream bool) error {
+	if mkstream {
+		return c.Cmd("xgroup", "create", stream, group, start, "mkstream").Err
+	}
+	return c.Cmd("xgroup", "create", stream, group, start).Err
+}
+
+// XReadGroup reads entries of the given streams as the given consumer of the given group.
+// Pass ">" as the ID to read entries never delivered to other consumers, or another ID
+// to read the entries pending for the consumer after it.
+// If no entries arrive before the Block timeout, the result is empty. The read timeout of
+// the client is extended by the Block timeout.
+func (c *Client) XReadGroup(group, consumer string, streams map[string]string,
+	opt XReadOptions) (map[string][]StreamEntry, error) {
+	args := append([]interface{}{"group", group, consumer}, opt.args()...)
+	args = append(args, "streams")
+	keys := make([]interface{}, 0, len(streams))
+	ids := make([]interface{}, 0, len(streams))
+	for k, id := range streams {
+		keys = append(keys, k)
+		ids = append(ids, id)
+	}
+	args = append(append(args, keys...), ids...)
+	return parseXRead(c.readCmd(opt, "xreadgroup", args))
+}
+
+// XAck acknowledges the entries with the given IDs for the given group and returns
+// the number of acknowledged entries.
+func (c *Client) XAck(stream, group string, ids ...string) (int64, error) {
+	return c.Cmd("xack", stream, group, ids).Int64()
+}
+
+// XAutoClaim transfers up to count entries of the given group that have been pending
+// for at least minIdle, starting at the given ID, to the given consumer (Redis 6.2 or
+// later). It returns the claimed entries and the ID to continue with, "0-0" once all
+// pending entries have been scanned.
+func (c *Client) XAutoClaim(stream, group, consumer string, minIdle time.Duration,
+	start string, count int64) (next string, entries []StreamEntry, err error) {
+	r := c.cmdSince(Version{6, 2, 0}, "xautoclaim", stream, group, consumer,
+		int64(minIdle/time.Millisecond), start, "count", count)
+	if r.Type == ErrorReply {
+		return "", nil, r.Err
+	}
+	if r.Type != MultiReply || len(r.Elems) < 2 {
+		return "", nil, ParseError
+	}
+	if next, err = r.Elems[0].Str(); err != nil {
+		return "", nil, err
+	}
+	entries, err = parseStreamEntries(r.Elems[1])
+	return next, entries, err
+}
+
+// StreamHandler processes an entry of the given stream.
+// Entries are acknowledged if the handler returns nil.
+type StreamHandler func(stream string, e StreamEntry) error
+
+// ConsumerGroup reads the entries of a stream as a consumer of a consumer group and
+// dispatches them to a handler.
+// Entries that the handler fails to process stay pending and are delivered again,
+// either by claiming, see ClaimIdle, or when the consumer restarts, as it reads its own
+// pending entries first.
+//
+// Entries that fail MaxDeliveries deliveries are moved to the DeadLetter stream instead:
+// they are added there with their fields and the fields "source-stream", "source-id",
+// "consumer", "deliveries" and "error", and acknowledged in the group.
+// Like Client, ConsumerGroup is not safe for concurrent use.
+type ConsumerGroup struct {
+	Count         int64         // Entries read at a time, 0 means 10
+	Block         time.Duration // Wait for new entries up to this long per read, 0 means 1 second
+	ClaimIdle     time.Duration // Claim entries pending this long with others, 0 disables
+	MaxDeliveries int64         // Deliveries before dead-lettering, 0 disables
+	DeadLetter    string        // Dead-letter stream, empty means the stream name + ":dead"
+	DedupTTL      time.Duration // How long committed entries are remembered, 0 means 1 day
+	DedupField    string        // Field identifying duplicate entries, empty means the ID
+	c             *Client
+	stream        string
+	group         string
+	consumer      string
+	history       bool   // whether the own pending entries are being read
+	lastID        string // last own pending entry read
+	cursor        string // XAUTOCLAIM cursor
+}
+
+// NewConsumerGroup returns a ConsumerGroup reading the given stream as the given
+// consumer of the given group. The group and the stream are created if needed,
+// with the group starting at the end of the stream.
+func NewConsumerGroup(c *Client, stream, group, consumer string) (*ConsumerGroup, error) {
+	err := c.XGroupCreate(stream, group, "$", true)
+	if err != nil && !strings.HasPrefix(err.Error(), "BUSYGROUP ") {
+		return nil, err
+	}
+	return &ConsumerGroup{
+		c:        c,
+		stream:   stream,
+		group:    group,
+		consumer: consumer,
+		history:  true,
+		lastID:   "0",
+		cursor:   "0-0",
+	}, nil
+}
+
+// Poll claims idle entries of other consumers, reads entries, dispatches them to the
+// given handler and acknowledges the entries that were processed.
+// It returns the number of dispatched entries.
+func (g *ConsumerGroup) Poll(h StreamHandler) (int, error) {
+	n := 0
+	if g.ClaimIdle > 0 {
+		next, entries, err := g.c.XAutoClaim(g.stream, g.group, g.consumer, g.ClaimIdle,
+			g.cursor, g.count())
+		if err != nil {
+			return n, err
+		}
+		g.cursor = next
+		if err = g.dispatch(h, entries); err != nil {
+			return n, err
+		}
+		n += len(entries)
+	}
+
+	var entries []StreamEntry
+	var err error
+	if g.history {
+		entries, err = g.read(g.lastID, 0)
+		if err == nil && len(entries) == 0 {
+			g.history = false
+		} else if err == nil {
+			g.lastID = entries[len(entries)-1].ID
+		}
+	}
+	if err == nil && !g.history {
+		block := g.Block
+		if block <= 0 {
+			block = time.Second
+		}
+		entries, err = g.read(">", block)
+	}
+	if err != nil {
+		return n, err
+	}
+	return n + len(entries), g.dispatch(h, entries)
+}
+
+// Run polls until the given context is done or Poll fails, see Poll.
+// The context is checked between reads, so Run may return up to Block after the context
+// is done. The timeout of the client must be longer than Block.
+func (g *ConsumerGroup) Run(ctx context.Context, h StreamHandler) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+		}
+		if _, err := g.Poll(h); err != nil {
+			return err
+		}
+	}
+}
+
+func (g *ConsumerGroup) count() int64 {
+	if g.Count <= 0 {
+		return 10
+	}
+	return g.Count
+}
+
+// read reads the entries of the stream after the given ID.
+func (g *ConsumerGroup) read(id string, block time.Duration) ([]StreamEntry, error) {
+	streams, err := g.c.XReadGroup(g.group, g.consumer, map[string]string{g.stream: id},
+		XReadOptions{Count: g.count(), Block: block})
+	if err != nil {
+		return nil, err
+	}
+	return streams[g.stream], nil
+}
+
+// dispatch passes the given entries to the handler and acknowledges the processed ones.
+// Entries deleted from the stream while pending are acknowledged without processing.
+func (g *ConsumerGroup) dispatch(h StreamHandler, entries []StreamEntry) error {
+	var ack []string
+	for _, e := range entries {
+		if e.Fields == nil {
+			ack = append(ack, e.ID)
+			continue
+		}
+		herr := h(g.stream, e)
+		if herr == committedError {
+			continue
+		}
+		if herr == nil {
+			ack = append(ack, e.ID)
+			continue
+		}
+		if g.MaxDeliveries <= 0 {
+			continue
+		}
+		dead, err := g.deadLetter(e, herr)
+		if err != nil {
+			return err
+		}
+		if dead {
+			ack = append(ack, e.ID)
+		}
+	}
+	if len(ack) == 0 {
+		return nil
+	}
+	_, err := g.c.XAck(g.stream, g.group, ack...)
+	return err
+}
+
+// deadLetter adds the given entry, which failed with the given error, to the dead-letter
+// stream if it has been delivered MaxDeliveries times, and returns true if it did.
+func (g *ConsumerGroup) deadLetter(e StreamEntry, herr error) (bool, error) {
+	pending, err := g.c.XPendingExt(g.stream, g.group, XPendingOptions{Start: e.ID, End: e.ID,
+		Count: 1})
+	if err != nil || len(pending) == 0 {
+		// no longer pending, e.g. claimed by another consumer
+		return false, err
+	}
+	n := pending[0].Deliveries
+	if n < g.MaxDeliveries {
+		return false, nil
+	}
+
+	fields := make(map[string]interface{}, len(e.Fields)+5)
+	for k, v := range e.Fields {
+		fields[k] = v
+	}
+	fields["source-stream"] = g.stream
+	fields["source-id"] = e.ID
+	fields["consumer"] = g.consumer
+	fields["deliveries"] = n
+	fields["error"] = herr.Error()
+	dl := g.DeadLetter
+	if dl == "" {
+		dl = g.stream + ":dead"
+	}
+	_, err = g.c.XAdd(dl, fields, XAddOptions{})
+	return err == nil, err
+}
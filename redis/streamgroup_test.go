@@ -0,0 +1,102 @@
+package redis
+
+import (
+	"errors"
+	. "launchpad.net/gocheck"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type StreamGroupSuite struct{}
+
+var _ = Suite(&StreamGroupSuite{})
+
+func (s *StreamGroupSuite) TestPoll(c *C) {
+	cl, f := fakeClient("-BUSYGROUP Consumer Group name already exists\r\n" +
+		// own pending entries, then none left
+		"*1\r\n*2\r\n$1\r\ns\r\n*1\r\n*2\r\n$3\r\n1-0\r\n*2\r\n$1\r\na\r\n$1\r\n1\r\n" +
+		":1\r\n" +
+		"*1\r\n*2\r\n$1\r\ns\r\n*0\r\n" +
+		// new entries
+		"*1\r\n*2\r\n$1\r\ns\r\n*2\r\n" +
+		"*2\r\n$3\r\n2-0\r\n*2\r\n$1\r\na\r\n$1\r\n2\r\n" +
+		"*2\r\n$3\r\n3-0\r\n*2\r\n$1\r\na\r\n$3\r\nbad\r\n" +
+		":1\r\n")
+	g, err := NewConsumerGroup(cl, "s", "g", "c1")
+	c.Assert(err, IsNil)
+
+	var seen []string
+	h := func(stream string, e StreamEntry) error {
+		seen = append(seen, e.ID)
+		if e.Fields["a"] == "bad" {
+			return errors.New("bad entry")
+		}
+		return nil
+	}
+	n, err := g.Poll(h)
+	c.Assert(err, IsNil)
+	c.Check(n, Equals, 1)
+	n, err = g.Poll(h)
+	c.Assert(err, IsNil)
+	c.Check(n, Equals, 2)
+	c.Check(seen, DeepEquals, []string{"1-0", "2-0", "3-0"})
+
+	out := f.out.String()
+	c.Check(strings.Count(out, "$4\r\nxack\r\n$1\r\ns\r\n$1\r\ng\r\n$3\r\n1-0\r\n"), Equals, 1)
+	c.Check(strings.Count(out, "$4\r\nxack\r\n$1\r\ns\r\n$1\r\ng\r\n$3\r\n2-0\r\n"), Equals, 1)
+	c.Check(strings.Contains(out, "3-0"), Equals, false)
+	c.Check(strings.Count(out, "$1\r\n>\r\n"), Equals, 1)
+}
+
+func (s *StreamGroupSuite) TestXReadGroupBlockTimeout(c *C) {
+	cl, err := DialTimeout("tcp", slowServer(c, 100*time.Millisecond, "*-1\r\n"),
+		30*time.Millisecond)
+	c.Assert(err, IsNil)
+	defer cl.Close()
+	streams, err := cl.XReadGroup("g", "c1", map[string]string{"s": ">"},
+		XReadOptions{Block: time.Second})
+	c.Assert(err, IsNil)
+	c.Check(streams, HasLen, 0)
+}
+
+func (s *StreamGroupSuite) TestClaim(c *C) {
+	cl, f := fakeClient("+OK\r\n" +
+		"*3\r\n$3\r\n0-0\r\n*1\r\n*2\r\n$3\r\n1-0\r\n*2\r\n$1\r\na\r\n$1\r\n1\r\n*0\r\n" +
+		":1\r\n" +
+		"*1\r\n*2\r\n$1\r\ns\r\n*0\r\n" +
+		"*-1\r\n")
+	g, err := NewConsumerGroup(cl, "s", "g", "c1")
+	c.Assert(err, IsNil)
+	g.ClaimIdle = time.Minute
+	g.Block = time.Millisecond
+	n, err := g.Poll(func(string, StreamEntry) error { return nil })
+	c.Assert(err, IsNil)
+	c.Check(n, Equals, 1)
+	c.Check(strings.Contains(f.out.String(), "$10\r\nxautoclaim\r\n$1\r\ns\r\n$1\r\ng\r\n"+
+		"$2\r\nc1\r\n$5\r\n60000\r\n$3\r\n0-0\r\n$5\r\ncount\r\n$2\r\n10\r\n"), Equals, true)
+}
+
+func (s *StreamGroupSuite) TestDeadLetter(c *C) {
+	pending := func(n int) string {
+		return "*1\r\n*4\r\n$3\r\n1-0\r\n$2\r\nc1\r\n:10\r\n:" + strconv.Itoa(n) + "\r\n"
+	}
+	read := "*1\r\n*2\r\n$1\r\ns\r\n*1\r\n*2\r\n$3\r\n1-0\r\n*2\r\n$1\r\na\r\n$1\r\n1\r\n"
+	cl, f := fakeClient("+OK\r\n" + read + pending(1) + read + pending(2) +
+		"$3\r\n9-0\r\n:1\r\n")
+	g, err := NewConsumerGroup(cl, "s", "g", "c1")
+	c.Assert(err, IsNil)
+	g.MaxDeliveries = 2
+	h := func(string, StreamEntry) error { return errors.New("boom") }
+	_, err = g.Poll(h)
+	c.Assert(err, IsNil)
+	c.Check(strings.Contains(f.out.String(), "xack"), Equals, false)
+	_, err = g.Poll(h)
+	c.Assert(err, IsNil)
+
+	out := f.out.String()
+	c.Check(strings.Contains(out, "$4\r\nxadd\r\n$6\r\ns:dead\r\n$1\r\n*\r\n"), Equals, true)
+	c.Check(strings.Contains(out, "$5\r\nerror\r\n$4\r\nboom\r\n"), Equals, true)
+	ack := "$4\r\nxack\r\n$1\r\ns\r\n$1\r\ng\r\n$3\r\n1-0\r\n"
+	c.Check(strings.HasSuffix(out, ack), Equals, true)
+}
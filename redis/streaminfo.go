@@ -0,0 +1,298 @@
+package redis
+
+import (
+	"errors"
+	"time"
+)
+
+//* Stream introspection
+
+// StreamInfo describes the output of XINFO STREAM.
+type StreamInfo struct {
+	Length            int64
+	RadixTreeKeys     int64
+	RadixTreeNodes    int64
+	Groups            int64
+	LastGeneratedID   string
+	MaxDeletedEntryID string // Redis 7.0 or later
+	EntriesAdded      int64  // Redis 7.0 or later
+	FirstEntry        *StreamEntry
+	LastEntry         *StreamEntry
+}
+
+// StreamGroupInfo describes a consumer group in XINFO GROUPS.
+type StreamGroupInfo struct {
+	Name            string
+	Consumers       int64
+	Pending         int64 // Entries delivered but not acknowledged
+	LastDeliveredID string
+	EntriesRead     int64 // Redis 7.0 or later
+	Lag             int64 // Entries not delivered yet (Redis 7.0 or later), -1 if unknown
+}
+
+// StreamConsumerInfo describes a consumer in XINFO CONSUMERS.
+type StreamConsumerInfo struct {
+	Name     string
+	Pending  int64
+	Idle     time.Duration // Since the last attempted interaction
+	Inactive time.Duration // Since the last successful interaction (Redis 7.2), -1 if unknown
+}
+
+// XPendingSummary describes the summary form of XPENDING.
+type XPendingSummary struct {
+	Count     int64
+	Lowest    string           // Lowest pending ID, empty if there are none
+	Highest   string           // Highest pending ID, empty if there are none
+	Consumers map[string]int64 // Pending entries by consumer
+}
+
+// XPendingEntry describes a pending entry in the extended form of XPENDING.
+type XPendingEntry struct {
+	ID         string
+	Consumer   string
+	Idle       time.Duration // Since the last delivery
+	Deliveries int64
+}
+
+// XPendingOptions describes the options of XPendingExt.
+type XPendingOptions struct {
+	Start    string        // Empty means "-"
+	End      string        // Empty means "+"
+	Count    int64         // 0 means 10
+	Consumer string        // Only entries of this consumer, empty for all consumers
+	Idle     time.Duration // Only entries idle at least this long, 0 for all entries
+}
+
+// XInfoStream returns information about the given stream.
+func (c *Client) XInfoStream(stream string) (*StreamInfo, error) {
+	return parseXInfoStream(c.Cmd("xinfo", "stream", stream))
+}
+
+// XInfoGroups returns the consumer groups of the given stream.
+func (c *Client) XInfoGroups(stream string) ([]StreamGroupInfo, error) {
+	return parseXInfoGroups(c.Cmd("xinfo", "groups", stream))
+}
+
+// XInfoConsumers returns the consumers of the given group.
+func (c *Client) XInfoConsumers(stream, group string) ([]StreamConsumerInfo, error) {
+	return parseXInfoConsumers(c.Cmd("xinfo", "consumers", stream, group))
+}
+
+// XPending returns the summary of the pending entries of the given group.
+func (c *Client) XPending(stream, group string) (*XPendingSummary, error) {
+	return parseXPending(c.Cmd("xpending", stream, group))
+}
+
+// XPendingExt returns the pending entries of the given group.
+func (c *Client) XPendingExt(stream, group string, opt XPendingOptions) ([]XPendingEntry,
+	error) {
+	args := []interface{}{stream, group}
+	if opt.Idle > 0 {
+		args = append(args, "idle", int64(opt.Idle/time.Millisecond))
+	}
+	start, end, count := opt.Start, opt.End, opt.Count
+	if start == "" {
+		start = "-"
+	}
+	if end == "" {
+		end = "+"
+	}
+	if count <= 0 {
+		count = 10
+	}
+	args = append(args, start, end, count)
+	if opt.Consumer != "" {
+		args = append(args, opt.Consumer)
+	}
+	return parseXPendingExt(c.Cmd("xpending", args...))
+}
+
+//* Parsing
+
+// pairInt returns the integer value of the given key of a pairs map, or def if it is
+// missing or nil.
+func pairInt(m map[string]*Reply, key string, def int64) (int64, error) {
+	v, ok := m[key]
+	if !ok || v.Type == NilReply {
+		return def, nil
+	}
+	return v.Int64()
+}
+
+// parseXInfoStream parses an XINFO STREAM reply.
+func parseXInfoStream(r *Reply) (*StreamInfo, error) {
+	m, err := r.pairs()
+	if err != nil {
+		return nil, err
+	}
+	info := &StreamInfo{
+		LastGeneratedID:   pairStr(m, "last-generated-id"),
+		MaxDeletedEntryID: pairStr(m, "max-deleted-entry-id"),
+	}
+	ints := []struct {
+		key string
+		v   *int64
+	}{
+		{"length", &info.Length},
+		{"radix-tree-keys", &info.RadixTreeKeys},
+		{"radix-tree-nodes", &info.RadixTreeNodes},
+		{"groups", &info.Groups},
+		{"entries-added", &info.EntriesAdded},
+	}
+	for _, i := range ints {
+		if *i.v, err = pairInt(m, i.key, 0); err != nil {
+			return nil, err
+		}
+	}
+	for key, p := range map[string]**StreamEntry{
+		"first-entry": &info.FirstEntry, "last-entry": &info.LastEntry} {
+		if v, ok := m[key]; ok && v.Type == MultiReply {
+			entries, err := parseStreamEntries(multiOf(v))
+			if err != nil {
+				return nil, err
+			}
+			*p = &entries[0]
+		}
+	}
+	return info, nil
+}
+
+// multiOf returns a multi bulk reply with the given reply as its only element.
+func multiOf(r *Reply) *Reply {
+	return &Reply{Type: MultiReply, Elems: []*Reply{r}}
+}
+
+// parseXInfoGroups parses an XINFO GROUPS reply.
+func parseXInfoGroups(r *Reply) ([]StreamGroupInfo, error) {
+	if r.Type == ErrorReply {
+		return nil, r.Err
+	}
+	if r.Type != MultiReply {
+		return nil, errors.New("reply type is not MultiReply")
+	}
+	groups := make([]StreamGroupInfo, 0, len(r.Elems))
+	for _, e := range r.Elems {
+		m, err := e.pairs()
+		if err != nil {
+			return nil, err
+		}
+		g := StreamGroupInfo{
+			Name:            pairStr(m, "name"),
+			LastDeliveredID: pairStr(m, "last-delivered-id"),
+		}
+		if g.Consumers, err = pairInt(m, "consumers", 0); err != nil {
+			return nil, err
+		}
+		if g.Pending, err = pairInt(m, "pending", 0); err != nil {
+			return nil, err
+		}
+		if g.EntriesRead, err = pairInt(m, "entries-read", 0); err != nil {
+			return nil, err
+		}
+		if g.Lag, err = pairInt(m, "lag", -1); err != nil {
+			return nil, err
+		}
+		groups = append(groups, g)
+	}
+	return groups, nil
+}
+
+// parseXInfoConsumers parses an XINFO CONSUMERS reply.
+func parseXInfoConsumers(r *Reply) ([]StreamConsumerInfo, error) {
+	if r.Type == ErrorReply {
+		return nil, r.Err
+	}
+	if r.Type != MultiReply {
+		return nil, errors.New("reply type is not MultiReply")
+	}
+	consumers := make([]StreamConsumerInfo, 0, len(r.Elems))
+	for _, e := range r.Elems {
+		m, err := e.pairs()
+		if err != nil {
+			return nil, err
+		}
+		cons := StreamConsumerInfo{Name: pairStr(m, "name")}
+		if cons.Pending, err = pairInt(m, "pending", 0); err != nil {
+			return nil, err
+		}
+		idle, err := pairInt(m, "idle", 0)
+		if err != nil {
+			return nil, err
+		}
+		inactive, err := pairInt(m, "inactive", -1)
+		if err != nil {
+			return nil, err
+		}
+		cons.Idle = time.Duration(idle) * time.Millisecond
+		cons.Inactive = -1
+		if inactive >= 0 {
+			cons.Inactive = time.Duration(inactive) * time.Millisecond
+		}
+		consumers = append(consumers, cons)
+	}
+	return consumers, nil
+}
+
+// parseXPending parses the summary form of an XPENDING reply.
+func parseXPending(r *Reply) (*XPendingSummary, error) {
+	if r.Type == ErrorReply {
+		return nil, r.Err
+	}
+	if r.Type != MultiReply || len(r.Elems) != 4 {
+		return nil, ParseError
+	}
+	n, err := r.Elems[0].Int64()
+	if err != nil {
+		return nil, err
+	}
+	s := &XPendingSummary{Count: n, Consumers: map[string]int64{}}
+	s.Lowest, _ = r.Elems[1].Str()
+	s.Highest, _ = r.Elems[2].Str()
+	for _, e := range r.Elems[3].Elems {
+		if e.Type != MultiReply || len(e.Elems) != 2 {
+			return nil, ParseError
+		}
+		name, err := e.Elems[0].Str()
+		if err != nil {
+			return nil, err
+		}
+		if s.Consumers[name], err = e.Elems[1].Int64(); err != nil {
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+// parseXPendingExt parses the extended form of an XPENDING reply.
+func parseXPendingExt(r *Reply) ([]XPendingEntry, error) {
+	if r.Type == ErrorReply {
+		return nil, r.Err
+	}
+	if r.Type != MultiReply {
+		return nil, errors.New("reply type is not MultiReply")
+	}
+	entries := make([]XPendingEntry, 0, len(r.Elems))
+	for _, e := range r.Elems {
+		if e.Type != MultiReply || len(e.Elems) != 4 {
+			return nil, ParseError
+		}
+		var p XPendingEntry
+		var err error
+		if p.ID, err = e.Elems[0].Str(); err != nil {
+			return nil, err
+		}
+		if p.Consumer, err = e.Elems[1].Str(); err != nil {
+			return nil, err
+		}
+		idle, err := e.Elems[2].Int64()
+		if err != nil {
+			return nil, err
+		}
+		p.Idle = time.Duration(idle) * time.Millisecond
+		if p.Deliveries, err = e.Elems[3].Int64(); err != nil {
+			return nil, err
+		}
+		entries = append(entries, p)
+	}
+	return entries, nil
+}
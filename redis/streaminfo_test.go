@@ -0,0 +1,69 @@
+package redis
+
+import (
+	. "launchpad.net/gocheck"
+	"time"
+)
+
+type StreamInfoSuite struct{}
+
+var _ = Suite(&StreamInfoSuite{})
+
+func (s *StreamInfoSuite) TestXInfoStream(c *C) {
+	info, err := parseXInfoStream(multi(
+		bulk("length"), integer(2),
+		bulk("radix-tree-keys"), integer(1),
+		bulk("radix-tree-nodes"), integer(2),
+		bulk("last-generated-id"), bulk("2-0"),
+		bulk("groups"), integer(1),
+		bulk("first-entry"), entry("1-0", "a", "1"),
+		bulk("last-entry"), entry("2-0", "a", "2"),
+	))
+	c.Assert(err, IsNil)
+	c.Check(info, DeepEquals, &StreamInfo{
+		Length:          2,
+		RadixTreeKeys:   1,
+		RadixTreeNodes:  2,
+		Groups:          1,
+		LastGeneratedID: "2-0",
+		FirstEntry:      &StreamEntry{"1-0", map[string]string{"a": "1"}},
+		LastEntry:       &StreamEntry{"2-0", map[string]string{"a": "2"}},
+	})
+}
+
+func (s *StreamInfoSuite) TestXInfoGroups(c *C) {
+	groups, err := parseXInfoGroups(multi(multi(
+		bulk("name"), bulk("g"),
+		bulk("consumers"), integer(2),
+		bulk("pending"), integer(3),
+		bulk("last-delivered-id"), bulk("5-0"),
+		bulk("entries-read"), integer(5),
+		bulk("lag"), &Reply{Type: NilReply},
+	)))
+	c.Assert(err, IsNil)
+	c.Check(groups, DeepEquals, []StreamGroupInfo{{"g", 2, 3, "5-0", 5, -1}})
+}
+
+func (s *StreamInfoSuite) TestXInfoConsumers(c *C) {
+	consumers, err := parseXInfoConsumers(multi(multi(
+		bulk("name"), bulk("c1"),
+		bulk("pending"), integer(1),
+		bulk("idle"), integer(1500),
+	)))
+	c.Assert(err, IsNil)
+	c.Check(consumers, DeepEquals, []StreamConsumerInfo{{"c1", 1, 1500 * time.Millisecond, -1}})
+}
+
+func (s *StreamInfoSuite) TestXPending(c *C) {
+	sum, err := parseXPending(multi(integer(3), bulk("1-0"), bulk("3-0"),
+		multi(multi(bulk("c1"), bulk("2")), multi(bulk("c2"), bulk("1")))))
+	c.Assert(err, IsNil)
+	c.Check(sum, DeepEquals, &XPendingSummary{3, "1-0", "3-0", map[string]int64{"c1": 2, "c2": 1}})
+
+	cl, f := fakeClient("*1\r\n*4\r\n$3\r\n1-0\r\n$2\r\nc1\r\n:20\r\n:4\r\n")
+	pending, err := cl.XPendingExt("s", "g", XPendingOptions{Idle: time.Second, Consumer: "c1"})
+	c.Assert(err, IsNil)
+	c.Check(pending, DeepEquals, []XPendingEntry{{"1-0", "c1", 20 * time.Millisecond, 4}})
+	c.Check(f.out.String(), Equals, "*9\r\n$8\r\nxpending\r\n$1\r\ns\r\n$1\r\ng\r\n"+
+		"$4\r\nidle\r\n$4\r\n1000\r\n$1\r\n-\r\n$1\r\n+\r\n$2\r\n10\r\n$2\r\nc1\r\n")
+}
@@ -0,0 +1,85 @@
+package redis
+
+//* Stream iteration
+
+// StreamIterator iterates over the entries of a stream range, fetching them in batches.
+//
+//	it := c.XRangeIter("events", "-", "+", 100)
+//	for it.Next() {
+//		fmt.Println(it.Val().ID)
+//	}
+//	if err := it.Err(); err != nil {
+//		...
+//	}
+type StreamIterator struct {
+	c      *Client
+	cmd    string
+	stream string
+	start  string
+	from   string // next start of the range, exclusive after the first batch
+	to     string
+	batch  int64
+	buf    []StreamEntry
+	val    StreamEntry
+	err    error
+	done   bool
+}
+
+// XRangeIter returns an iterator over the entries of the given stream with IDs between
+// start and end, inclusive, in ascending order, fetching batch entries at a time
+// (0 means 100). Batches after the first one use exclusive ranges (Redis 6.2 or later).
+func (c *Client) XRangeIter(stream, start, end string, batch int64) *StreamIterator {
+	return newStreamIterator(c, "xrange", stream, start, end, batch)
+}
+
+// XRevRangeIter is like XRangeIter in descending order, starting with end.
+func (c *Client) XRevRangeIter(stream, end, start string, batch int64) *StreamIterator {
+	return newStreamIterator(c, "xrevrange", stream, end, start, batch)
+}
+
+func newStreamIterator(c *Client, cmd, stream, from, to string, batch int64) *StreamIterator {
+	if batch <= 0 {
+		batch = 100
+	}
+	return &StreamIterator{c: c, cmd: cmd, stream: stream, start: from, from: from, to: to,
+		batch: batch}
+}
+
+// Next advances the iterator to the next entry and returns false
+// when the iteration is over or an error occurred.
+func (it *StreamIterator) Next() bool {
+	for len(it.buf) == 0 {
+		if it.done || it.err != nil {
+			return false
+		}
+		it.buf, it.err = parseStreamEntries(it.c.Cmd(it.cmd, it.stream, it.from, it.to,
+			"count", it.batch))
+		if int64(len(it.buf)) < it.batch {
+			it.done = true
+		}
+		if len(it.buf) > 0 {
+			it.from = "(" + it.buf[len(it.buf)-1].ID
+		}
+	}
+	it.val, it.buf = it.buf[0], it.buf[1:]
+	return true
+}
+
+// Val returns the current entry.
+func (it *StreamIterator) Val() StreamEntry {
+	return it.val
+}
+
+// Err returns the error that ended the iteration, if any.
+func (it *StreamIterator) Err() error {
+	return it.err
+}
+
+// Cursor returns the start of the rest of the range, after the current entry.
+// Passing it as the start of a new iterator resumes the iteration.
+func (it *StreamIterator) Cursor() string {
+	if it.val.ID == "" {
+		return it.start
+	}
+	return "(" + it.val.ID
+}
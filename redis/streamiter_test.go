@@ -0,0 +1,33 @@
+package redis
+
+import (
+	. "launchpad.net/gocheck"
+	"strings"
+)
+
+type StreamIterSuite struct{}
+
+var _ = Suite(&StreamIterSuite{})
+
+func (s *StreamIterSuite) TestXRangeIter(c *C) {
+	cl, f := fakeClient("*2\r\n" +
+		"*2\r\n$3\r\n1-0\r\n*2\r\n$1\r\na\r\n$1\r\n1\r\n" +
+		"*2\r\n$3\r\n2-0\r\n*2\r\n$1\r\na\r\n$1\r\n2\r\n" +
+		"*1\r\n" +
+		"*2\r\n$3\r\n3-0\r\n*2\r\n$1\r\na\r\n$1\r\n3\r\n")
+	it := cl.XRangeIter("s", "-", "+", 2)
+	c.Check(it.Cursor(), Equals, "-")
+	var ids []string
+	for it.Next() {
+		ids = append(ids, it.Val().ID)
+	}
+	c.Assert(it.Err(), IsNil)
+	c.Check(ids, DeepEquals, []string{"1-0", "2-0", "3-0"})
+	c.Check(it.Cursor(), Equals, "(3-0")
+	c.Check(strings.Contains(f.out.String(), "$4\r\n(2-0\r\n$1\r\n+\r\n"), Equals, true)
+
+	cl, _ = fakeClient("-ERR no such key\r\n")
+	it = cl.XRevRangeIter("s", "+", "-", 0)
+	c.Check(it.Next(), Equals, false)
+	c.Check(it.Err(), ErrorMatches, "ERR no such key")
+}
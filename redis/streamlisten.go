@@ -0,0 +1,117 @@
+package redis
+
+import (
+	"context"
+	"time"
+)
+
+//* Stream listening
+
+// Streams reads streams with blocking XREAD calls on a dedicated connection.
+type Streams struct {
+	Count   int64         // Entries read at a time per stream, 0 for no limit
+	Block   time.Duration // Wait for new entries up to this long per read, 0 means 5 seconds
+	Backoff time.Duration // Delay before reconnecting after a failure, 0 means 1 second
+	// Password, if set, is sent with AUTH on each new connection.
+	Password string
+	network  string
+	addr     string
+	timeout  time.Duration
+}
+
+// NewStreams returns a Streams reading from the given server with the given connection
+// timeout. The timeout must be longer than Block, or 0.
+func NewStreams(network, addr string, timeout time.Duration) *Streams {
+	return &Streams{network: network, addr: addr, timeout: timeout}
+}
+
+// Listen reads the entries of the given streams after the given IDs by stream name
+// ("$" for new entries only) and passes them to the given handler, until the given
+// context is done or the handler returns an error.
+// Listen returns the error of the handler or the error of the context.
+// Done contexts interrupt blocking reads.
+// On connection failures, Listen reconnects after Backoff and continues after the last
+// entry passed to the handler. Entries added during a failure are lost for streams read
+// from "$" that have not delivered an entry yet.
+func (s *Streams) Listen(ctx context.Context, streams map[string]string, h StreamHandler) error {
+	ids := make(map[string]string, len(streams))
+	for k, id := range streams {
+		ids[k] = id
+	}
+	block := s.Block
+	if block <= 0 {
+		block = 5 * time.Second
+	}
+	backoff := s.Backoff
+	if backoff <= 0 {
+		backoff = time.Second
+	}
+
+	for {
+		c, err := s.dial()
+		if err == nil {
+			err = s.read(ctx, c, ids, XReadOptions{Count: s.Count, Block: block}, h)
+			c.Close()
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if err != nil && !isConnError(err) {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+	}
+}
+
+// dial connects and authenticates.
+func (s *Streams) dial() (*Client, error) {
+	c, err := DialTimeout(s.network, s.addr, s.timeout)
+	if err != nil {
+		return nil, err
+	}
+	if s.Password != "" {
+		if r := c.Cmd("auth", s.Password); r.Type == ErrorReply {
+			c.Close()
+			return nil, r.Err
+		}
+	}
+	return c, nil
+}
+
+// read reads entries with the given client until an error occurs.
+// Done contexts close the connection to interrupt blocking reads.
+func (s *Streams) read(ctx context.Context, c *Client, ids map[string]string,
+	opt XReadOptions, h StreamHandler) error {
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			c.Close()
+		case <-done:
+		}
+	}()
+
+	for {
+		streams, err := c.XRead(ids, opt)
+		if err != nil {
+			return err
+		}
+		for k, entries := range streams {
+			for _, e := range entries {
+				if err = h(k, e); err != nil {
+					return err
+				}
+				ids[k] = e.ID
+			}
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+	}
+}
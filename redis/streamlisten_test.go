@@ -0,0 +1,63 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	. "launchpad.net/gocheck"
+	"net"
+	"time"
+)
+
+type StreamListenSuite struct{}
+
+var _ = Suite(&StreamListenSuite{})
+
+const xreadReply = "*1\r\n*2\r\n$1\r\ns\r\n*1\r\n*2\r\n$3\r\n1-0\r\n*2\r\n$1\r\na\r\n$1\r\nb\r\n"
+
+func (s *StreamListenSuite) TestCancel(c *C) {
+	// the second read blocks forever
+	addr := fakeServer(c, xreadReply)
+	st := NewStreams("tcp", addr, 0)
+	ctx, cancel := context.WithCancel(context.Background())
+	var ids []string
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		cancel()
+	}()
+	err := st.Listen(ctx, map[string]string{"s": "$"}, func(stream string, e StreamEntry) error {
+		ids = append(ids, e.ID)
+		return nil
+	})
+	c.Check(err, Equals, context.Canceled)
+	c.Check(ids, DeepEquals, []string{"1-0"})
+}
+
+func (s *StreamListenSuite) TestReconnect(c *C) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	c.Assert(err, IsNil)
+	defer l.Close()
+	go func() {
+		// fail the first connection
+		conn, err := l.Accept()
+		if err != nil {
+			return
+		}
+		conn.Close()
+		conn, err = l.Accept()
+		if err != nil {
+			return
+		}
+		conn.Write([]byte(xreadReply))
+	}()
+
+	st := NewStreams("tcp", l.Addr().String(), 0)
+	st.Backoff = time.Millisecond
+	stop := errors.New("stop")
+	err = st.Listen(context.Background(), map[string]string{"s": "0"},
+		func(stream string, e StreamEntry) error {
+			c.Check(stream, Equals, "s")
+			c.Check(e.ID, Equals, "1-0")
+			return stop
+		})
+	c.Check(err, Equals, stop)
+}
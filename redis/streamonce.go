@@ -0,0 +1,116 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+//* Effectively-once processing
+
+// committedError is returned by the handlers of PollOnce for entries acknowledged by
+// Commit, which dispatch must not acknowledge again.
+var committedError = errors.New("entry committed")
+
+// StreamWrite describes a command writing the result of processing a stream entry.
+type StreamWrite struct {
+	Cmd  string
+	Args []interface{}
+}
+
+// OnceHandler processes an entry of the given stream and returns the commands writing
+// its result, see PollOnce.
+type OnceHandler func(stream string, e StreamEntry) ([]StreamWrite, error)
+
+// Processed returns true if the given entry, or an entry with the same DedupField value,
+// has been committed by the group within the last DedupTTL.
+func (g *ConsumerGroup) Processed(e StreamEntry) (bool, error) {
+	n, err := g.c.Cmd("exists", g.dedupKey(e)).Int64()
+	return n > 0, err
+}
+
+// Commit atomically runs the given writes, marks the given entry as processed and
+// acknowledges it, in a MULTI/EXEC transaction.
+func (g *ConsumerGroup) Commit(e StreamEntry, writes ...StreamWrite) error {
+	ttl := g.DedupTTL
+	if ttl <= 0 {
+		ttl = 24 * time.Hour
+	}
+	reqs := []*request{{cmd: "multi"}}
+	for _, w := range writes {
+		reqs = append(reqs, &request{cmd: w.Cmd, args: w.Args})
+	}
+	reqs = append(reqs,
+		&request{cmd: "set", args: []interface{}{g.dedupKey(e), e.ID, "px",
+			int64(ttl / time.Millisecond)}},
+		&request{cmd: "xack", args: []interface{}{g.stream, g.group, e.ID}},
+		&request{cmd: "exec"})
+	replies := g.c.flush(reqs)
+
+	// commands failing to queue abort the transaction
+	var err error
+	for _, r := range replies[:len(replies)-1] {
+		if r.Type == ErrorReply && err == nil {
+			err = r.Err
+		}
+	}
+	r := replies[len(replies)-1]
+	if err != nil {
+		return err
+	}
+	if r.Type == ErrorReply {
+		return r.Err
+	}
+	if r.Type != MultiReply {
+		return ParseError
+	}
+	for _, er := range r.Elems {
+		if er.Type == ErrorReply {
+			return er.Err
+		}
+	}
+	return nil
+}
+
+// PollOnce is like Poll, but for building effectively-once pipelines on top of the
+// at-least-once delivery of consumer groups. Entries already processed, see Processed,
+// are acknowledged without calling the handler, and the results of the handler are
+// written atomically with acknowledging the entry, see Commit.
+//
+// The deduplication only covers writes returned by the handler; other side effects of
+// the handler may still happen more than once.
+func (g *ConsumerGroup) PollOnce(h OnceHandler) (int, error) {
+	return g.Poll(g.once(h))
+}
+
+// RunOnce is like Run, but polls with PollOnce.
+func (g *ConsumerGroup) RunOnce(ctx context.Context, h OnceHandler) error {
+	return g.Run(ctx, g.once(h))
+}
+
+// once returns a StreamHandler deduplicating entries and committing the results of h.
+func (g *ConsumerGroup) once(h OnceHandler) StreamHandler {
+	return func(stream string, e StreamEntry) error {
+		done, err := g.Processed(e)
+		if err != nil || done {
+			return err
+		}
+		writes, err := h(stream, e)
+		if err != nil {
+			return err
+		}
+		if err = g.Commit(e, writes...); err != nil {
+			return err
+		}
+		return committedError
+	}
+}
+
+// dedupKey returns the key marking the given entry as processed.
+func (g *ConsumerGroup) dedupKey(e StreamEntry) string {
+	id := e.ID
+	if v, ok := e.Fields[g.DedupField]; ok && g.DedupField != "" {
+		id = v
+	}
+	return g.stream + ":" + g.group + ":done:" + id
+}
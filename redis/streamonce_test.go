@@ -0,0 +1,72 @@
+package redis
+
+import (
+	. "launchpad.net/gocheck"
+	"strings"
+)
+
+type StreamOnceSuite struct{}
+
+var _ = Suite(&StreamOnceSuite{})
+
+func (s *StreamOnceSuite) TestPollOnce(c *C) {
+	cl, f := fakeClient("+OK\r\n" +
+		"*1\r\n*2\r\n$1\r\ns\r\n*2\r\n" +
+		"*2\r\n$3\r\n1-0\r\n*2\r\n$1\r\na\r\n$1\r\n1\r\n" +
+		"*2\r\n$3\r\n2-0\r\n*2\r\n$1\r\na\r\n$1\r\n2\r\n" +
+		// 1-0 is new
+		":0\r\n" +
+		"+OK\r\n+QUEUED\r\n+QUEUED\r\n+QUEUED\r\n*3\r\n:1\r\n+OK\r\n:1\r\n" +
+		// 2-0 is a duplicate
+		":1\r\n" +
+		":1\r\n")
+	g, err := NewConsumerGroup(cl, "s", "g", "c1")
+	c.Assert(err, IsNil)
+
+	var seen []string
+	n, err := g.PollOnce(func(stream string, e StreamEntry) ([]StreamWrite, error) {
+		seen = append(seen, e.ID)
+		return []StreamWrite{{"incrby", []interface{}{"total", e.Fields["a"]}}}, nil
+	})
+	c.Assert(err, IsNil)
+	c.Check(n, Equals, 2)
+	c.Check(seen, DeepEquals, []string{"1-0"})
+
+	out := f.out.String()
+	c.Check(strings.Contains(out, "$5\r\nmulti\r\n"+
+		"*3\r\n$6\r\nincrby\r\n$5\r\ntotal\r\n$1\r\n1\r\n"+
+		"*5\r\n$3\r\nset\r\n$12\r\ns:g:done:1-0\r\n$3\r\n1-0\r\n$2\r\npx\r\n$8\r\n86400000\r\n"+
+		"*4\r\n$4\r\nxack\r\n$1\r\ns\r\n$1\r\ng\r\n$3\r\n1-0\r\n"+
+		"*1\r\n$4\r\nexec\r\n"), Equals, true)
+	c.Check(strings.HasSuffix(out, "*4\r\n$4\r\nxack\r\n$1\r\ns\r\n$1\r\ng\r\n$3\r\n2-0\r\n"),
+		Equals, true)
+}
+
+func (s *StreamOnceSuite) TestCommitAborted(c *C) {
+	cl, _ := fakeClient("+OK\r\n-ERR unknown command 'nope'\r\n+QUEUED\r\n+QUEUED\r\n" +
+		"-EXECABORT Transaction discarded because of previous errors.\r\n")
+	g := &ConsumerGroup{c: cl, stream: "s", group: "g"}
+	err := g.Commit(StreamEntry{ID: "1-0"}, StreamWrite{Cmd: "nope"})
+	c.Assert(err, NotNil)
+	c.Check(err.Error(), Equals, "ERR unknown command 'nope'")
+}
+
+func (s *StreamOnceSuite) TestCommitKeepsPipeline(c *C) {
+	cl, f := fakeClient("+OK\r\n+QUEUED\r\n+QUEUED\r\n*2\r\n+OK\r\n:1\r\n" +
+		"$3\r\nbar\r\n")
+	g := &ConsumerGroup{c: cl, stream: "s", group: "g"}
+	cl.Append("get", "foo")
+	c.Assert(g.Commit(StreamEntry{ID: "1-0"}), IsNil)
+	c.Check(strings.HasPrefix(f.out.String(), "*1\r\n$5\r\nmulti\r\n"), Equals, true)
+	v, err := cl.GetReply().Str()
+	c.Assert(err, IsNil)
+	c.Check(v, Equals, "bar")
+	c.Check(cl.GetReply().Err, Equals, PipelineQueueEmptyError)
+}
+
+func (s *StreamOnceSuite) TestDedupKey(c *C) {
+	g := &ConsumerGroup{stream: "s", group: "g", DedupField: "req"}
+	c.Check(g.dedupKey(StreamEntry{ID: "1-0", Fields: map[string]string{"req": "r1"}}),
+		Equals, "s:g:done:r1")
+	c.Check(g.dedupKey(StreamEntry{ID: "1-0"}), Equals, "s:g:done:1-0")
+}
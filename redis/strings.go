@@ -0,0 +1,179 @@
+package redis
+
+import (
+	"errors"
+	"time"
+)
+
+//* Strings
+
+// SetOptions describes the options of Set.
+type SetOptions struct {
+	TTL      time.Duration // Expire after this long (EX or PX), 0 disables
+	ExpireAt time.Time     // Expire at this time (EXAT or PXAT), used if TTL is 0
+	KeepTTL  bool          // Keep the time to live of the existing key
+	NX       bool          // Only set the key if it does not exist
+	XX       bool          // Only set the key if it exists
+	Get      bool          // Return the old value (Redis 6.2 or later, 7.0 with NX)
+}
+
+func (opt SetOptions) args() []interface{} {
+	args := expiryArgs(opt.TTL, opt.ExpireAt)
+	if len(args) == 0 && opt.KeepTTL {
+		args = append(args, "keepttl")
+	}
+	if opt.NX {
+		args = append(args, "nx")
+	} else if opt.XX {
+		args = append(args, "xx")
+	}
+	if opt.Get {
+		args = append(args, "get")
+	}
+	return args
+}
+
+// Set sets the given key to the given value and returns true if it did, which it may not
+// with NX or XX. With Get, it also returns the old value, or "" if the key did not exist.
+func (c *Client) Set(key string, value interface{}, opt SetOptions) (set bool, old string,
+	err error) {
+	r := c.Cmd("set", append([]interface{}{key, value}, opt.args()...)...)
+	switch {
+	case r.Type == ErrorReply:
+		return false, "", r.Err
+	case !opt.Get:
+		return r.Type != NilReply, "", nil
+	case r.Type == NilReply:
+		return !opt.XX, "", nil
+	}
+	old, err = r.Str()
+	return err == nil && !opt.NX, old, err
+}
+
+// GetExOptions describes the options of GetEx.
+type GetExOptions struct {
+	TTL      time.Duration // Expire after this long (EX or PX), 0 disables
+	ExpireAt time.Time     // Expire at this time (EXAT or PXAT), used if TTL is 0
+	Persist  bool          // Remove the time to live, used if neither TTL nor ExpireAt is set
+}
+
+// GetEx returns the value of the given key and updates its time to live (Redis 6.2 or
+// later). It returns false if the key does not exist.
+func (c *Client) GetEx(key string, opt GetExOptions) (string, bool, error) {
+	args := expiryArgs(opt.TTL, opt.ExpireAt)
+	if len(args) == 0 && opt.Persist {
+		args = append(args, "persist")
+	}
+	return optStr(c.cmdSince(Version{6, 2, 0}, "getex", key, args))
+}
+
+// GetDel returns the value of the given key and deletes it (Redis 6.2 or later).
+// It returns false if the key does not exist.
+func (c *Client) GetDel(key string) (string, bool, error) {
+	return optStr(c.cmdSince(Version{6, 2, 0}, "getdel", key))
+}
+
+// AppendValue appends the given value to the value of the given key, creating the key
+// if needed, and returns the length of the new value.
+func (c *Client) AppendValue(key string, value interface{}) (int64, error) {
+	return c.Cmd("append", key, value).Int64()
+}
+
+// SetRange overwrites the value of the given key with the given value starting at the
+// given offset, padding it with zero bytes if needed, and returns the length of the
+// new value.
+func (c *Client) SetRange(key string, offset int64, value interface{}) (int64, error) {
+	return c.Cmd("setrange", key, offset, value).Int64()
+}
+
+// GetRange returns the substring of the value of the given key between the given
+// offsets, both inclusive. Negative offsets count from the end of the value.
+func (c *Client) GetRange(key string, start, end int64) (string, error) {
+	return c.Cmd("getrange", key, start, end).Str()
+}
+
+// MGetMap returns the values of the given keys by key. Missing keys are left out.
+func (c *Client) MGetMap(keys ...string) (map[string]string, error) {
+	return mgetMap(c, keys)
+}
+
+// MSetMap sets the given keys to their values.
+func (c *Client) MSetMap(values map[string]interface{}) error {
+	return msetMap(c, values)
+}
+
+// MGetMap returns the values of the given keys by key, which may be in different slots.
+// Missing keys are left out.
+func (cc *ClusterClient) MGetMap(keys ...string) (map[string]string, error) {
+	return mgetMap(cc, keys)
+}
+
+// MSetMap sets the given keys, which may be in different slots, to their values.
+// Keys in different slots are not set atomically.
+func (cc *ClusterClient) MSetMap(values map[string]interface{}) error {
+	return msetMap(cc, values)
+}
+
+// MGetMap returns the values of the given keys by key, which may be on different
+// servers. Missing keys are left out.
+func (rc *RingClient) MGetMap(keys ...string) (map[string]string, error) {
+	return mgetMap(rc, keys)
+}
+
+// MSetMap sets the given keys, which may be on different servers, to their values.
+// Keys on different servers are not set atomically.
+func (rc *RingClient) MSetMap(values map[string]interface{}) error {
+	return msetMap(rc, values)
+}
+
+func mgetMap(c Cmder, keys []string) (map[string]string, error) {
+	m := make(map[string]string, len(keys))
+	if len(keys) == 0 {
+		return m, nil
+	}
+	r := c.Cmd("mget", keys)
+	if r.Type == ErrorReply {
+		return nil, r.Err
+	}
+	if r.Type != MultiReply || len(r.Elems) != len(keys) {
+		return nil, ParseError
+	}
+	for i, e := range r.Elems {
+		if e.Type == NilReply {
+			continue
+		}
+		v, err := e.Str()
+		if err != nil {
+			return nil, err
+		}
+		m[keys[i]] = v
+	}
+	return m, nil
+}
+
+func msetMap(c Cmder, values map[string]interface{}) error {
+	if len(values) == 0 {
+		return errors.New("no values to set")
+	}
+	args := make([]interface{}, 0, 2*len(values))
+	for k, v := range values {
+		args = append(args, k, v)
+	}
+	return c.Cmd("mset", args...).Err
+}
+
+// expiryArgs returns the arguments setting the given time to live or expiration time,
+// in seconds if they are whole seconds and in milliseconds otherwise.
+func expiryArgs(ttl time.Duration, at time.Time) []interface{} {
+	switch {
+	case ttl > 0 && ttl%time.Second == 0:
+		return []interface{}{"ex", int64(ttl / time.Second)}
+	case ttl > 0:
+		return []interface{}{"px", int64(ttl / time.Millisecond)}
+	case !at.IsZero() && at.Nanosecond() != 0:
+		return []interface{}{"pxat", at.UnixNano() / int64(time.Millisecond)}
+	case !at.IsZero():
+		return []interface{}{"exat", at.Unix()}
+	}
+	return nil
+}
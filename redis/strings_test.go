@@ -0,0 +1,102 @@
+package redis
+
+import (
+	. "launchpad.net/gocheck"
+	"strings"
+	"time"
+)
+
+type StringsSuite struct{}
+
+var _ = Suite(&StringsSuite{})
+
+func (s *StringsSuite) TestSetArgs(c *C) {
+	c.Check(SetOptions{TTL: 2 * time.Second, NX: true}.args(), DeepEquals,
+		[]interface{}{"ex", int64(2), "nx"})
+	c.Check(SetOptions{TTL: 1500 * time.Millisecond, XX: true, Get: true}.args(), DeepEquals,
+		[]interface{}{"px", int64(1500), "xx", "get"})
+	c.Check(SetOptions{ExpireAt: time.Unix(100, 0)}.args(), DeepEquals,
+		[]interface{}{"exat", int64(100)})
+	c.Check(SetOptions{ExpireAt: time.Unix(100, int64(time.Millisecond))}.args(), DeepEquals,
+		[]interface{}{"pxat", int64(100001)})
+	c.Check(SetOptions{KeepTTL: true}.args(), DeepEquals, []interface{}{"keepttl"})
+	c.Check(SetOptions{}.args(), HasLen, 0)
+}
+
+func (s *StringsSuite) TestSet(c *C) {
+	cl, f := fakeClient("+OK\r\n$-1\r\n$3\r\nold\r\n$-1\r\n")
+	set, _, err := cl.Set("k", "v", SetOptions{})
+	c.Assert(err, IsNil)
+	c.Check(set, Equals, true)
+	c.Check(f.out.String(), Equals, "*3\r\n$3\r\nset\r\n$1\r\nk\r\n$1\r\nv\r\n")
+
+	set, _, err = cl.Set("k", "v", SetOptions{NX: true})
+	c.Assert(err, IsNil)
+	c.Check(set, Equals, false)
+
+	set, old, err := cl.Set("k", "v", SetOptions{NX: true, Get: true})
+	c.Assert(err, IsNil)
+	c.Check(set, Equals, false)
+	c.Check(old, Equals, "old")
+
+	set, old, err = cl.Set("k", "v", SetOptions{XX: true, Get: true})
+	c.Assert(err, IsNil)
+	c.Check(set, Equals, false)
+	c.Check(old, Equals, "")
+}
+
+func (s *StringsSuite) TestGetEx(c *C) {
+	cl, f := fakeClient("$1\r\nv\r\n$-1\r\n$1\r\nv\r\n")
+	v, ok, err := cl.GetEx("k", GetExOptions{TTL: time.Minute})
+	c.Assert(err, IsNil)
+	c.Check(ok, Equals, true)
+	c.Check(v, Equals, "v")
+	c.Check(f.out.String(), Equals, "*4\r\n$5\r\ngetex\r\n$1\r\nk\r\n$2\r\nex\r\n$2\r\n60\r\n")
+
+	f.out.Reset()
+	_, ok, err = cl.GetEx("k", GetExOptions{Persist: true})
+	c.Assert(err, IsNil)
+	c.Check(ok, Equals, false)
+	c.Check(f.out.String(), Equals, "*3\r\n$5\r\ngetex\r\n$1\r\nk\r\n$7\r\npersist\r\n")
+
+	v, ok, err = cl.GetDel("k")
+	c.Assert(err, IsNil)
+	c.Check(ok, Equals, true)
+	c.Check(v, Equals, "v")
+}
+
+func (s *StringsSuite) TestMutation(c *C) {
+	cl, f := fakeClient(":5\r\n:8\r\n$3\r\nell\r\n")
+	n, err := cl.AppendValue("k", "hello")
+	c.Assert(err, IsNil)
+	c.Check(n, Equals, int64(5))
+	n, err = cl.SetRange("k", 5, "!!!")
+	c.Assert(err, IsNil)
+	c.Check(n, Equals, int64(8))
+	v, err := cl.GetRange("k", 1, -6)
+	c.Assert(err, IsNil)
+	c.Check(v, Equals, "ell")
+	c.Check(f.out.String()[len(f.out.String())-24:], Equals,
+		"\r\n$1\r\nk\r\n$1\r\n1\r\n$2\r\n-6\r\n")
+}
+
+func (s *StringsSuite) TestMGetMap(c *C) {
+	cl, f := fakeClient("*3\r\n$1\r\n1\r\n$-1\r\n$0\r\n\r\n+OK\r\n")
+	m, err := cl.MGetMap("a", "b", "c")
+	c.Assert(err, IsNil)
+	c.Check(m, DeepEquals, map[string]string{"a": "1", "c": ""})
+	c.Assert(cl.MSetMap(map[string]interface{}{"a": 1}), IsNil)
+	c.Check(strings.HasSuffix(f.out.String(), "*3\r\n$4\r\nmset\r\n$1\r\na\r\n$1\r\n1\r\n"),
+		Equals, true)
+}
+
+func (s *StringsSuite) TestClusterMGetMap(c *C) {
+	// split by slot: foo and qux live on the second node, bar on the first
+	cc, fa, fb := twoNodeCluster("*1\r\n$1\r\n2\r\n", "*1\r\n$1\r\n1\r\n*1\r\n$-1\r\n")
+	m, err := cc.MGetMap("foo", "bar", "qux")
+	c.Assert(err, IsNil)
+	c.Check(m, DeepEquals, map[string]string{"foo": "1", "bar": "2"})
+	c.Check(fa.out.String(), Equals, "*2\r\n$4\r\nmget\r\n$3\r\nbar\r\n")
+	c.Check(fb.out.String(), Equals,
+		"*2\r\n$4\r\nmget\r\n$3\r\nfoo\r\n*2\r\n$4\r\nmget\r\n$3\r\nqux\r\n")
+}
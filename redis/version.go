@@ -0,0 +1,112 @@
+package redis
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+//* Server version
+
+// Version is a server version.
+type Version struct {
+	Major, Minor, Patch int
+}
+
+// ParseVersion parses a version of the form "7.2.4". Missing minor and patch numbers
+// are zero.
+func ParseVersion(s string) (Version, error) {
+	var v Version
+	parts := strings.SplitN(s, ".", 3)
+	for i, p := range []*int{&v.Major, &v.Minor, &v.Patch}[:len(parts)] {
+		n, err := strconv.Atoi(parts[i])
+		if err != nil {
+			return Version{}, ParseError
+		}
+		*p = n
+	}
+	return v, nil
+}
+
+// Less returns true if v is older than o.
+func (v Version) Less(o Version) bool {
+	if v.Major != o.Major {
+		return v.Major < o.Major
+	}
+	if v.Minor != o.Minor {
+		return v.Minor < o.Minor
+	}
+	return v.Patch < o.Patch
+}
+
+func (v Version) String() string {
+	return strconv.Itoa(v.Major) + "." + strconv.Itoa(v.Minor) + "." + strconv.Itoa(v.Patch)
+}
+
+// ErrUnsupportedServerVersion matches every *UnsupportedServerVersionError with
+// errors.Is.
+var ErrUnsupportedServerVersion error = errors.New("unsupported server version")
+
+// UnsupportedServerVersionError is returned by the helpers of commands, or command
+// options, that the server does not support because it is too old.
+type UnsupportedServerVersionError struct {
+	Cmd      string  // Command name
+	Required Version // First server version supporting the command
+	Server   Version
+}
+
+func (e *UnsupportedServerVersionError) Error() string {
+	return e.Cmd + " requires Redis " + e.Required.String() + " or later, the server runs " +
+		e.Server.String()
+}
+
+// Is returns true for ErrUnsupportedServerVersion.
+func (e *UnsupportedServerVersionError) Is(target error) bool {
+	return target == ErrUnsupportedServerVersion
+}
+
+// ServerVersion returns the version of the server, as reported by INFO. It is fetched
+// lazily, not when connecting: the helpers of commands requiring a recent server only
+// fetch it once such a command has been sent and failed. It is cached until the client
+// connects to another server.
+func (c *Client) ServerVersion() (Version, error) {
+	if c.version == (Version{}) {
+		info, err := c.Info("server")
+		if err != nil {
+			return Version{}, err
+		}
+		v, err := ParseVersion(info.Version)
+		if err != nil {
+			return Version{}, err
+		}
+		c.version = v
+	}
+	return c.version, nil
+}
+
+// cmdSince calls the given command, which requires the given server version, see
+// checkVersion.
+func (c *Client) cmdSince(required Version, cmd string, args ...interface{}) *Reply {
+	return c.checkVersion(required, cmd, c.Cmd(cmd, args...))
+}
+
+// checkVersion replaces the error of the given reply of a command requiring the given
+// server version with an *UnsupportedServerVersionError if the server rejected the
+// command, one of its options or its number of arguments, and is too old. The version
+// is only fetched then, so that supported commands cost no extra round trip.
+func (c *Client) checkVersion(required Version, cmd string, r *Reply) *Reply {
+	if r.Type != ErrorReply {
+		return r
+	}
+	msg := strings.ToLower(r.Err.Error())
+	if !strings.HasPrefix(msg, "err unknown") && !strings.HasPrefix(msg, "err syntax error") &&
+		!strings.HasPrefix(msg, "err wrong number of arguments") {
+		return r
+	}
+	v, err := c.ServerVersion()
+	if err != nil || !v.Less(required) {
+		return r
+	}
+	err = &UnsupportedServerVersionError{Cmd: strings.ToUpper(cmd), Required: required, Server: v}
+	return &Reply{Type: ErrorReply, Err: err}
+}
@@ -0,0 +1,101 @@
+package redis
+
+import (
+	"errors"
+	"fmt"
+	. "launchpad.net/gocheck"
+	"time"
+)
+
+type VersionSuite struct{}
+
+var _ = Suite(&VersionSuite{})
+
+func infoServer(version string) string {
+	s := "# Server\r\nredis_version:" + version + "\r\n"
+	return fmt.Sprintf("$%d\r\n%s\r\n", len(s), s)
+}
+
+func (s *VersionSuite) TestParseVersion(c *C) {
+	v, err := ParseVersion("7.2.4")
+	c.Assert(err, IsNil)
+	c.Check(v, Equals, Version{7, 2, 4})
+	c.Check(v.String(), Equals, "7.2.4")
+	v, err = ParseVersion("6")
+	c.Assert(err, IsNil)
+	c.Check(v, Equals, Version{6, 0, 0})
+	_, err = ParseVersion("7.x")
+	c.Check(err, Equals, ParseError)
+	_, err = ParseVersion("")
+	c.Check(err, Equals, ParseError)
+
+	c.Check(Version{6, 2, 0}.Less(Version{7, 0, 0}), Equals, true)
+	c.Check(Version{6, 2, 0}.Less(Version{6, 2, 1}), Equals, true)
+	c.Check(Version{6, 2, 0}.Less(Version{6, 0, 9}), Equals, false)
+	c.Check(Version{6, 2, 0}.Less(Version{6, 2, 0}), Equals, false)
+}
+
+func (s *VersionSuite) TestServerVersion(c *C) {
+	cl, f := fakeClient(infoServer("6.0.9"))
+	v, err := cl.ServerVersion()
+	c.Assert(err, IsNil)
+	c.Check(v, Equals, Version{6, 0, 9})
+	v, err = cl.ServerVersion()
+	c.Assert(err, IsNil)
+	c.Check(v, Equals, Version{6, 0, 9})
+	c.Check(f.out.String(), Equals, "*2\r\n$4\r\ninfo\r\n$6\r\nserver\r\n")
+}
+
+func (s *VersionSuite) TestUnsupportedServerVersion(c *C) {
+	cl, _ := fakeClient("-ERR unknown command 'getdel', with args beginning with: 'k'\r\n" +
+		infoServer("6.0.9") + "-ERR unknown command 'getdel'\r\n")
+	_, _, err := cl.GetDel("k")
+	c.Check(err, DeepEquals, &UnsupportedServerVersionError{Cmd: "GETDEL",
+		Required: Version{6, 2, 0}, Server: Version{6, 0, 9}})
+	c.Check(err, ErrorMatches, "GETDEL requires Redis 6.2.0 or later, the server runs 6.0.9")
+	c.Check(errors.Is(err, ErrUnsupportedServerVersion), Equals, true)
+	c.Check(errors.Is(ParseError, ErrUnsupportedServerVersion), Equals, false)
+	_, err = cl.SortRO(NewSort("k"))
+	c.Check(err, FitsTypeOf, &UnsupportedServerVersionError{})
+
+	// recent servers keep their error
+	cl, _ = fakeClient("-ERR unknown subcommand 'no-evict'\r\n" + infoServer("7.2.0") +
+		"-WRONGTYPE Operation against a key holding the wrong kind of value\r\n")
+	c.Check(cl.ClientNoEvict(true), ErrorMatches, "ERR unknown subcommand 'no-evict'")
+	_, err = cl.SInterCard(0, "k")
+	c.Check(err, ErrorMatches, "WRONGTYPE .*")
+}
+
+func (s *VersionSuite) TestUnsupportedOptions(c *C) {
+	syntax := "-ERR syntax error\r\n"
+	cl, _ := fakeClient(syntax + infoServer("6.0.9") + syntax +
+		"-ERR unknown command 'xautoclaim'\r\n")
+	_, err := cl.ZRangeByScore("z", ScoreRange{Min: 0, Max: 1}, ZRangeOptions{})
+	c.Check(err, DeepEquals, &UnsupportedServerVersionError{Cmd: "ZRANGE",
+		Required: Version{6, 2, 0}, Server: Version{6, 0, 9}})
+	_, err = cl.ZRangeByLex("z", LexRange{}, ZRangeOptions{})
+	c.Check(err, FitsTypeOf, &UnsupportedServerVersionError{})
+	_, _, err = cl.XAutoClaim("s", "g", "c", time.Minute, "0-0", 10)
+	c.Check(err, FitsTypeOf, &UnsupportedServerVersionError{})
+
+	unknown := "-ERR unknown command\r\n"
+	cl, _ = fakeClient(unknown + infoServer("6.2.0") + unknown + unknown + unknown +
+		"-ERR wrong number of arguments for 'expire' command\r\n")
+	_, err = cl.FunctionLoad("#!lua name=mylib", false)
+	c.Check(err, DeepEquals, &UnsupportedServerVersionError{Cmd: "FUNCTION",
+		Required: Version{7, 0, 0}, Server: Version{6, 2, 0}})
+	c.Check(cl.FCall("myfunc", nil).Err, FitsTypeOf, &UnsupportedServerVersionError{})
+	_, err = cl.FunctionStats()
+	c.Check(err, FitsTypeOf, &UnsupportedServerVersionError{})
+	_, err = cl.ClusterShards()
+	c.Check(err, FitsTypeOf, &UnsupportedServerVersionError{})
+	_, err = cl.Expire("k", time.Minute, ExpireNX)
+	c.Check(err, DeepEquals, &UnsupportedServerVersionError{Cmd: "EXPIRE",
+		Required: Version{7, 0, 0}, Server: Version{6, 2, 0}})
+
+	// commands without options keep their error
+	cl, f := fakeClient("-ERR wrong number of arguments for 'expire' command\r\n")
+	_, err = cl.Expire("k", time.Minute, ExpireAlways)
+	c.Check(err, ErrorMatches, "ERR wrong number of arguments .*")
+	c.Check(f.out.String(), Equals, "*3\r\n$6\r\nexpire\r\n$1\r\nk\r\n$2\r\n60\r\n")
+}
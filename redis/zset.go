@@ -0,0 +1,260 @@
+package redis
+
+import (
+	"math"
+	"strconv"
+)
+
+//* Sorted sets
+
+// ScoredMember describes a member of a sorted set with its score.
+type ScoredMember struct {
+	Member string
+	Score  float64
+}
+
+// ZAddOptions describes the options of ZAdd and ZAddIncr.
+type ZAddOptions struct {
+	NX bool // Only add new members
+	XX bool // Only update existing members
+	GT bool // Only update scores that increase (Redis 6.2 or later)
+	LT bool // Only update scores that decrease (Redis 6.2 or later)
+	CH bool // Count changed members instead of added ones, ignored by ZAddIncr
+}
+
+func (opt ZAddOptions) args() []interface{} {
+	var args []interface{}
+	if opt.NX {
+		args = append(args, "nx")
+	} else if opt.XX {
+		args = append(args, "xx")
+	}
+	if opt.GT {
+		args = append(args, "gt")
+	} else if opt.LT {
+		args = append(args, "lt")
+	}
+	if opt.CH {
+		args = append(args, "ch")
+	}
+	return args
+}
+
+// ZAdd adds the given members to the sorted set at the given key, updating the scores
+// of existing members, and returns the number of added members, or changed members
+// with CH.
+func (c *Client) ZAdd(key string, opt ZAddOptions, members ...ScoredMember) (int64, error) {
+	args := append([]interface{}{key}, opt.args()...)
+	for _, m := range members {
+		args = append(args, formatScore(m.Score, false), m.Member)
+	}
+	return c.Cmd("zadd", args...).Int64()
+}
+
+// ZAddIncr increments the score of the given member like ZINCRBY, subject to the given
+// options (ZADD INCR), and returns the new score. It returns false if the options
+// prevented the update.
+func (c *Client) ZAddIncr(key string, opt ZAddOptions, m ScoredMember) (float64, bool,
+	error) {
+	opt.CH = false
+	args := append([]interface{}{key}, opt.args()...)
+	args = append(args, "incr", formatScore(m.Score, false), m.Member)
+	s, ok, err := optStr(c.Cmd("zadd", args...))
+	if !ok {
+		return 0, false, err
+	}
+	score, err := strconv.ParseFloat(s, 64)
+	return score, err == nil, err
+}
+
+// ZRangeSpec is a range of sorted set members: an IndexRange, a ScoreRange or
+// a LexRange.
+type ZRangeSpec interface {
+	zrangeArgs(rev bool) []interface{}
+}
+
+// IndexRange is a range of sorted set members by rank, both inclusive.
+// Negative ranks count from the end of the sorted set.
+type IndexRange struct {
+	Start, Stop int64
+}
+
+func (r IndexRange) zrangeArgs(rev bool) []interface{} {
+	return []interface{}{r.Start, r.Stop}
+}
+
+// ScoreRange is a range of sorted set members by score.
+// Use math.Inf for unbounded ranges.
+type ScoreRange struct {
+	Min, Max                   float64
+	MinExclusive, MaxExclusive bool
+}
+
+func (r ScoreRange) zrangeArgs(rev bool) []interface{} {
+	min, max := formatScore(r.Min, r.MinExclusive), formatScore(r.Max, r.MaxExclusive)
+	if rev {
+		return []interface{}{max, min, "byscore", "rev"}
+	}
+	return []interface{}{min, max, "byscore"}
+}
+
+// LexRange is a range of sorted set members by member, for sorted sets whose members
+// all have the same score. An empty Min or Max leaves the range unbounded.
+type LexRange struct {
+	Min, Max                   string
+	MinExclusive, MaxExclusive bool
+}
+
+func (r LexRange) zrangeArgs(rev bool) []interface{} {
+	min, max := formatLex(r.Min, r.MinExclusive, "-"), formatLex(r.Max, r.MaxExclusive, "+")
+	if rev {
+		return []interface{}{max, min, "bylex", "rev"}
+	}
+	return []interface{}{min, max, "bylex"}
+}
+
+// ZRangeOptions describes the options of the ZRange methods.
+type ZRangeOptions struct {
+	Rev    bool  // Return members in reverse order
+	Offset int64 // Skip this many members, score and lex ranges only
+	Count  int64 // Return at most this many members, score and lex ranges only, 0 for all
+}
+
+func (opt ZRangeOptions) args(r ZRangeSpec) []interface{} {
+	args := r.zrangeArgs(opt.Rev)
+	if _, ok := r.(IndexRange); ok && opt.Rev {
+		args = append(args, "rev")
+	}
+	if opt.Count > 0 {
+		args = append(args, "limit", opt.Offset, opt.Count)
+	}
+	return args
+}
+
+// ZRange returns the members of the sorted set at the given key within the given
+// range of ranks, with their scores.
+func (c *Client) ZRange(key string, r IndexRange, opt ZRangeOptions) ([]ScoredMember,
+	error) {
+	return c.zrangeWithScores(key, r, opt)
+}
+
+// ZRangeByScore returns the members of the sorted set at the given key within the given
+// range of scores, with their scores (Redis 6.2 or later).
+func (c *Client) ZRangeByScore(key string, r ScoreRange, opt ZRangeOptions) ([]ScoredMember,
+	error) {
+	return c.zrangeWithScores(key, r, opt)
+}
+
+// ZRangeByLex returns the members of the sorted set at the given key within the given
+// range of members (Redis 6.2 or later).
+func (c *Client) ZRangeByLex(key string, r LexRange, opt ZRangeOptions) ([]string, error) {
+	return c.zrange(key, r, opt).List()
+}
+
+// ZRangeStore stores the members of the sorted set src within the given range in the
+// sorted set dst and returns the number of stored members (Redis 6.2 or later).
+func (c *Client) ZRangeStore(dst, src string, r ZRangeSpec, opt ZRangeOptions) (int64,
+	error) {
+	return c.cmdSince(Version{6, 2, 0}, "zrangestore", dst, src, opt.args(r)).Int64()
+}
+
+func (c *Client) zrangeWithScores(key string, r ZRangeSpec, opt ZRangeOptions) (
+	[]ScoredMember, error) {
+	return parseScoredMembers(c.zrange(key, r, opt, "withscores"))
+}
+
+// zrange calls ZRANGE, whose BYSCORE, BYLEX and REV options require Redis 6.2.
+func (c *Client) zrange(key string, r ZRangeSpec, opt ZRangeOptions,
+	extra ...interface{}) *Reply {
+	args := append(append([]interface{}{key}, opt.args(r)...), extra...)
+	if _, ok := r.(IndexRange); ok && !opt.Rev {
+		return c.Cmd("zrange", args...)
+	}
+	return c.cmdSince(Version{6, 2, 0}, "zrange", args...)
+}
+
+// ZSide is an end of a sorted set.
+type ZSide string
+
+const (
+	ZMin ZSide = "min" // Members with the lowest scores
+	ZMax ZSide = "max" // Members with the highest scores
+)
+
+// ZMPop pops up to count members from the given side of the first non-empty sorted set
+// of the given keys (Redis 7.0 or later). It returns the key and the members, or false
+// if all sorted sets are empty.
+func (c *Client) ZMPop(side ZSide, count int64, keys ...string) (key string,
+	members []ScoredMember, ok bool, err error) {
+	r := c.cmdSince(Version{7, 0, 0}, "zmpop", len(keys), keys, string(side), "count", count)
+	switch {
+	case r.Type == NilReply:
+		return "", nil, false, nil
+	case r.Type == ErrorReply:
+		return "", nil, false, r.Err
+	case r.Type != MultiReply || len(r.Elems) != 2 || r.Elems[1].Type != MultiReply:
+		return "", nil, false, ParseError
+	}
+	if key, err = r.Elems[0].Str(); err != nil {
+		return "", nil, false, err
+	}
+	members = make([]ScoredMember, len(r.Elems[1].Elems))
+	for i, e := range r.Elems[1].Elems {
+		l, err := parseScoredMembers(e)
+		if err != nil {
+			return "", nil, false, err
+		}
+		if len(l) != 1 {
+			return "", nil, false, ParseError
+		}
+		members[i] = l[0]
+	}
+	return key, members, true, nil
+}
+
+// parseScoredMembers parses a reply of alternating members and scores.
+func parseScoredMembers(r *Reply) ([]ScoredMember, error) {
+	l, err := r.List()
+	if err != nil {
+		return nil, err
+	}
+	if len(l)%2 != 0 {
+		return nil, ParseError
+	}
+	members := make([]ScoredMember, len(l)/2)
+	for i := range members {
+		members[i].Member = l[2*i]
+		if members[i].Score, err = strconv.ParseFloat(l[2*i+1], 64); err != nil {
+			return nil, err
+		}
+	}
+	return members, nil
+}
+
+// formatScore formats the given score or score bound.
+func formatScore(f float64, exclusive bool) string {
+	var s string
+	switch {
+	case math.IsInf(f, 1):
+		s = "+inf"
+	case math.IsInf(f, -1):
+		s = "-inf"
+	default:
+		s = strconv.FormatFloat(f, 'g', -1, 64)
+	}
+	if exclusive {
+		return "(" + s
+	}
+	return s
+}
+
+// formatLex formats the given member bound, using unbounded if it is empty.
+func formatLex(s string, exclusive bool, unbounded string) string {
+	switch {
+	case s == "":
+		return unbounded
+	case exclusive:
+		return "(" + s
+	}
+	return "[" + s
+}
@@ -0,0 +1,61 @@
+package redis
+
+import (
+	. "launchpad.net/gocheck"
+	"math"
+)
+
+type ZSetSuite struct{}
+
+var _ = Suite(&ZSetSuite{})
+
+func (s *ZSetSuite) TestZAdd(c *C) {
+	cl, f := fakeClient(":1\r\n$3\r\n2.5\r\n$-1\r\n")
+	n, err := cl.ZAdd("z", ZAddOptions{XX: true, GT: true, CH: true},
+		ScoredMember{"a", 1}, ScoredMember{"b", math.Inf(1)})
+	c.Assert(err, IsNil)
+	c.Check(n, Equals, int64(1))
+	c.Check(f.out.String(), Equals, "*9\r\n$4\r\nzadd\r\n$1\r\nz\r\n$2\r\nxx\r\n$2\r\ngt\r\n"+
+		"$2\r\nch\r\n$1\r\n1\r\n$1\r\na\r\n$4\r\n+inf\r\n$1\r\nb\r\n")
+
+	score, ok, err := cl.ZAddIncr("z", ZAddOptions{}, ScoredMember{"a", 1.5})
+	c.Assert(err, IsNil)
+	c.Check(ok, Equals, true)
+	c.Check(score, Equals, 2.5)
+
+	_, ok, err = cl.ZAddIncr("z", ZAddOptions{NX: true}, ScoredMember{"a", 1})
+	c.Assert(err, IsNil)
+	c.Check(ok, Equals, false)
+}
+
+func (s *ZSetSuite) TestRangeArgs(c *C) {
+	r := ScoreRange{Min: 1, Max: math.Inf(1), MinExclusive: true}
+	c.Check(ZRangeOptions{Rev: true, Count: 10}.args(r), DeepEquals,
+		[]interface{}{"+inf", "(1", "byscore", "rev", "limit", int64(0), int64(10)})
+	c.Check(ZRangeOptions{}.args(LexRange{Max: "m", MaxExclusive: true}), DeepEquals,
+		[]interface{}{"-", "(m", "bylex"})
+	c.Check(ZRangeOptions{Rev: true}.args(IndexRange{0, -1}), DeepEquals,
+		[]interface{}{int64(0), int64(-1), "rev"})
+}
+
+func (s *ZSetSuite) TestZRange(c *C) {
+	cl, f := fakeClient("*4\r\n$1\r\na\r\n$1\r\n1\r\n$1\r\nb\r\n$3\r\ninf\r\n" +
+		"*1\r\n$1\r\na\r\n:2\r\n")
+	members, err := cl.ZRangeByScore("z", ScoreRange{Min: math.Inf(-1), Max: math.Inf(1)},
+		ZRangeOptions{})
+	c.Assert(err, IsNil)
+	c.Check(members, DeepEquals, []ScoredMember{{"a", 1}, {"b", math.Inf(1)}})
+	c.Check(f.out.String(), Equals, "*6\r\n$6\r\nzrange\r\n$1\r\nz\r\n$4\r\n-inf\r\n"+
+		"$4\r\n+inf\r\n$7\r\nbyscore\r\n$10\r\nwithscores\r\n")
+
+	l, err := cl.ZRangeByLex("z", LexRange{Min: "a"}, ZRangeOptions{})
+	c.Assert(err, IsNil)
+	c.Check(l, DeepEquals, []string{"a"})
+
+	f.out.Reset()
+	n, err := cl.ZRangeStore("dst", "z", IndexRange{0, 1}, ZRangeOptions{})
+	c.Assert(err, IsNil)
+	c.Check(n, Equals, int64(2))
+	c.Check(f.out.String(), Equals, "*5\r\n$11\r\nzrangestore\r\n$3\r\ndst\r\n$1\r\nz\r\n"+
+		"$1\r\n0\r\n$1\r\n1\r\n")
+}
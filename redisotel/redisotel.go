@@ -0,0 +1,120 @@
+// Package redisotel traces the commands of Radix clients with OpenTelemetry.
+//
+// Each command, or pipeline, is traced as a client span, child of the span of the context
+// given with CmdContext or AppendContext:
+//
+//	c, err := redis.Dial("tcp", "localhost:6379")
+//	c.Hook = redisotel.NewHook(redisotel.Options{})
+//	r := c.CmdContext(ctx, "get", "key")
+package redisotel
+
+import (
+	"context"
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/fzzy/radix/redis"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const instrumentationName = "github.com/fzzy/radix/redisotel"
+
+// sensitive are the commands whose arguments are always redacted, as they may carry
+// credentials.
+var sensitive = map[string]bool{
+	"auth": true, "hello": true, "acl": true, "config": true, "migrate": true,
+}
+
+// Options configures NewHook.
+type Options struct {
+	// TracerProvider provides the tracer, the global one if nil.
+	TracerProvider trace.TracerProvider
+	// Args includes the arguments of the commands in the db.statement attribute of the
+	// spans. They are redacted as "?" otherwise, and always for the commands carrying
+	// credentials, such as AUTH.
+	Args bool
+	// MaxArgLen is the length to which arguments are truncated, 64 bytes if zero.
+	MaxArgLen int
+	// Attributes are added to all spans.
+	Attributes []attribute.KeyValue
+}
+
+type hook struct {
+	tracer trace.Tracer
+	opt    Options
+}
+
+// NewHook returns a hook tracing the commands of a client, see redis.Client.Hook.
+func NewHook(opt Options) redis.Hook {
+	if opt.TracerProvider == nil {
+		opt.TracerProvider = otel.GetTracerProvider()
+	}
+	if opt.MaxArgLen == 0 {
+		opt.MaxArgLen = 64
+	}
+	return &hook{tracer: opt.TracerProvider.Tracer(instrumentationName), opt: opt}
+}
+
+func (h *hook) Before(ctx context.Context, c *redis.Client, calls []redis.Call) context.Context {
+	name := "pipeline"
+	attrs := []attribute.KeyValue{
+		attribute.String("db.system", "redis"),
+		attribute.String("db.statement", h.statement(calls)),
+	}
+	if len(calls) == 1 {
+		name = strings.ToLower(calls[0].Cmd)
+		attrs = append(attrs, attribute.String("db.operation", strings.ToUpper(name)))
+	} else {
+		attrs = append(attrs, attribute.Int("db.redis.num_cmd", len(calls)))
+	}
+	if addr := c.RemoteAddr(); addr != nil {
+		host, port, err := net.SplitHostPort(addr.String())
+		if err == nil {
+			attrs = append(attrs, attribute.String("net.peer.name", host))
+			if p, err := strconv.Atoi(port); err == nil {
+				attrs = append(attrs, attribute.Int("net.peer.port", p))
+			}
+		}
+	}
+	attrs = append(attrs, h.opt.Attributes...)
+	ctx, _ = h.tracer.Start(ctx, name, trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attrs...))
+	return ctx
+}
+
+func (h *hook) After(ctx context.Context, c *redis.Client, calls []redis.Call,
+	replies []*redis.Reply) {
+	span := trace.SpanFromContext(ctx)
+	for _, r := range replies {
+		if r.Type == redis.ErrorReply {
+			span.RecordError(r.Err)
+			span.SetStatus(codes.Error, r.Err.Error())
+			break
+		}
+	}
+	span.End()
+}
+
+// statement returns the given calls as the db.statement attribute, one per line.
+func (h *hook) statement(calls []redis.Call) string {
+	lines := make([]string, len(calls))
+	for i, call := range calls {
+		args := call.Strings()
+		redact := !h.opt.Args || sensitive[strings.ToLower(call.Cmd)]
+		for j, arg := range args {
+			switch {
+			case redact:
+				arg = "?"
+			case len(arg) > h.opt.MaxArgLen:
+				arg = arg[:h.opt.MaxArgLen] + "..."
+			}
+			args[j] = strings.ToValidUTF8(arg, "�")
+		}
+		lines[i] = strings.Join(append([]string{strings.ToLower(call.Cmd)}, args...), " ")
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,118 @@
+package redisotel
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/fzzy/radix/redis"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	. "launchpad.net/gocheck"
+)
+
+// hookup gocheck to `go test`
+func Test(t *testing.T) {
+	TestingT(t)
+}
+
+type HookSuite struct {
+	spans *tracetest.SpanRecorder
+	tp    *sdktrace.TracerProvider
+}
+
+var _ = Suite(&HookSuite{})
+
+func (s *HookSuite) SetUpTest(c *C) {
+	s.spans = tracetest.NewSpanRecorder()
+	s.tp = sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(s.spans))
+}
+
+// dial returns a client of a server replying with the given raw replies.
+func dial(c *C, replies string) *redis.Client {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	c.Assert(err, IsNil)
+	go func() {
+		defer l.Close()
+		conn, err := l.Accept()
+		if err != nil {
+			return
+		}
+		conn.Write([]byte(replies))
+		buf := make([]byte, 1024)
+		for {
+			if _, err := conn.Read(buf); err != nil {
+				return
+			}
+		}
+	}()
+	cl, err := redis.Dial("tcp", l.Addr().String())
+	c.Assert(err, IsNil)
+	return cl
+}
+
+func attrs(span sdktrace.ReadOnlySpan) map[attribute.Key]attribute.Value {
+	m := map[attribute.Key]attribute.Value{}
+	for _, kv := range span.Attributes() {
+		m[kv.Key] = kv.Value
+	}
+	return m
+}
+
+func (s *HookSuite) TestCmd(c *C) {
+	cl := dial(c, "+OK\r\n")
+	defer cl.Close()
+	cl.Hook = NewHook(Options{TracerProvider: s.tp})
+	ctx, parent := s.tp.Tracer("test").Start(context.Background(), "parent")
+	c.Check(cl.CmdContext(ctx, "SET", "key", "secret").Err, IsNil)
+	parent.End()
+
+	spans := s.spans.Ended()
+	c.Assert(spans, HasLen, 2)
+	span := spans[0]
+	c.Check(span.Name(), Equals, "set")
+	c.Check(span.SpanKind(), Equals, trace.SpanKindClient)
+	c.Check(span.Parent().SpanID(), Equals, parent.SpanContext().SpanID())
+	c.Check(span.Status().Code, Equals, codes.Unset)
+	m := attrs(span)
+	c.Check(m["db.system"].AsString(), Equals, "redis")
+	c.Check(m["db.operation"].AsString(), Equals, "SET")
+	c.Check(m["db.statement"].AsString(), Equals, "set ? ?")
+	c.Check(m["net.peer.name"].AsString(), Equals, "127.0.0.1")
+	c.Check(m["net.peer.port"].AsInt64() > 0, Equals, true)
+}
+
+func (s *HookSuite) TestArgs(c *C) {
+	cl := dial(c, "+OK\r\n+OK\r\n")
+	defer cl.Close()
+	cl.Hook = NewHook(Options{TracerProvider: s.tp, Args: true, MaxArgLen: 4})
+	cl.Cmd("set", "key", "value")
+	cl.Cmd("auth", "password")
+
+	spans := s.spans.Ended()
+	c.Assert(spans, HasLen, 2)
+	c.Check(attrs(spans[0])["db.statement"].AsString(), Equals, "set key valu...")
+	c.Check(attrs(spans[1])["db.statement"].AsString(), Equals, "auth ?")
+}
+
+func (s *HookSuite) TestPipeline(c *C) {
+	cl := dial(c, ":1\r\n-ERR wrong type\r\n")
+	defer cl.Close()
+	cl.Hook = NewHook(Options{TracerProvider: s.tp, Args: true})
+	cl.Append("incr", "a")
+	cl.Append("incr", "b")
+	cl.GetReply()
+	cl.GetReply()
+
+	spans := s.spans.Ended()
+	c.Assert(spans, HasLen, 1)
+	c.Check(spans[0].Name(), Equals, "pipeline")
+	m := attrs(spans[0])
+	c.Check(m["db.statement"].AsString(), Equals, "incr a\nincr b")
+	c.Check(m["db.redis.num_cmd"].AsInt64(), Equals, int64(2))
+	c.Check(spans[0].Status().Code, Equals, codes.Error)
+	c.Check(spans[0].Status().Description, Equals, "ERR wrong type")
+}
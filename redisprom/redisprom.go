@@ -0,0 +1,171 @@
+// Package redisprom exposes metrics of Radix clients to Prometheus.
+//
+// A Collector instruments one client, and is registered like any collector. Clients
+// registered with the same registry must be told apart with constant labels:
+//
+//	c, err := redis.Dial("tcp", "localhost:6379")
+//	col := redisprom.NewCollector(c, redisprom.Options{
+//		ConstLabels: prometheus.Labels{"server": "cache"},
+//	})
+//	prometheus.MustRegister(col)
+package redisprom
+
+import (
+	"context"
+	"io"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/fzzy/radix/redis"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Options configures NewCollector.
+type Options struct {
+	Namespace   string // Namespace of the metrics, "redis" if empty
+	Subsystem   string
+	ConstLabels prometheus.Labels
+	// Buckets of the latency histograms, in seconds, DefaultBuckets if nil.
+	Buckets []float64
+}
+
+// DefaultBuckets are the default buckets of the latency histograms, from 100µs to 1s.
+var DefaultBuckets = []float64{.0001, .00025, .0005, .001, .0025, .005, .01, .025, .05, .1,
+	.25, .5, 1}
+
+// Collector collects the metrics of a client:
+//
+//	redis_command_duration_seconds{cmd}  histogram of the latency of the commands,
+//	                                     cmd is "pipeline" for pipelines
+//	redis_errors_total{class}            errors, see ErrorClass
+//	redis_commands_in_flight             commands and pipelines waiting for replies
+//	redis_connects_total                 connections by UpdateConfiguration
+//	redis_connect_errors_total           failed connections by UpdateConfiguration
+type Collector struct {
+	duration      *prometheus.HistogramVec
+	errors        *prometheus.CounterVec
+	inFlight      prometheus.Gauge
+	connects      prometheus.Counter
+	connectErrors prometheus.Counter
+}
+
+// NewCollector returns a collector of the metrics of the given client. It adds itself
+// to the hook of the client, and wraps its OnConnect callback.
+func NewCollector(c *redis.Client, opt Options) *Collector {
+	if opt.Namespace == "" {
+		opt.Namespace = "redis"
+	}
+	if opt.Buckets == nil {
+		opt.Buckets = DefaultBuckets
+	}
+	opts := func(name, help string) prometheus.Opts {
+		return prometheus.Opts{Namespace: opt.Namespace, Subsystem: opt.Subsystem,
+			Name: name, Help: help, ConstLabels: opt.ConstLabels}
+	}
+	col := &Collector{
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: opt.Namespace, Subsystem: opt.Subsystem,
+			Name: "command_duration_seconds", Help: "Latency of the commands and pipelines.",
+			ConstLabels: opt.ConstLabels, Buckets: opt.Buckets,
+		}, []string{"cmd"}),
+		errors: prometheus.NewCounterVec(prometheus.CounterOpts(opts("errors_total",
+			"Errors by class.")), []string{"class"}),
+		inFlight: prometheus.NewGauge(prometheus.GaugeOpts(opts("commands_in_flight",
+			"Commands and pipelines waiting for replies."))),
+		connects: prometheus.NewCounter(prometheus.CounterOpts(opts("connects_total",
+			"Connections to a server."))),
+		connectErrors: prometheus.NewCounter(prometheus.CounterOpts(opts(
+			"connect_errors_total", "Failed connections to a server."))),
+	}
+
+	if c.Hook == nil {
+		c.Hook = col
+	} else {
+		c.Hook = redis.Hooks{c.Hook, col}
+	}
+	onConnect := c.OnConnect
+	c.OnConnect = func(addr string, err error) {
+		col.connects.Inc()
+		if err != nil {
+			col.connectErrors.Inc()
+			col.errors.WithLabelValues(ErrorClass(err)).Inc()
+		}
+		if onConnect != nil {
+			onConnect(addr, err)
+		}
+	}
+	return col
+}
+
+// Describe implements prometheus.Collector.
+func (col *Collector) Describe(ch chan<- *prometheus.Desc) {
+	col.duration.Describe(ch)
+	col.errors.Describe(ch)
+	col.inFlight.Describe(ch)
+	col.connects.Describe(ch)
+	col.connectErrors.Describe(ch)
+}
+
+// Collect implements prometheus.Collector.
+func (col *Collector) Collect(ch chan<- prometheus.Metric) {
+	col.duration.Collect(ch)
+	col.errors.Collect(ch)
+	col.inFlight.Collect(ch)
+	col.connects.Collect(ch)
+	col.connectErrors.Collect(ch)
+}
+
+type startKey struct{}
+
+// Before implements redis.Hook.
+func (col *Collector) Before(ctx context.Context, c *redis.Client,
+	calls []redis.Call) context.Context {
+	col.inFlight.Inc()
+	return context.WithValue(ctx, startKey{}, time.Now())
+}
+
+// After implements redis.Hook.
+func (col *Collector) After(ctx context.Context, c *redis.Client, calls []redis.Call,
+	replies []*redis.Reply) {
+	col.inFlight.Dec()
+	cmd := "pipeline"
+	if len(calls) == 1 {
+		cmd = strings.ToLower(calls[0].Cmd)
+	}
+	if start, ok := ctx.Value(startKey{}).(time.Time); ok {
+		col.duration.WithLabelValues(cmd).Observe(time.Since(start).Seconds())
+	}
+	for _, r := range replies {
+		if r.Type == redis.ErrorReply {
+			col.errors.WithLabelValues(ErrorClass(r.Err)).Inc()
+		}
+	}
+}
+
+// ErrorClass returns the class of the given error: "timeout" and "connection" for
+// network errors, the error code for error replies, such as "ERR", "WRONGTYPE" or
+// "MOVED", and "client" for the errors of the client, such as redis.ParseError.
+func ErrorClass(err error) string {
+	if ne, ok := err.(net.Error); ok {
+		if ne.Timeout() {
+			return "timeout"
+		}
+		return "connection"
+	}
+	if err == io.EOF || err == io.ErrUnexpectedEOF {
+		return "connection"
+	}
+	if err == redis.LoadingError {
+		return "LOADING"
+	}
+	msg := err.Error()
+	code := msg
+	if i := strings.IndexByte(msg, ' '); i >= 0 {
+		code = msg[:i]
+	}
+	if code == "" || strings.ToUpper(code) != code {
+		return "client"
+	}
+	return code
+}
@@ -0,0 +1,126 @@
+package redisprom
+
+import (
+	"errors"
+	"io"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/fzzy/radix/redis"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	. "launchpad.net/gocheck"
+)
+
+// hookup gocheck to `go test`
+func Test(t *testing.T) {
+	TestingT(t)
+}
+
+type CollectorSuite struct{}
+
+var _ = Suite(&CollectorSuite{})
+
+// serve starts a server replying to its first connection with the given raw replies
+// and returns its address.
+func serve(c *C, replies string) string {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	c.Assert(err, IsNil)
+	go func() {
+		defer l.Close()
+		conn, err := l.Accept()
+		if err != nil {
+			return
+		}
+		conn.Write([]byte(replies))
+		buf := make([]byte, 1024)
+		for {
+			if _, err := conn.Read(buf); err != nil {
+				return
+			}
+		}
+	}()
+	return l.Addr().String()
+}
+
+func (s *CollectorSuite) TestCommands(c *C) {
+	cl, err := redis.Dial("tcp", serve(c, "+OK\r\n-WRONGTYPE bad\r\n:1\r\n-ERR x\r\n"))
+	c.Assert(err, IsNil)
+	defer cl.Close()
+	col := NewCollector(cl, Options{Namespace: "cache"})
+	reg := prometheus.NewPedanticRegistry()
+	c.Assert(reg.Register(col), IsNil)
+
+	cl.Cmd("SET", "k", "v")
+	cl.Cmd("lpush", "k", "v")
+	cl.Append("incr", "n")
+	cl.Append("incr", "k")
+	cl.GetReply()
+
+	n, err := testutil.GatherAndCount(reg, "cache_command_duration_seconds")
+	c.Assert(err, IsNil)
+	c.Check(n, Equals, 3) // set, lpush and pipeline
+	c.Check(testutil.ToFloat64(col.errors.WithLabelValues("WRONGTYPE")), Equals, 1.0)
+	c.Check(testutil.ToFloat64(col.errors.WithLabelValues("ERR")), Equals, 1.0)
+	c.Check(testutil.ToFloat64(col.inFlight), Equals, 0.0)
+
+	expected := `
+# HELP cache_errors_total Errors by class.
+# TYPE cache_errors_total counter
+cache_errors_total{class="ERR"} 1
+cache_errors_total{class="WRONGTYPE"} 1
+`
+	err = testutil.GatherAndCompare(reg, strings.NewReader(expected), "cache_errors_total")
+	c.Check(err, IsNil)
+}
+
+func (s *CollectorSuite) TestConnects(c *C) {
+	cl, err := redis.Dial("tcp", serve(c, ""))
+	c.Assert(err, IsNil)
+	defer cl.Close()
+	var called int
+	cl.OnConnect = func(addr string, err error) { called++ }
+	col := NewCollector(cl, Options{})
+
+	c.Check(cl.UpdateConfiguration("tcp", serve(c, ""), ""), IsNil)
+	c.Check(cl.UpdateConfiguration("tcp", "127.0.0.1:1", ""), NotNil)
+	c.Check(testutil.ToFloat64(col.connects), Equals, 2.0)
+	c.Check(testutil.ToFloat64(col.connectErrors), Equals, 1.0)
+	c.Check(testutil.ToFloat64(col.errors.WithLabelValues("connection")), Equals, 1.0)
+	c.Check(called, Equals, 2)
+}
+
+func (s *CollectorSuite) TestHooks(c *C) {
+	cl, err := redis.Dial("tcp", serve(c, "+OK\r\n"))
+	c.Assert(err, IsNil)
+	defer cl.Close()
+	first := NewCollector(cl, Options{})
+	second := NewCollector(cl, Options{})
+	c.Check(cl.Hook, FitsTypeOf, redis.Hooks{})
+	cl.Cmd("ping")
+	c.Check(testutil.CollectAndCount(first, "redis_command_duration_seconds"), Equals, 1)
+	c.Check(testutil.CollectAndCount(second, "redis_command_duration_seconds"), Equals, 1)
+}
+
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "i/o timeout" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
+
+func (s *CollectorSuite) TestErrorClass(c *C) {
+	for err, class := range map[error]string{
+		timeoutError{}:                               "timeout",
+		io.EOF:                                       "connection",
+		redis.LoadingError:                           "LOADING",
+		redis.ParseError:                             "client",
+		errors.New("ERR unknown command 'foo'"):      "ERR",
+		errors.New("MOVED 3999 127.0.0.1:6381"):      "MOVED",
+		errors.New("NOSCRIPT No matching script"):    "NOSCRIPT",
+		&redis.ArityError{Cmd: "get", Arity: 2}:      "client",
+		&redis.ScriptBusyError{Msg: "BUSY Redis is"}: "BUSY",
+	} {
+		c.Check(ErrorClass(err), Equals, class)
+	}
+}